@@ -1,25 +1,36 @@
 package auth
 
 import (
-	"log"
 	"net/http"
+	"slices"
 	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/mjmarrazzo/maintenance-app/components/auth_views"
+	"github.com/mjmarrazzo/maintenance-app/components/common"
 	"github.com/mjmarrazzo/maintenance-app/domain"
 	"github.com/mjmarrazzo/maintenance-app/internal/api"
+	"github.com/mjmarrazzo/maintenance-app/internal/i18n"
+	"github.com/mjmarrazzo/maintenance-app/internal/logging"
+	"github.com/mjmarrazzo/maintenance-app/internal/responses"
 )
 
 type AuthContext struct {
 	User      *domain.User
 	ExpiresAt int64
+	// Session is set when the request authenticated with the session cookie,
+	// Token when it used an API token instead.
+	Session *domain.Session
+	Token   *domain.APIToken
 }
 
 func AuthenticatedMiddleware() echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
 			authCtx, err := GetAuthContext(c)
+			if appErr, ok := responses.IsAppError(err); ok && appErr.Kind == responses.KindInternal {
+				return err
+			}
 			if err != nil {
 				return handleUnauthorized(c, err)
 			}
@@ -29,34 +40,78 @@ func AuthenticatedMiddleware() echo.MiddlewareFunc {
 				return handleUnauthorized(c, err)
 			}
 
+			req := c.Request()
+			logger := logging.FromContext(req.Context()).With("user_id", authCtx.User.ID)
+			if authCtx.Token != nil {
+				logger = logger.With("token_id", authCtx.Token.ID)
+			}
+			ctx := logging.WithLogger(req.Context(), logger)
+			ctx = common.WithCurrentUser(ctx, authCtx.User)
+			if authCtx.User.Locale.Valid {
+				ctx = i18n.WithLocale(ctx, authCtx.User.Locale.String)
+			}
+			if zone := i18n.LoadTimeZone(authCtx.User.TimeZone.String); zone != nil {
+				ctx = i18n.WithTimeZone(ctx, zone)
+			}
+			c.SetRequest(req.WithContext(ctx))
+
 			return next(c)
 		}
 	}
 }
 
-func handleUnauthorized(c echo.Context, err error) error {
-	log.Printf("Unauthorized access: %v\n", err)
+// RequireRole rejects users without one of roles. It must run after
+// AuthenticatedMiddleware.
+func RequireRole(roles ...domain.UserRole) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			authCtx, err := GetAuthContext(c)
+			if err != nil {
+				return handleUnauthorized(c, err)
+			}
+
+			if !slices.Contains(roles, authCtx.User.Role) {
+				logging.FromContext(c.Request().Context()).Warn("forbidden access", "path", c.Request().URL.Path)
+				return responses.NewForbiddenError("You do not have permission to do this")
+			}
 
+			return next(c)
+		}
+	}
+}
+
+func handleUnauthorized(c echo.Context, err error) error {
 	originalUrl := c.Request().URL.String()
-	log.Printf("Original URL: %s\n", originalUrl)
+	logging.FromContext(c.Request().Context()).Info("unauthorized access",
+		"path", c.Request().URL.Path,
+		"error", err,
+	)
+
+	if !api.IsHtmxRequest(c) && !api.WantsHTML(c) {
+		return responses.NewUnauthorizedError("Authentication required")
+	}
+
 	login := auth_views.Login(auth_views.LoginProps{
 		OriginalUrl: originalUrl,
+		Providers:   LoginProviders(),
 	})
 	return api.Render(c, http.StatusUnauthorized, login)
 }
 
 func GetAuthContext(c echo.Context) (*AuthContext, error) {
-	user, err := getUserFromSession(c)
-	if err != nil {
-		return nil, err
+	if authCtx, ok := c.Get(authContextKey).(*AuthContext); ok {
+		return authCtx, nil
 	}
-	expiration, err := getExpirationTime(c)
+
+	user, sess, err := getUserFromSession(c)
 	if err != nil {
 		return nil, err
 	}
 	authContext := &AuthContext{
 		User:      user,
-		ExpiresAt: expiration,
+		ExpiresAt: sess.ExpiresAt.Unix(),
+		Session:   sess,
 	}
+	c.Set(authContextKey, authContext)
 	return authContext, nil
 }
@@ -1,134 +1,189 @@
 package auth
 
 import (
+	"context"
 	"errors"
 	"net/http"
-	"strconv"
 	"time"
 
 	"github.com/gorilla/sessions"
 	"github.com/labstack/echo-contrib/session"
 	"github.com/labstack/echo/v4"
 	"github.com/mjmarrazzo/maintenance-app/domain"
+	"github.com/mjmarrazzo/maintenance-app/internal/api"
 )
 
 type SessionKey string
 
 const (
-	sessionKey = SessionKey("user-session")
+	sessionKey           = SessionKey("user-session")
+	sessionTokenValue    = "Token"
+	sessionManagerKey    = "session_manager"
+	pendingUserValue     = "PendingUserID"
+	pendingSinceValue    = "PendingSince"
+	pendingRedirectValue = "PendingRedirect"
 )
 
-func getUserFromSession(c echo.Context) (*domain.User, error) {
-	sess, err := session.Get(string(sessionKey), c)
-	if err != nil {
-		return nil, err
-	}
+// PendingLoginTimeout is how long a user has to complete the second login
+// step after entering their password.
+const PendingLoginTimeout = 5 * time.Minute
 
-	userID, ok := parseInt(sess.Values["ID"])
-	if !ok {
-		return nil, errors.New("user ID not found in session")
-	}
-	firstName, ok := parseString(sess.Values["FirstName"])
-	if !ok {
-		return nil, errors.New("first name not found in session")
-	}
-	lastName, ok := parseString(sess.Values["LastName"])
-	if !ok {
-		return nil, errors.New("last name not found in session")
-	}
-	email, ok := parseString(sess.Values["Email"])
-	if !ok {
-		return nil, errors.New("email not found in session")
+// SessionManager persists sessions server side. The cookie only carries the
+// token handed out by Create.
+type SessionManager interface {
+	Create(ctx context.Context, userID int64, userAgent, ipAddress string) (*domain.Session, string, error)
+	Authenticate(ctx context.Context, token string) (*domain.User, *domain.Session, error)
+	RevokeToken(ctx context.Context, token string) error
+}
+
+// SessionMiddleware makes sessions available to the helpers in this package.
+// Sessions are only looked up when a handler asks for the auth context.
+func SessionMiddleware(manager SessionManager) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Set(sessionManagerKey, manager)
+			return next(c)
+		}
 	}
+}
 
-	role, ok := sess.Values["Role"].(string)
+func getSessionManager(c echo.Context) (SessionManager, error) {
+	manager, ok := c.Get(sessionManagerKey).(SessionManager)
 	if !ok {
-		return nil, errors.New("role not found in session")
-	}
-	user := &domain.User{
-		ID:        userID,
-		FirstName: firstName,
-		LastName:  lastName,
-		Email:     email,
-		Role:      domain.UserRole(role),
+		return nil, errors.New("session middleware not configured")
 	}
-
-	return user, nil
+	return manager, nil
 }
 
-func parseString(value any) (string, bool) {
-	if value == nil {
-		return "", false
+func getSessionToken(c echo.Context) (string, error) {
+	sess, err := session.Get(string(sessionKey), c)
+	if err != nil {
+		return "", err
 	}
-	str, ok := value.(string)
-	if !ok {
-		return "", false
+
+	token, ok := sess.Values[sessionTokenValue].(string)
+	if !ok || token == "" {
+		return "", errors.New("session token not found in cookie")
 	}
-	return str, true
+	return token, nil
 }
 
-func parseInt(value any) (int64, bool) {
-	if value == nil {
-		return 0, false
-	}
-	str, ok := value.(string)
-	if !ok {
-		return 0, false
+// getUserFromSession resolves the session cookie against the sessions table.
+// The user is loaded from the database on every lookup so role changes and
+// revocations take effect on the next request.
+func getUserFromSession(c echo.Context) (*domain.User, *domain.Session, error) {
+	manager, err := getSessionManager(c)
+	if err != nil {
+		return nil, nil, err
 	}
-	intValue, err := strconv.ParseInt(str, 10, 64)
+
+	token, err := getSessionToken(c)
 	if err != nil {
-		return 0, false
+		return nil, nil, err
 	}
-	return intValue, true
+
+	return manager.Authenticate(c.Request().Context(), token)
 }
 
-func getExpirationTime(c echo.Context) (int64, error) {
+func SaveUserToSession(c echo.Context, user *domain.User) error {
+	manager, err := getSessionManager(c)
+	if err != nil {
+		return err
+	}
+
 	s, err := session.Get(string(sessionKey), c)
 	if err != nil {
-		return 0, err
+		return err
 	}
 
-	expiresAt, ok := parseInt(s.Values["ExpiresAt"])
-	if !ok {
-		return 0, errors.New("expiresAt not found in session")
+	ctx := c.Request().Context()
+	if previous, ok := s.Values[sessionTokenValue].(string); ok && previous != "" {
+		if err := manager.RevokeToken(ctx, previous); err != nil {
+			return err
+		}
 	}
 
-	return expiresAt, nil
+	_, token, err := manager.Create(ctx, user.ID, c.Request().UserAgent(), c.RealIP())
+	if err != nil {
+		return err
+	}
+
+	s.Options = cookieOptions()
+	s.Values = map[any]any{sessionTokenValue: token}
+
+	return s.Save(c.Request(), c.Response())
 }
 
-func SaveUserToSession(c echo.Context, user *domain.User) error {
+func ClearSession(c echo.Context) error {
 	s, err := session.Get(string(sessionKey), c)
 	if err != nil {
 		return err
 	}
 
-	s.Options = &sessions.Options{
+	if token, ok := s.Values[sessionTokenValue].(string); ok && token != "" {
+		manager, err := getSessionManager(c)
+		if err != nil {
+			return err
+		}
+		if err := manager.RevokeToken(c.Request().Context(), token); err != nil {
+			return err
+		}
+	}
+
+	s.Values = make(map[any]any)
+	s.Options.MaxAge = -1
+	return s.Save(c.Request(), c.Response())
+}
+
+func cookieOptions() *sessions.Options {
+	return &sessions.Options{
 		Path:     "/",
-		MaxAge:   86400 * 7,
+		MaxAge:   int(domain.SessionLifetime.Seconds()),
 		HttpOnly: true,
-		Secure:   true,
+		Secure:   api.SecureCookies(),
 		SameSite: http.SameSiteStrictMode,
 	}
+}
+
+// PendingLogin is a user that passed the password check but still has to
+// provide a second factor before SaveUserToSession is called.
+type PendingLogin struct {
+	UserID   int64
+	Redirect string
+}
 
-	s.Values["ID"] = strconv.FormatInt(user.ID, 10)
-	s.Values["FirstName"] = user.FirstName
-	s.Values["LastName"] = user.LastName
-	s.Values["Email"] = user.Email
-	s.Values["Role"] = string(user.Role)
+// SavePendingLogin remembers userID in the cookie until the second login
+// step completes or PendingLoginTimeout passes.
+func SavePendingLogin(c echo.Context, userID int64, redirect string) error {
+	s, err := session.Get(string(sessionKey), c)
+	if err != nil {
+		return err
+	}
 
-	expirationTime := time.Now().Add(time.Hour * 2).Unix()
-	s.Values["ExpiresAt"] = strconv.FormatInt(expirationTime, 10)
+	s.Options = cookieOptions()
+	s.Values[pendingUserValue] = userID
+	s.Values[pendingSinceValue] = time.Now().Unix()
+	s.Values[pendingRedirectValue] = redirect
 
 	return s.Save(c.Request(), c.Response())
 }
 
-func ClearSession(c echo.Context) error {
+func GetPendingLogin(c echo.Context) (*PendingLogin, error) {
 	s, err := session.Get(string(sessionKey), c)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	s.Values = make(map[any]any)
-	s.Options.MaxAge = -1
-	return s.Save(c.Request(), c.Response())
+	userID, ok := s.Values[pendingUserValue].(int64)
+	if !ok {
+		return nil, errors.New("no pending login")
+	}
+
+	since, _ := s.Values[pendingSinceValue].(int64)
+	if time.Since(time.Unix(since, 0)) > PendingLoginTimeout {
+		return nil, errors.New("pending login expired")
+	}
+
+	redirect, _ := s.Values[pendingRedirectValue].(string)
+	return &PendingLogin{UserID: userID, Redirect: redirect}, nil
 }
@@ -0,0 +1,22 @@
+package auth
+
+import (
+	"github.com/mjmarrazzo/maintenance-app/components/auth_views"
+	"github.com/mjmarrazzo/maintenance-app/internal/sso"
+)
+
+// LoginProviders lists the configured single sign-on providers for the
+// login page. Configuration errors are reported at startup, so a broken
+// configuration simply offers no providers here.
+func LoginProviders() []auth_views.LoginProvider {
+	registry, err := sso.Default()
+	if err != nil {
+		return nil
+	}
+
+	var providers []auth_views.LoginProvider
+	for _, p := range registry.Providers() {
+		providers = append(providers, auth_views.LoginProvider{Name: p.Name, DisplayName: p.DisplayName})
+	}
+	return providers
+}
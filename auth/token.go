@@ -0,0 +1,71 @@
+package auth
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/mjmarrazzo/maintenance-app/domain"
+	"github.com/mjmarrazzo/maintenance-app/internal/responses"
+)
+
+const authContextKey = "auth_context"
+
+type TokenAuthenticator interface {
+	Authenticate(ctx context.Context, token string) (*domain.User, *domain.APIToken, error)
+}
+
+// BearerTokenMiddleware authenticates requests carrying an
+// "Authorization: Bearer" header against tokens and stores the resulting
+// AuthContext on the echo context. Requests without the header fall through
+// to the session, so browsers keep working on the same routes.
+func BearerTokenMiddleware(tokens TokenAuthenticator) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			header := c.Request().Header.Get(echo.HeaderAuthorization)
+			if header == "" {
+				return next(c)
+			}
+
+			c.Response().Header().Set(echo.HeaderWWWAuthenticate, `Bearer realm="api"`)
+
+			scheme, value, ok := strings.Cut(header, " ")
+			if !ok || !strings.EqualFold(scheme, "Bearer") {
+				return responses.NewUnauthorizedError("Unsupported authorization scheme")
+			}
+
+			user, token, err := tokens.Authenticate(c.Request().Context(), strings.TrimSpace(value))
+			if err != nil {
+				return err
+			}
+
+			scope := requiredScope(c.Request().Method)
+			if !token.HasScope(scope) {
+				return responses.NewForbiddenError("Token is missing the '" + string(scope) + "' scope")
+			}
+
+			expiresAt := int64(math.MaxInt64)
+			if token.ExpiresAt.Valid {
+				expiresAt = token.ExpiresAt.Time.Unix()
+			}
+
+			c.Set(authContextKey, &AuthContext{
+				User:      user,
+				ExpiresAt: expiresAt,
+				Token:     token,
+			})
+			return next(c)
+		}
+	}
+}
+
+func requiredScope(method string) domain.TokenScope {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return domain.ScopeRead
+	default:
+		return domain.ScopeWrite
+	}
+}
@@ -11,10 +11,12 @@ import templruntime "github.com/a-h/templ/runtime"
 import (
 	"github.com/mjmarrazzo/maintenance-app/components/common"
 	"github.com/mjmarrazzo/maintenance-app/components/common/form"
+	"github.com/mjmarrazzo/maintenance-app/internal/i18n"
 )
 
 type RegisterProps struct {
-	Email string
+	Email           string
+	InvitationToken string
 }
 
 func Register(props RegisterProps) templ.Component {
@@ -50,10 +52,42 @@ func Register(props RegisterProps) templ.Component {
 				}()
 			}
 			ctx = templ.InitializeContext(ctx)
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<div class=\"hero min-h-screen\" style=\"background-image: url(/public/illustrations/hero.png);\"><div class=\"hero-overlay bg-opacity-60\"></div><div class=\"flex justify-center items-center\"><div id=\"registration-card\" class=\"card backdrop-blur-lg bg-white/20 shadow-xl \"><div class=\"card-body\"><h2 class=\"text-3xl font-bold text-white text-center\">Welcome to Groundwork!</h2><form id=\"registration-form\" class=\"flex flex-col gap-4 p-4\" hx-post=\"/register\" hx-indicator=\"#form-spinner\" hx-disabled-elt=\"button[type=submit]\">")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<div class=\"hero min-h-screen\" style=\"background-image: url(/public/illustrations/hero.png);\"><div class=\"hero-overlay bg-opacity-60\"></div><div class=\"flex justify-center items-center\"><div id=\"registration-card\" class=\"card backdrop-blur-lg bg-white/20 shadow-xl \"><div class=\"card-body\"><h2 class=\"text-3xl font-bold text-white text-center\">")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
+			var templ_7745c5c3_Var3 string
+			templ_7745c5c3_Var3, templ_7745c5c3_Err = templ.JoinStringErrs(i18n.T(ctx, "Welcome to Groundwork!"))
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/auth_views/register.templ`, Line: 24, Col: 99}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var3))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 2, "</h2><form id=\"registration-form\" class=\"flex flex-col gap-4 p-4\" hx-post=\"/register\" hx-target=\"#registration-card\" hx-swap=\"innerHTML\" hx-indicator=\"#form-spinner\" hx-disabled-elt=\"button[type=submit]\">")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			if props.InvitationToken != "" {
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 3, "<input type=\"hidden\" name=\"invitation_token\" value=\"")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				var templ_7745c5c3_Var4 string
+				templ_7745c5c3_Var4, templ_7745c5c3_Err = templ.JoinStringErrs(props.InvitationToken)
+				if templ_7745c5c3_Err != nil {
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/auth_views/register.templ`, Line: 35, Col: 82}
+				}
+				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var4))
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 4, "\">")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+			}
 			templ_7745c5c3_Err = form.Input(form.InputProps{
 				ID:           "email",
 				Label:        "Email",
@@ -69,8 +103,8 @@ func Register(props RegisterProps) templ.Component {
 			templ_7745c5c3_Err = form.Password(form.PasswordProps{
 				ID:           "password",
 				Label:        "Password",
-				Autocomplete: form.AutocompleteCurrentPassword,
-				Hint:         "Required",
+				Autocomplete: form.AutocompleteNewPassword,
+				Hint:         "At least 8 characters",
 			}).Render(ctx, templ_7745c5c3_Buffer)
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
@@ -78,7 +112,7 @@ func Register(props RegisterProps) templ.Component {
 			templ_7745c5c3_Err = form.Password(form.PasswordProps{
 				ID:           "password-confirmation",
 				Label:        "Confirm Password",
-				Autocomplete: form.AutocompleteCurrentPassword,
+				Autocomplete: form.AutocompleteNewPassword,
 				Hint:         "Passwords must match",
 			}).Render(ctx, templ_7745c5c3_Buffer)
 			if templ_7745c5c3_Err != nil {
@@ -106,7 +140,46 @@ func Register(props RegisterProps) templ.Component {
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 2, "<button class=\"btn btn-primary btn-block mt-2 shadow-md\" type=\"submit\">Login</button></form></div></div></div></div><script>\n            // Function to check passwords match on dirty of password confirmation\n            const passwordInput = document.getElementById('password');\n            const passwordConfirmationInput = document.getElementById('password-confirmation');\n            const passwordHint = passwordConfirmationInput.nextElementSibling;\n            passwordConfirmationInput.addEventListener('input', function(event) {\n                if (passwordInput.value !== passwordConfirmationInput.value) {\n                    passwordConfirmationInput.setCustomValidity(\"Passwords do not match\");\n                } else {\n                    passwordConfirmationInput.setCustomValidity(\"\");\n                }\n            });\n\n        </script>")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 5, "<button class=\"btn btn-primary btn-block mt-2 shadow-md\" type=\"submit\">")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var5 string
+			templ_7745c5c3_Var5, templ_7745c5c3_Err = templ.JoinStringErrs(i18n.T(ctx, "Register"))
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/auth_views/register.templ`, Line: 74, Col: 103}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var5))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 6, "</button></form></div></div></div></div><script nonce=\"")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var6 string
+			templ_7745c5c3_Var6, templ_7745c5c3_Err = templ.JoinStringErrs(templ.GetNonce(ctx))
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/auth_views/register.templ`, Line: 80, Col: 37}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var6))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 7, "\" data-mismatch=\"")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var7 string
+			templ_7745c5c3_Var7, templ_7745c5c3_Err = templ.JoinStringErrs(i18n.T(ctx, "Passwords do not match"))
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/auth_views/register.templ`, Line: 80, Col: 93}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var7))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 8, "\">\n            // Function to check passwords match on dirty of password confirmation\n            const passwordInput = document.getElementById('password');\n            const passwordConfirmationInput = document.getElementById('password-confirmation');\n            const passwordHint = passwordConfirmationInput.nextElementSibling;\n            const mismatchMessage = document.currentScript.dataset.mismatch;\n            passwordConfirmationInput.addEventListener('input', function(event) {\n                if (passwordInput.value !== passwordConfirmationInput.value) {\n                    passwordConfirmationInput.setCustomValidity(mismatchMessage);\n                } else {\n                    passwordConfirmationInput.setCustomValidity(\"\");\n                }\n            });\n\n        </script>")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
@@ -8,6 +8,12 @@ package common
 import "github.com/a-h/templ"
 import templruntime "github.com/a-h/templ/runtime"
 
+import (
+	"context"
+	"encoding/json"
+	"github.com/mjmarrazzo/maintenance-app/internal/i18n"
+)
+
 func BaseHtml(title string) templ.Component {
 	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
@@ -29,20 +35,59 @@ func BaseHtml(title string) templ.Component {
 			templ_7745c5c3_Var1 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<!doctype html><html class=\"h-full\"><head><link rel=\"icon\" href=\"/public/favicon.ico\" type=\"image/x-icon\"><meta name=\"viewport\" content=\"width=device-width, initial-scale=1.0\"><title>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<!doctype html><html class=\"h-full\" lang=\"")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
 		var templ_7745c5c3_Var2 string
-		templ_7745c5c3_Var2, templ_7745c5c3_Err = templ.JoinStringErrs(title)
+		templ_7745c5c3_Var2, templ_7745c5c3_Err = templ.JoinStringErrs(i18n.Locale(ctx))
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/common/base_html.templ`, Line: 9, Col: 17}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/common/base_html.templ`, Line: 11, Col: 45}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var2))
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 2, "</title><script src=\"/public/htmx.min.js\"></script><link href=\"/public/tailwind.css\" rel=\"stylesheet\"><script src=\"https://unpkg.com/lucide@0.488.0\"></script><style>\n                label:has(+ input:required):after {\n                    content: ' *';\n                    color: red;\n                }\n                label:has(+ * input:required):after {\n                    content: ' *';\n                    color: red;\n                }\n                .htmx-indicator:not(.htmx-request) {\n\t\t\t\t\tdisplay: none;\n\t\t\t\t}\n                .hero .card form label {\n                    color: white;\n                }\n\t\t\t</style></head><body class=\"h-full\">")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 2, "\"><head><link rel=\"icon\" href=\"/public/favicon.ico\" type=\"image/x-icon\"><meta name=\"viewport\" content=\"width=device-width, initial-scale=1.0\"><title>")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var3 string
+		templ_7745c5c3_Var3, templ_7745c5c3_Err = templ.JoinStringErrs(i18n.T(ctx, title))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/common/base_html.templ`, Line: 15, Col: 30}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var3))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 3, "</title><meta name=\"htmx-config\" content=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var4 string
+		templ_7745c5c3_Var4, templ_7745c5c3_Err = templ.JoinStringErrs(htmxConfig(ctx))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/common/base_html.templ`, Line: 16, Col: 53}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var4))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 4, "\"><script src=\"/public/htmx.min.js\"></script><script src=\"/public/htmx-ext-sse.js\"></script><link href=\"/public/tailwind.css\" rel=\"stylesheet\"><script src=\"/public/lucide.min.js\"></script><style>\n                label:has(+ input:required):after {\n                    content: ' *';\n                    color: red;\n                }\n                label:has(+ * input:required):after {\n                    content: ' *';\n                    color: red;\n                }\n                .htmx-indicator:not(.htmx-request) {\n\t\t\t\t\tdisplay: none;\n\t\t\t\t}\n                .hero .card form label {\n                    color: white;\n                }\n                .list-empty:not(:only-child) {\n                    display: none;\n                }\n                [data-drop-name].drop-over {\n                    outline: 2px dashed var(--color-primary);\n                    outline-offset: -2px;\n                }\n\t\t\t</style></head><body class=\"h-full\" hx-headers=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var5 string
+		templ_7745c5c3_Var5, templ_7745c5c3_Err = templ.JoinStringErrs(csrfHeaders(ctx))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/common/base_html.templ`, Line: 45, Col: 52}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var5))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 5, "\">")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -50,7 +95,20 @@ func BaseHtml(title string) templ.Component {
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 3, "<script>\n                lucide.createIcons();\n            </script></body></html>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 6, "<div id=\"toast\" class=\"toast\"></div><script nonce=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var6 string
+		templ_7745c5c3_Var6, templ_7745c5c3_Err = templ.JoinStringErrs(templ.GetNonce(ctx))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/common/base_html.templ`, Line: 48, Col: 38}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var6))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 7, "\">\n                window.lucide?.createIcons();\n\n                function showToast(message, type) {\n                    const toast = document.getElementById('toast');\n                    const toastItem = document.createElement('div');\n                    toastItem.className = `alert alert-${type} shadow-lg`;\n                    toastItem.appendChild(document.createTextNode(message));\n                    toast.appendChild(toastItem);\n                    setTimeout(() => {\n                        toastItem.remove();\n                    }, 30000);\n                }\n\n                document.body.addEventListener('showToast', function(event) {\n                    showToast(event.detail.message, event.detail.type);\n                });\n\n                function togglePasswordVisibility(id) {\n                    const input = document.getElementById(id);\n                    const eyeIcon = document.getElementById('eye-' + id);\n                    const eyeOffIcon = document.getElementById('eye-off-' + id);\n\n                    if (input.type === \"password\") {\n                        input.type = \"text\";\n                        eyeIcon.classList.add(\"hidden\");\n                        eyeOffIcon.classList.remove(\"hidden\");\n                    } else {\n                        input.type = \"password\";\n                        eyeIcon.classList.remove(\"hidden\");\n                        eyeOffIcon.classList.add(\"hidden\");\n                    }\n                }\n\n                // Inline event handlers are blocked by the Content-Security-Policy,\n                // so elements opt into behaviour with data attributes instead.\n                document.addEventListener('click', function(event) {\n                    const opener = event.target.closest('[data-show-modal]');\n                    if (opener) {\n                        document.getElementById(opener.dataset.showModal)?.showModal();\n                    }\n\n                    const closer = event.target.closest('[data-close-modal]');\n                    if (closer) {\n                        document.getElementById(closer.dataset.closeModal)?.close();\n                    }\n\n                    const passwordToggle = event.target.closest('[data-toggle-password]');\n                    if (passwordToggle) {\n                        togglePasswordVisibility(passwordToggle.dataset.togglePassword);\n                    }\n                });\n\n                document.addEventListener('change', function(event) {\n                    const toggle = event.target.closest('[data-toggle-hidden]');\n                    if (toggle) {\n                        document.getElementById(toggle.dataset.toggleHidden)?.classList.toggle('hidden');\n                    }\n                });\n\n                document.body.addEventListener('htmx:afterSwap', function() {\n                    window.lucide?.createIcons();\n                });\n\n                document.body.addEventListener('htmx:sseMessage', function() {\n                    window.lucide?.createIcons();\n                });\n\n                document.body.addEventListener('closeModal', function(event) {\n                    document.getElementById(event.detail.value)?.close();\n                });\n\n                // A new row reaches the page both in the response to the request\n                // that created it and over the event stream. Lists marked with\n                // data-unique-rows drop whichever copy arrives second.\n                document.body.addEventListener('htmx:oobBeforeSwap', function(event) {\n                    const id = event.detail.fragment.firstElementChild?.id;\n                    if (event.detail.target.hasAttribute('data-unique-rows') && id && document.getElementById(id)) {\n                        event.detail.shouldSwap = false;\n                    }\n                });\n\n                document.body.addEventListener('htmx:sseBeforeMessage', function(event) {\n                    const id = event.detail.lastEventId;\n                    if (event.target.hasAttribute('data-unique-rows') && id && document.getElementById(id)) {\n                        event.preventDefault();\n                    }\n                });\n\n                // htmx does not swap error responses. Forms marked with\n                // data-swap-on-conflict show the 409 they get when the record\n                // was saved by someone else in the meantime.\n                document.body.addEventListener('htmx:beforeSwap', function(event) {\n                    if (event.detail.xhr.status === 409 && event.detail.elt.hasAttribute('data-swap-on-conflict')) {\n                        event.detail.shouldSwap = true;\n                        event.detail.isError = false;\n                    }\n                });\n\n                // Elements with data-drag-url can be dragged onto elements with\n                // data-drop-name. The drop sends the data-drop-value of the drop\n                // zone under that name to the url, with data-drag-method or PATCH.\n                // The request takes its target, swap and included values from the\n                // drop zone like any htmx request.\n                let dragged = null;\n\n                document.addEventListener('dragstart', function(event) {\n                    dragged = event.target.closest?.('[data-drag-url]') ?? null;\n                    if (dragged) {\n                        event.dataTransfer.effectAllowed = 'move';\n                        event.dataTransfer.setData('text/plain', dragged.dataset.dragUrl);\n                    }\n                });\n\n                document.addEventListener('dragend', function() {\n                    dragged = null;\n                    document.querySelectorAll('.drop-over').forEach(zone => zone.classList.remove('drop-over'));\n                });\n\n                document.addEventListener('dragover', function(event) {\n                    const zone = event.target.closest?.('[data-drop-name]');\n                    if (dragged && zone) {\n                        event.preventDefault();\n                        zone.classList.add('drop-over');\n                    }\n                });\n\n                document.addEventListener('dragleave', function(event) {\n                    const zone = event.target.closest?.('[data-drop-name]');\n                    if (zone && !zone.contains(event.relatedTarget)) {\n                        zone.classList.remove('drop-over');\n                    }\n                });\n\n                document.addEventListener('drop', function(event) {\n                    const zone = event.target.closest?.('[data-drop-name]');\n                    if (!dragged || !zone) {\n                        return;\n                    }\n                    event.preventDefault();\n                    zone.classList.remove('drop-over');\n                    if (zone.contains(dragged)) {\n                        return;\n                    }\n                    htmx.ajax(dragged.dataset.dragMethod || 'PATCH', dragged.dataset.dragUrl, {\n                        source: zone,\n                        values: {[zone.dataset.dropName]: zone.dataset.dropValue},\n                    });\n                });\n\n                document.body.addEventListener('htmx:afterRequest', function(event) {\n                    const elt = event.detail.elt;\n                    if (event.detail.failed && elt.dataset.showModal) {\n                        document.getElementById(elt.dataset.showModal)?.close();\n                    }\n                    if (event.detail.successful && elt.hasAttribute('data-reset-on-success')) {\n                        elt.reset();\n                    }\n                });\n            </script></body></html>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -58,4 +116,21 @@ func BaseHtml(title string) templ.Component {
 	})
 }
 
+// htmxConfig disables htmx features that evaluate strings as code and has
+// scripts in swapped content run with the nonce of the page.
+func htmxConfig(ctx context.Context) string {
+	config, _ := json.Marshal(map[string]any{
+		"allowEval":         false,
+		"inlineScriptNonce": templ.GetNonce(ctx),
+	})
+	return string(config)
+}
+
+// csrfHeaders is inherited by every htmx request on the page, see
+// security.CSRF.
+func csrfHeaders(ctx context.Context) string {
+	headers, _ := json.Marshal(map[string]string{"X-CSRF-Token": CSRFToken(ctx)})
+	return string(headers)
+}
+
 var _ = templruntime.GeneratedTemplate
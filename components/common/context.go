@@ -0,0 +1,41 @@
+package common
+
+import (
+	"context"
+
+	"github.com/mjmarrazzo/maintenance-app/domain"
+)
+
+type currentUserKey struct{}
+
+// WithCurrentUser makes the signed in user available to components rendered
+// with ctx.
+func WithCurrentUser(ctx context.Context, user *domain.User) context.Context {
+	return context.WithValue(ctx, currentUserKey{}, user)
+}
+
+// CurrentUser returns the signed in user, or nil on public pages.
+func CurrentUser(ctx context.Context) *domain.User {
+	user, _ := ctx.Value(currentUserKey{}).(*domain.User)
+	return user
+}
+
+func isAdmin(ctx context.Context) bool {
+	user := CurrentUser(ctx)
+	return user != nil && user.IsAdmin()
+}
+
+type csrfTokenKey struct{}
+
+// WithCSRFToken makes the CSRF token of the request available to components
+// rendered with ctx.
+func WithCSRFToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, csrfTokenKey{}, token)
+}
+
+// CSRFToken returns the token mutating requests have to send back, or an
+// empty string when the CSRF middleware did not run.
+func CSRFToken(ctx context.Context) string {
+	token, _ := ctx.Value(csrfTokenKey{}).(string)
+	return token
+}
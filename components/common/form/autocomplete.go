@@ -8,4 +8,5 @@ const (
 	AutocompleteUsername        autocomplete = "username"
 	AutocompleteGivenName       autocomplete = "given-name"
 	AutocompleteFamilyName      autocomplete = "family-name"
+	AutocompleteOneTimeCode     autocomplete = "one-time-code"
 )
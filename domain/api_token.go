@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"database/sql"
+	"slices"
+	"time"
+)
+
+type TokenScope string
+
+const (
+	ScopeRead  TokenScope = "read"
+	ScopeWrite TokenScope = "write"
+)
+
+type APIToken struct {
+	ID         int64        `db:"id"`
+	UserID     int64        `db:"user_id"`
+	Name       string       `db:"name"`
+	TokenHash  string       `db:"token_hash"`
+	Scopes     []string     `db:"scopes"`
+	ExpiresAt  sql.NullTime `db:"expires_at"`
+	LastUsedAt sql.NullTime `db:"last_used_at"`
+	CreatedAt  time.Time    `db:"created_at"`
+	RevokedAt  sql.NullTime `db:"revoked_at"`
+}
+
+func (t *APIToken) HasScope(scope TokenScope) bool {
+	return slices.Contains(t.Scopes, string(scope))
+}
+
+func (t *APIToken) IsExpired(now time.Time) bool {
+	return t.ExpiresAt.Valid && !now.Before(t.ExpiresAt.Time)
+}
+
+func (t *APIToken) IsActive(now time.Time) bool {
+	return !t.RevokedAt.Valid && !t.IsExpired(now)
+}
+
+type APITokenRequest struct {
+	Name          string   `json:"name" form:"name" validate:"required,max=100"`
+	Scopes        []string `json:"scopes" form:"scopes" validate:"required,min=1,dive,oneof=read write"`
+	ExpiresInDays int      `json:"expires_in_days" form:"expires_in_days" validate:"omitempty,oneof=7 30 90 365"`
+}
+
+func (tr *APITokenRequest) ToDomain(now time.Time) *APIToken {
+	var expiresAt sql.NullTime
+	if tr.ExpiresInDays > 0 {
+		expiresAt.Time = now.AddDate(0, 0, tr.ExpiresInDays)
+		expiresAt.Valid = true
+	}
+
+	return &APIToken{
+		Name:      tr.Name,
+		Scopes:    slices.Compact(slices.Sorted(slices.Values(tr.Scopes))),
+		ExpiresAt: expiresAt,
+	}
+}
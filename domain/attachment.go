@@ -0,0 +1,19 @@
+package domain
+
+import (
+	"database/sql"
+	"time"
+)
+
+type Attachment struct {
+	ID                  int64          `db:"id"`
+	TaskID              int64          `db:"task_id"`
+	Filename            string         `db:"filename"`
+	ObjectKey           string         `db:"object_key"`
+	MimeType            string         `db:"mime_type"`
+	SizeBytes           int64          `db:"size_bytes"`
+	UploadedBy          int64          `db:"uploaded_by"`
+	UploadedByFirstName sql.NullString `db:"first_name"`
+	UploadedByLastName  sql.NullString `db:"last_name"`
+	UploadDate          time.Time      `db:"upload_date"`
+}
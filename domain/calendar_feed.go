@@ -0,0 +1,19 @@
+package domain
+
+import (
+	"database/sql"
+	"time"
+)
+
+// CalendarFeedHorizon is how far ahead calendar feeds list the occurrences
+// of recurring tasks.
+const CalendarFeedHorizon = 180 * 24 * time.Hour
+
+// CalendarFeed is the secret iCalendar feed of the tasks assigned to a
+// user. Only the hash of the secret in its URL is stored.
+type CalendarFeed struct {
+	UserID     int64        `db:"user_id"`
+	TokenHash  string       `db:"token_hash"`
+	CreatedAt  time.Time    `db:"created_at"`
+	LastUsedAt sql.NullTime `db:"last_used_at"`
+}
@@ -7,7 +7,7 @@ type Category struct {
 }
 
 type CategoryRequest struct {
-	Name        string `json:"name" form:"name"`
+	Name        string `json:"name" form:"name" validate:"required,max=100"`
 	Description string `json:"description" form:"description"`
 }
 
@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"database/sql"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+type Comment struct {
+	ID            int64          `db:"id"`
+	TaskID        int64          `db:"task_id"`
+	UserID        int64          `db:"user_id"`
+	UserFirstName sql.NullString `db:"first_name"`
+	UserLastName  sql.NullString `db:"last_name"`
+	Content       string         `db:"content"`
+	CreatedAt     time.Time      `db:"created_at"`
+}
+
+type CommentRequest struct {
+	Content string `json:"content" form:"content" validate:"required,max=5000"`
+}
+
+func (cr *CommentRequest) ToDomain() *Comment {
+	return &Comment{
+		Content: cr.Content,
+	}
+}
+
+// mentionPattern matches users mentioned by email address, as in
+// "@ada@example.org".
+var mentionPattern = regexp.MustCompile(`(?:^|\s)@([A-Za-z0-9._%+\-]+@[A-Za-z0-9\-]+(?:\.[A-Za-z0-9\-]+)*\.[A-Za-z]{2,})`)
+
+// Mentions returns the email addresses mentioned in the comment, each once.
+func (c *Comment) Mentions() []string {
+	var emails []string
+	for _, match := range mentionPattern.FindAllStringSubmatch(c.Content, -1) {
+		email := match[1]
+		if !slices.ContainsFunc(emails, func(e string) bool { return strings.EqualFold(e, email) }) {
+			emails = append(emails, email)
+		}
+	}
+	return emails
+}
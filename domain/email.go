@@ -0,0 +1,119 @@
+package domain
+
+import (
+	"database/sql"
+	"time"
+)
+
+// EmailDelivery is how a user wants to hear about an EmailEvent by email.
+type EmailDelivery string
+
+const (
+	EmailDeliveryOff       EmailDelivery = "off"
+	EmailDeliveryImmediate EmailDelivery = "immediate"
+	EmailDeliveryDigest    EmailDelivery = "digest"
+)
+
+// EmailEvent is what an email notification is about.
+type EmailEvent string
+
+const (
+	EmailEventAssigned  EmailEvent = "assigned"
+	EmailEventMentioned EmailEvent = "mentioned"
+	EmailEventOverdue   EmailEvent = "overdue"
+)
+
+const (
+	// DigestHour is the hour of the day from which the daily digest is sent.
+	DigestHour = 7
+	// DigestHorizon is how far ahead the digest lists open tasks.
+	DigestHorizon = 7 * 24 * time.Hour
+)
+
+// EmailPreferences are a user's email settings. Users without stored
+// preferences get DefaultEmailPreferences.
+type EmailPreferences struct {
+	UserID       int64         `db:"user_id"`
+	Assigned     EmailDelivery `db:"assigned"`
+	Mentioned    EmailDelivery `db:"mentioned"`
+	Overdue      EmailDelivery `db:"overdue"`
+	DailyDigest  bool          `db:"daily_digest"`
+	DigestSentAt sql.NullTime  `db:"digest_sent_at"`
+}
+
+func DefaultEmailPreferences(userID int64) *EmailPreferences {
+	return &EmailPreferences{
+		UserID:      userID,
+		Assigned:    EmailDeliveryImmediate,
+		Mentioned:   EmailDeliveryImmediate,
+		Overdue:     EmailDeliveryImmediate,
+		DailyDigest: true,
+	}
+}
+
+func (p *EmailPreferences) DeliveryFor(event EmailEvent) EmailDelivery {
+	switch event {
+	case EmailEventAssigned:
+		return p.Assigned
+	case EmailEventMentioned:
+		return p.Mentioned
+	case EmailEventOverdue:
+		return p.Overdue
+	default:
+		return EmailDeliveryOff
+	}
+}
+
+type EmailPreferencesRequest struct {
+	Assigned    string `form:"assigned" validate:"required,oneof=off immediate digest"`
+	Mentioned   string `form:"mentioned" validate:"required,oneof=off immediate digest"`
+	Overdue     string `form:"overdue" validate:"required,oneof=off immediate digest"`
+	DailyDigest bool   `form:"daily_digest"`
+}
+
+func (r *EmailPreferencesRequest) ToDomain() *EmailPreferences {
+	return &EmailPreferences{
+		Assigned:    EmailDelivery(r.Assigned),
+		Mentioned:   EmailDelivery(r.Mentioned),
+		Overdue:     EmailDelivery(r.Overdue),
+		DailyDigest: r.DailyDigest,
+	}
+}
+
+// EmailDigestItem is an email notification held back for the next digest.
+type EmailDigestItem struct {
+	ID        int64        `db:"id"`
+	UserID    int64        `db:"user_id"`
+	TaskID    int64        `db:"task_id"`
+	Event     EmailEvent   `db:"event"`
+	Summary   string       `db:"summary"`
+	SentAt    sql.NullTime `db:"sent_at"`
+	CreatedAt time.Time    `db:"created_at"`
+}
+
+const (
+	// OutboxMaxAttempts is how often delivery of an email is tried before
+	// it is given up.
+	OutboxMaxAttempts = 8
+	OutboxRetryBase   = time.Minute
+	OutboxRetryMax    = 6 * time.Hour
+	// OutboxLease is how long a claimed email is hidden from other senders
+	// while it is being delivered.
+	OutboxLease = 5 * time.Minute
+)
+
+// OutboxEmail is a rendered email waiting to be delivered by the background
+// sender.
+type OutboxEmail struct {
+	ID            int64          `db:"id"`
+	Recipient     string         `db:"recipient"`
+	Subject       string         `db:"subject"`
+	TextBody      string         `db:"text_body"`
+	HTMLBody      string         `db:"html_body"`
+	Attempts      int            `db:"attempts"`
+	NextAttemptAt time.Time      `db:"next_attempt_at"`
+	LastError     sql.NullString `db:"last_error"`
+	SentAt        sql.NullTime   `db:"sent_at"`
+	FailedAt      sql.NullTime   `db:"failed_at"`
+	CreatedAt     time.Time      `db:"created_at"`
+}
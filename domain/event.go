@@ -0,0 +1,32 @@
+package domain
+
+import "time"
+
+type TaskEventKind string
+
+const (
+	TaskEventCreated       TaskEventKind = "task.created"
+	TaskEventAssigned      TaskEventKind = "task.assigned"
+	TaskEventStatusChanged TaskEventKind = "task.status_changed"
+	TaskEventCommented     TaskEventKind = "comment.created"
+	TaskEventDueSoon       TaskEventKind = "task.due_soon"
+	TaskEventOverdue       TaskEventKind = "task.overdue"
+)
+
+// DueSoonWindow is how long before its estimated completion date a task
+// counts as due soon.
+const DueSoonWindow = 24 * time.Hour
+
+// TaskEvent describes something that happened to a task. Events are
+// published by the services that make the change, see events.Bus.
+type TaskEvent struct {
+	Kind TaskEventKind
+	Task *Task
+	// Previous is the task before the change for updates.
+	Previous *Task
+	// ActorID is the user who made the change, or 0 for events raised by
+	// the due date checks.
+	ActorID    int64
+	Comment    *Comment
+	OccurredAt time.Time
+}
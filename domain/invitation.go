@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+)
+
+// InvitationTTL is how long an invitation link can be used.
+const InvitationTTL = 7 * 24 * time.Hour
+
+type Invitation struct {
+	ID             int64          `db:"id"`
+	Email          string         `db:"email"`
+	Role           UserRole       `db:"role"`
+	TokenHash      string         `db:"token_hash"`
+	InvitedBy      sql.NullInt64  `db:"invited_by"`
+	InvitedByName  sql.NullString `db:"invited_by_name"`
+	ExpiresAt      time.Time      `db:"expires_at"`
+	AcceptedAt     sql.NullTime   `db:"accepted_at"`
+	AcceptedUserID sql.NullInt64  `db:"accepted_user_id"`
+	RevokedAt      sql.NullTime   `db:"revoked_at"`
+	CreatedAt      time.Time      `db:"created_at"`
+}
+
+func (i *Invitation) IsPending(now time.Time) bool {
+	return !i.AcceptedAt.Valid && !i.RevokedAt.Valid && now.Before(i.ExpiresAt)
+}
+
+// Matches reports whether email is the address the invitation was sent to.
+func (i *Invitation) Matches(email string) bool {
+	return strings.EqualFold(strings.TrimSpace(email), i.Email)
+}
+
+type InvitationRequest struct {
+	Email string `form:"email" validate:"required,email,max=255"`
+	Role  string `form:"role" validate:"required,oneof=User Administrator"`
+}
+
+func (ir *InvitationRequest) ToDomain() *Invitation {
+	return &Invitation{
+		Email: strings.ToLower(strings.TrimSpace(ir.Email)),
+		Role:  UserRole(ir.Role),
+	}
+}
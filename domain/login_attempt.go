@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"database/sql"
+	"time"
+)
+
+const (
+	// AccountLockoutThreshold is the number of consecutive failures after
+	// which an account is locked. Every further failure doubles the lock.
+	AccountLockoutThreshold = 5
+	AccountLockoutBase      = time.Minute
+	AccountLockoutMax       = time.Hour
+
+	// IPFailureThreshold is the number of failures an address may have within
+	// IPFailureWindow before it has to back off, doubling with each failure.
+	IPFailureThreshold = 10
+	IPFailureWindow    = 15 * time.Minute
+	IPBackoffBase      = time.Second
+	IPBackoffMax       = 15 * time.Minute
+)
+
+type LoginAttempt struct {
+	ID        int64         `db:"id"`
+	Email     string        `db:"email"`
+	UserID    sql.NullInt64 `db:"user_id"`
+	IPAddress string        `db:"ip_address"`
+	Succeeded bool          `db:"succeeded"`
+	Reason    string        `db:"reason"`
+	CreatedAt time.Time     `db:"created_at"`
+}
+
+// Backoff returns how long to wait after failures, or zero while failures
+// is below threshold. The wait starts at base and doubles with every
+// further failure up to max.
+func Backoff(failures, threshold int, base, max time.Duration) time.Duration {
+	if failures < threshold {
+		return 0
+	}
+
+	wait := base
+	for i := threshold; i < failures; i++ {
+		wait *= 2
+		if wait >= max {
+			return max
+		}
+	}
+	return wait
+}
+
+const (
+	LoginReasonUnknownEmail      = "unknown_email"
+	LoginReasonWrongPassword     = "wrong_password"
+	LoginReasonWrongSecondFactor = "wrong_second_factor"
+	LoginReasonLocked            = "locked"
+	LoginReasonRateLimited       = "rate_limited"
+	LoginReasonEmailNotVerified  = "email_not_verified"
+)
@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Notification tells a user about a TaskEvent. The text is rendered from
+// Kind and Detail when the notification is shown.
+type Notification struct {
+	ID             int64          `db:"id"`
+	UserID         int64          `db:"user_id"`
+	TaskID         int64          `db:"task_id"`
+	TaskTitle      string         `db:"task_title"`
+	ActorID        sql.NullInt64  `db:"actor_id"`
+	ActorFirstName sql.NullString `db:"actor_first_name"`
+	ActorLastName  sql.NullString `db:"actor_last_name"`
+	Kind           TaskEventKind  `db:"kind"`
+	// Detail is the new status for status changes and an excerpt of the
+	// comment for comments.
+	Detail    string       `db:"detail"`
+	ReadAt    sql.NullTime `db:"read_at"`
+	CreatedAt time.Time    `db:"created_at"`
+}
+
+func (n *Notification) IsRead() bool {
+	return n.ReadAt.Valid
+}
+
+// ActorName is the name of the user who caused the notification.
+func (n *Notification) ActorName() string {
+	if !n.ActorFirstName.Valid && !n.ActorLastName.Valid {
+		return "Someone"
+	}
+	return n.ActorFirstName.String + " " + n.ActorLastName.String
+}
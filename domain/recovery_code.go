@@ -0,0 +1,23 @@
+package domain
+
+import (
+	"database/sql"
+	"time"
+)
+
+// RecoveryCodeCount is how many recovery codes are issued at a time.
+const RecoveryCodeCount = 10
+
+type RecoveryCode struct {
+	ID        int64        `db:"id"`
+	UserID    int64        `db:"user_id"`
+	CodeHash  string       `db:"code_hash"`
+	UsedAt    sql.NullTime `db:"used_at"`
+	CreatedAt time.Time    `db:"created_at"`
+}
+
+// TwoFactorRequest carries a code from the authenticator app or, where
+// accepted, a recovery code.
+type TwoFactorRequest struct {
+	Code string `form:"code" validate:"required,max=32"`
+}
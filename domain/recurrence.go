@@ -0,0 +1,110 @@
+package domain
+
+import "time"
+
+// maxProjectedSteps bounds how far occurrences are projected, so a daily
+// task with a due date long in the past cannot stall a calendar.
+const maxProjectedSteps = 5000
+
+// Occurrence is a date a task is due on. Projected occurrences are future
+// repetitions of a recurring task that do not exist as tasks yet.
+type Occurrence struct {
+	Task      *Task
+	Due       time.Time
+	Projected bool
+}
+
+// recurrenceStep returns the years, months and days between occurrences of
+// t, as the calculate_next_occurrence trigger steps next_occurrence.
+func (t *Task) recurrenceStep() (years, months, days int) {
+	interval := max(t.RecurrenceInterval, 1)
+	kind := RecurrenceType(t.RecurrenceType.String)
+	if kind == RecurrentTypeCustom {
+		switch RecurrenceUnit(t.RecurrenceUnit.String) {
+		case RecurrenceUnitWeeks:
+			kind = RecurrenceTypeWeekly
+		case RecurrenceUnitMonths:
+			kind = RecurrenceTypeMonthly
+		case RecurrenceUnitYears:
+			kind = RecurrenceTypeYearly
+		case RecurrenceUnitDays:
+			kind = RecurrenceTypeDaily
+		default:
+			return 0, 0, 1
+		}
+	}
+
+	switch kind {
+	case RecurrenceTypeDaily:
+		return 0, 0, interval
+	case RecurrenceTypeWeekly:
+		return 0, 0, 7 * interval
+	case RecurrenceTypeMonthly:
+		return 0, interval, 0
+	case RecurrenceTypeYearly:
+		return interval, 0, 0
+	}
+	return 0, 0, 1
+}
+
+// recurrenceAnchor is the occurrence the others are counted from: the due
+// date, or the next occurrence of a task without one.
+func (t *Task) recurrenceAnchor() (time.Time, bool) {
+	if t.EstimatedCompletionDate.Valid {
+		return t.EstimatedCompletionDate.Time, true
+	}
+	return t.NextOccurrence.Time, t.NextOccurrence.Valid
+}
+
+// Occurrences returns the occurrences of t due from from until to. The due
+// date is an occurrence of its own and the later ones of a recurring task
+// are projected from it in zone, so they keep their local time of day.
+// Like in Postgres, a monthly task due on the 31st is due on the last day
+// of shorter months.
+func (t *Task) Occurrences(from, to time.Time, zone *time.Location) []Occurrence {
+	var occurrences []Occurrence
+	inRange := func(due time.Time) bool {
+		return !due.Before(from) && due.Before(to)
+	}
+
+	if t.EstimatedCompletionDate.Valid && inRange(t.EstimatedCompletionDate.Time) {
+		occurrences = append(occurrences, Occurrence{Task: t, Due: t.EstimatedCompletionDate.Time})
+	}
+	if !t.IsRecurring {
+		return occurrences
+	}
+
+	anchor, ok := t.recurrenceAnchor()
+	if !ok {
+		return occurrences
+	}
+	anchor = anchor.In(zone)
+	years, months, days := t.recurrenceStep()
+
+	// A task without a due date is first due on its next occurrence.
+	first := 1
+	if !t.EstimatedCompletionDate.Valid {
+		first = 0
+	}
+	for step := first; step <= maxProjectedSteps; step++ {
+		due := addDate(anchor, step*years, step*months, step*days)
+		if !due.Before(to) {
+			break
+		}
+		if inRange(due) {
+			occurrences = append(occurrences, Occurrence{Task: t, Due: due, Projected: true})
+		}
+	}
+	return occurrences
+}
+
+// addDate adds to t like time.AddDate, except that a day past the end of
+// the resulting month is clamped to its last day instead of overflowing
+// into the next.
+func addDate(t time.Time, years, months, days int) time.Time {
+	year, month, day := t.Date()
+	hour, minute, second := t.Clock()
+	first := time.Date(year+years, month+time.Month(months), 1, hour, minute, second, t.Nanosecond(), t.Location())
+	lastDay := first.AddDate(0, 1, -1).Day()
+	return first.AddDate(0, 0, min(day, lastDay)-1+days)
+}
@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+)
+
+func TestOccurrences(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	date := func(year int, month time.Month, day, hour int) time.Time {
+		return time.Date(year, month, day, hour, 0, 0, 0, newYork)
+	}
+	recurring := func(kind RecurrenceType, interval int, unit RecurrenceUnit, due time.Time) *Task {
+		return &Task{
+			IsRecurring:             true,
+			RecurrenceType:          sql.NullString{String: string(kind), Valid: true},
+			RecurrenceInterval:      interval,
+			RecurrenceUnit:          sql.NullString{String: string(unit), Valid: unit != ""},
+			EstimatedCompletionDate: sql.NullTime{Time: due, Valid: true},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		task     *Task
+		from     time.Time
+		to       time.Time
+		expected []time.Time
+	}{
+		{
+			name:     "single task",
+			task:     &Task{EstimatedCompletionDate: sql.NullTime{Time: date(2026, time.March, 2, 23), Valid: true}},
+			from:     date(2026, time.March, 1, 0),
+			to:       date(2026, time.April, 1, 0),
+			expected: []time.Time{date(2026, time.March, 2, 23)},
+		},
+		{
+			name:     "weekly keeps the local time across daylight saving",
+			task:     recurring(RecurrenceTypeWeekly, 1, "", date(2026, time.March, 2, 23)),
+			from:     date(2026, time.March, 1, 0),
+			to:       date(2026, time.March, 17, 0),
+			expected: []time.Time{date(2026, time.March, 2, 23), date(2026, time.March, 9, 23), date(2026, time.March, 16, 23)},
+		},
+		{
+			name:     "monthly on the last day",
+			task:     recurring(RecurrenceTypeMonthly, 1, "", date(2026, time.January, 31, 23)),
+			from:     date(2026, time.February, 1, 0),
+			to:       date(2026, time.April, 1, 0),
+			expected: []time.Time{date(2026, time.February, 28, 23), date(2026, time.March, 31, 23)},
+		},
+		{
+			name:     "custom interval",
+			task:     recurring(RecurrentTypeCustom, 10, RecurrenceUnitDays, date(2026, time.January, 1, 23)),
+			from:     date(2026, time.January, 15, 0),
+			to:       date(2026, time.February, 1, 0),
+			expected: []time.Time{date(2026, time.January, 21, 23), date(2026, time.January, 31, 23)},
+		},
+		{
+			name: "next occurrence without a due date",
+			task: &Task{
+				IsRecurring:    true,
+				RecurrenceType: sql.NullString{String: string(RecurrenceTypeYearly), Valid: true},
+				NextOccurrence: sql.NullTime{Time: date(2026, time.June, 1, 9), Valid: true},
+			},
+			from:     date(2026, time.January, 1, 0),
+			to:       date(2028, time.January, 1, 0),
+			expected: []time.Time{date(2026, time.June, 1, 9), date(2027, time.June, 1, 9)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			occurrences := tt.task.Occurrences(tt.from, tt.to, newYork)
+			if len(occurrences) != len(tt.expected) {
+				t.Fatalf("Expected %d occurrences, got %d", len(tt.expected), len(occurrences))
+			}
+			for i, occurrence := range occurrences {
+				if !occurrence.Due.Equal(tt.expected[i]) {
+					t.Errorf("Expected occurrence %d on %v, got %v", i, tt.expected[i], occurrence.Due)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"time"
+)
+
+const (
+	// SessionIdleTimeout is how long a session survives without requests.
+	SessionIdleTimeout = 24 * time.Hour
+	// SessionLifetime caps a session regardless of activity. The session
+	// cookie uses the same max age.
+	SessionLifetime = 7 * 24 * time.Hour
+)
+
+type Session struct {
+	ID         int64     `db:"id"`
+	UserID     int64     `db:"user_id"`
+	TokenHash  string    `db:"token_hash"`
+	UserAgent  string    `db:"user_agent"`
+	IPAddress  string    `db:"ip_address"`
+	CreatedAt  time.Time `db:"created_at"`
+	LastSeenAt time.Time `db:"last_seen_at"`
+	ExpiresAt  time.Time `db:"expires_at"`
+}
+
+func (s *Session) IsExpired(now time.Time) bool {
+	return !now.Before(s.ExpiresAt)
+}
+
+// NextExpiry slides the idle timeout forward from now without exceeding the
+// absolute lifetime of the session.
+func (s *Session) NextExpiry(now time.Time) time.Time {
+	expiry := now.Add(SessionIdleTimeout)
+	if limit := s.CreatedAt.Add(SessionLifetime); expiry.After(limit) {
+		return limit
+	}
+	return expiry
+}
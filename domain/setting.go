@@ -0,0 +1,58 @@
+package domain
+
+import "strings"
+
+type RegistrationMode string
+
+const (
+	RegistrationOpen       RegistrationMode = "open"
+	RegistrationInviteOnly RegistrationMode = "invite-only"
+	RegistrationClosed     RegistrationMode = "closed"
+)
+
+const (
+	// SettingRegistrationMode is the settings key holding the RegistrationMode.
+	SettingRegistrationMode = "registration_mode"
+	// SettingRequireAdminTwoFactor holds "true" when administrators have to
+	// use two-factor authentication.
+	SettingRequireAdminTwoFactor = "require_admin_two_factor"
+	// SettingTimeZone is the IANA name of the time zone of the organization,
+	// which dates are shown and entered in unless users pick their own.
+	SettingTimeZone = "time_zone"
+)
+
+// WIPLimitStatuses are the statuses of the task board whose number of tasks
+// can be limited.
+var WIPLimitStatuses = []Status{StatusNew, StatusInProgress, StatusOnHold}
+
+// WIPLimitSetting is the settings key holding the most tasks that should
+// have status, e.g. "wip_limit_in_progress". Missing or 0 is no limit.
+func WIPLimitSetting(status Status) string {
+	return "wip_limit_" + strings.ReplaceAll(strings.ToLower(string(status)), " ", "_")
+}
+
+func (m RegistrationMode) IsValid() bool {
+	switch m {
+	case RegistrationOpen, RegistrationInviteOnly, RegistrationClosed:
+		return true
+	}
+	return false
+}
+
+type SettingsRequest struct {
+	RegistrationMode      string `form:"registration_mode" validate:"required,oneof=open invite-only closed"`
+	RequireAdminTwoFactor bool   `form:"require_admin_two_factor"`
+	TimeZone              string `form:"time_zone" validate:"required,timezone"`
+	WIPLimitNew           int    `form:"wip_limit_new" validate:"min=0,max=1000"`
+	WIPLimitInProgress    int    `form:"wip_limit_in_progress" validate:"min=0,max=1000"`
+	WIPLimitOnHold        int    `form:"wip_limit_on_hold" validate:"min=0,max=1000"`
+}
+
+// WIPLimits returns the limits of the request by status.
+func (sr *SettingsRequest) WIPLimits() map[Status]int {
+	return map[Status]int{
+		StatusNew:        sr.WIPLimitNew,
+		StatusInProgress: sr.WIPLimitInProgress,
+		StatusOnHold:     sr.WIPLimitOnHold,
+	}
+}
@@ -2,8 +2,13 @@ package domain
 
 import (
 	"database/sql"
+	"fmt"
 	"strconv"
+	"strings"
 	"time"
+
+	"github.com/mjmarrazzo/maintenance-app/internal/patch"
+	"github.com/mjmarrazzo/maintenance-app/internal/responses"
 )
 
 type Priority string
@@ -37,10 +42,10 @@ const (
 type RecurrenceUnit string
 
 const (
-	RecurrenceUnitDay   RecurrenceUnit = "Day"
-	RecurrenceUnitWeek  RecurrenceUnit = "Week"
-	RecurrenceUnitMonth RecurrenceUnit = "Month"
-	RecurrenceUnitYear  RecurrenceUnit = "Year"
+	RecurrenceUnitDays   RecurrenceUnit = "Days"
+	RecurrenceUnitWeeks  RecurrenceUnit = "Weeks"
+	RecurrenceUnitMonths RecurrenceUnit = "Months"
+	RecurrenceUnitYears  RecurrenceUnit = "Years"
 )
 
 type Task struct {
@@ -73,112 +78,378 @@ type Task struct {
 }
 
 type TaskRequest struct {
-	Title                   string `json:"title" form:"title"`
+	Title                   string `json:"title" form:"title" validate:"required,max=255"`
 	Description             string `json:"description" form:"description"`
-	CategoryID              string `json:"category_id" form:"category_id"`
-	LocationID              string `json:"location_id" form:"location_id"`
-	TaskPriority            string `json:"priority" form:"priority"`
-	TaskStatus              string `json:"status" form:"status"`
-	AssignedTo              string `json:"assigned_to" form:"assigned_to"`
-	EstimatedCompletionDate string `json:"estimated_completion_date" form:"estimated_completion_date"`
-	Cost                    string `json:"cost" form:"cost"`
+	CategoryID              string `json:"category_id" form:"category_id" validate:"omitempty,numericstring=gte=0"`
+	LocationID              string `json:"location_id" form:"location_id" validate:"omitempty,numericstring=gte=0"`
+	TaskPriority            string `json:"priority" form:"priority" validate:"omitempty,oneof=Low Medium High Urgent"`
+	TaskStatus              string `json:"status" form:"status" validate:"omitempty,oneof=New 'In Progress' Completed 'On Hold'"`
+	AssignedTo              string `json:"assigned_to" form:"assigned_to" validate:"omitempty,numericstring=gte=0"`
+	EstimatedCompletionDate string `json:"estimated_completion_date" form:"estimated_completion_date" validate:"omitempty,date"`
+	Cost                    string `json:"cost" form:"cost" validate:"omitempty,numericstring=gte=0"`
 	IsRecurring             bool   `json:"is_recurring" form:"is_recurring"`
-	RecurrenceType          string `json:"recurrence_type" form:"recurrence_type"`
-	RecurrenceInterval      int    `json:"recurrence_interval" form:"recurrence_interval"`
-	RecurrenceUnit          string `json:"recurrence_unit" form:"recurrence_unit"`
-	ParentTaskID            string `json:"parent_task_id" form:"parent_task_id"`
-}
-
-func (tr *TaskRequest) ToDomain() *Task {
-	var categoryID, locationID, assignedTo, parentTaskID sql.NullInt64
-	if tr.CategoryID != "" {
-		num, err := strconv.ParseInt(tr.CategoryID, 10, 64)
-		if err == nil {
-			categoryID.Int64 = num
-			categoryID.Valid = true
-		}
+	RecurrenceType          string `json:"recurrence_type" form:"recurrence_type" validate:"omitempty,oneof=Daily Weekly Monthly Yearly Custom"`
+	RecurrenceInterval      int    `json:"recurrence_interval" form:"recurrence_interval" validate:"gte=0,lte=1000"`
+	RecurrenceUnit          string `json:"recurrence_unit" form:"recurrence_unit" validate:"omitempty,oneof=Days Weeks Months Years"`
+	ParentTaskID            string `json:"parent_task_id" form:"parent_task_id" validate:"omitempty,numericstring=gte=0"`
+	// UpdatedAt is the updated_at of the task the edit is based on, in
+	// RFC 3339 format. When it is set, the update fails with a conflict if
+	// someone else saved the task in the meantime.
+	UpdatedAt string `json:"updated_at" form:"updated_at" validate:"omitempty,datetime=2006-01-02T15:04:05.999999999Z07:00"`
+}
+
+// ToDomain converts the request into a task. Malformed ids, dates and costs
+// are reported as a validation error. A due date without a time is the end
+// of that day in zone.
+func (tr *TaskRequest) ToDomain(zone *time.Location) (*Task, error) {
+	p := requestParser{zone: zone}
+	task := &Task{
+		Title:                   tr.Title,
+		Description:             tr.Description,
+		CategoryID:              p.id("category_id", tr.CategoryID),
+		LocationID:              p.id("location_id", tr.LocationID),
+		Priority:                nullString(tr.TaskPriority),
+		Status:                  nullString(tr.TaskStatus),
+		AssignedTo:              p.id("assigned_to", tr.AssignedTo),
+		EstimatedCompletionDate: p.date("estimated_completion_date", tr.EstimatedCompletionDate),
+		Cost:                    p.float("cost", tr.Cost),
+		IsRecurring:             tr.IsRecurring,
+		RecurrenceType:          nullString(tr.RecurrenceType),
+		RecurrenceInterval:      tr.RecurrenceInterval,
+		RecurrenceUnit:          nullString(tr.RecurrenceUnit),
+		ParentTaskID:            p.id("parent_task_id", tr.ParentTaskID),
+		UpdatedAt:               p.timestamp("updated_at", tr.UpdatedAt).Time,
 	}
+	if err := p.err(); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
 
-	if tr.LocationID != "" {
-		num, err := strconv.ParseInt(tr.LocationID, 10, 64)
-		if err == nil {
-			locationID.Int64 = num
-			locationID.Valid = true
+// Validate checks the rules that span fields. A recurring task needs a
+// recurrence type and a custom recurrence also an interval and unit.
+func (t *Task) Validate() error {
+	var p requestParser
+	if strings.TrimSpace(t.Title) == "" {
+		p.invalid("title", "This field is required")
+	}
+	if t.IsRecurring {
+		if !t.RecurrenceType.Valid {
+			p.invalid("recurrence_type", "Required for recurring tasks")
+		}
+		if t.RecurrenceType.String == string(RecurrentTypeCustom) {
+			if t.RecurrenceInterval < 1 {
+				p.invalid("recurrence_interval", "Required for custom recurrences")
+			}
+			if !t.RecurrenceUnit.Valid {
+				p.invalid("recurrence_unit", "Required for custom recurrences")
+			}
 		}
 	}
+	if t.ID != 0 && t.ParentTaskID.Valid && t.ParentTaskID.Int64 == t.ID {
+		p.invalid("parent_task_id", "A task cannot be its own parent")
+	}
+	return p.err()
+}
 
-	if tr.AssignedTo != "" {
-		num, err := strconv.ParseInt(tr.AssignedTo, 10, 64)
-		if err == nil {
-			assignedTo.Int64 = num
-			assignedTo.Valid = true
+// ToPatch converts the fields of the request named in supplied into a patch,
+// as for a form that only posts some of them. Empty values clear a field.
+// Dates are read as in ToDomain.
+func (tr *TaskRequest) ToPatch(supplied []string, zone *time.Location) (*TaskPatch, error) {
+	p := requestParser{zone: zone}
+	tp := &TaskPatch{}
+	for _, field := range supplied {
+		switch field {
+		case "title":
+			tp.Title = patch.Of(tr.Title, true)
+		case "description":
+			tp.Description = patch.Of(tr.Description, true)
+		case "category_id":
+			tp.CategoryID = nullInt64Field(p.id(field, tr.CategoryID))
+		case "location_id":
+			tp.LocationID = nullInt64Field(p.id(field, tr.LocationID))
+		case "priority":
+			tp.Priority = patch.Of(tr.TaskPriority, tr.TaskPriority != "")
+		case "status":
+			tp.Status = patch.Of(tr.TaskStatus, tr.TaskStatus != "")
+		case "assigned_to":
+			tp.AssignedTo = nullInt64Field(p.id(field, tr.AssignedTo))
+		case "estimated_completion_date":
+			date := p.date(field, tr.EstimatedCompletionDate)
+			tp.EstimatedCompletionDate = patch.Of(date.Time, date.Valid)
+		case "cost":
+			cost := p.float(field, tr.Cost)
+			tp.Cost = patch.Of(cost.Float64, cost.Valid)
+		case "is_recurring":
+			tp.IsRecurring = patch.Of(tr.IsRecurring, true)
+		case "recurrence_type":
+			tp.RecurrenceType = patch.Of(tr.RecurrenceType, tr.RecurrenceType != "")
+		case "recurrence_interval":
+			tp.RecurrenceInterval = patch.Of(tr.RecurrenceInterval, true)
+		case "recurrence_unit":
+			tp.RecurrenceUnit = patch.Of(tr.RecurrenceUnit, tr.RecurrenceUnit != "")
+		case "parent_task_id":
+			tp.ParentTaskID = nullInt64Field(p.id(field, tr.ParentTaskID))
+		case "updated_at":
+			if updatedAt := p.timestamp(field, tr.UpdatedAt); updatedAt.Valid {
+				tp.UpdatedAt = &updatedAt.Time
+			}
 		}
 	}
+	if err := p.err(); err != nil {
+		return nil, err
+	}
+	return tp, nil
+}
 
-	if tr.ParentTaskID != "" {
-		num, err := strconv.ParseInt(tr.ParentTaskID, 10, 64)
-		if err == nil {
-			parentTaskID.Int64 = num
-			parentTaskID.Valid = true
-		}
+// TaskPatch changes some fields of a task following JSON Merge Patch
+// (RFC 7396): fields missing from the document are left as they are and
+// null clears them.
+type TaskPatch struct {
+	Title                   patch.Field[string]    `json:"title" validate:"omitempty,max=255"`
+	Description             patch.Field[string]    `json:"description"`
+	CategoryID              patch.Field[int64]     `json:"category_id" validate:"omitempty,gt=0"`
+	LocationID              patch.Field[int64]     `json:"location_id" validate:"omitempty,gt=0"`
+	Priority                patch.Field[string]    `json:"priority" validate:"omitempty,oneof=Low Medium High Urgent"`
+	Status                  patch.Field[string]    `json:"status" validate:"omitempty,oneof=New 'In Progress' Completed 'On Hold'"`
+	AssignedTo              patch.Field[int64]     `json:"assigned_to" validate:"omitempty,gt=0"`
+	EstimatedCompletionDate patch.Field[time.Time] `json:"estimated_completion_date"`
+	Cost                    patch.Field[float64]   `json:"cost" validate:"omitempty,gte=0"`
+	IsRecurring             patch.Field[bool]      `json:"is_recurring"`
+	RecurrenceType          patch.Field[string]    `json:"recurrence_type" validate:"omitempty,oneof=Daily Weekly Monthly Yearly Custom"`
+	RecurrenceInterval      patch.Field[int]       `json:"recurrence_interval" validate:"omitempty,gte=0,lte=1000"`
+	RecurrenceUnit          patch.Field[string]    `json:"recurrence_unit" validate:"omitempty,oneof=Days Weeks Months Years"`
+	ParentTaskID            patch.Field[int64]     `json:"parent_task_id" validate:"omitempty,gt=0"`
+	// UpdatedAt works as TaskRequest.UpdatedAt.
+	UpdatedAt *time.Time `json:"updated_at"`
+}
+
+// Columns returns the values of the fields in the patch keyed by column.
+func (tp *TaskPatch) Columns() map[string]any {
+	columns := map[string]any{}
+	setColumn(columns, "title", tp.Title)
+	setColumn(columns, "description", tp.Description)
+	setColumn(columns, "category_id", tp.CategoryID)
+	setColumn(columns, "location_id", tp.LocationID)
+	setColumn(columns, "priority", tp.Priority)
+	setColumn(columns, "status", tp.Status)
+	setColumn(columns, "assigned_to", tp.AssignedTo)
+	setColumn(columns, "estimated_completion_date", tp.EstimatedCompletionDate)
+	setColumn(columns, "cost", tp.Cost)
+	setColumn(columns, "is_recurring", tp.IsRecurring)
+	setColumn(columns, "recurrence_type", tp.RecurrenceType)
+	setColumn(columns, "recurrence_interval", tp.RecurrenceInterval)
+	setColumn(columns, "recurrence_unit", tp.RecurrenceUnit)
+	setColumn(columns, "parent_task_id", tp.ParentTaskID)
+	return columns
+}
+
+// Apply sets the fields in the patch on task. The names of a changed
+// category or location are cleared.
+func (tp *TaskPatch) Apply(task *Task) {
+	if tp.Title.Set {
+		task.Title = tp.Title.Value
+	}
+	if tp.Description.Set {
+		task.Description = tp.Description.Value
+	}
+	if tp.CategoryID.Set {
+		task.CategoryID = sql.NullInt64{Int64: tp.CategoryID.Value, Valid: tp.CategoryID.Valid}
+		task.CategoryName = sql.NullString{}
+	}
+	if tp.LocationID.Set {
+		task.LocationID = sql.NullInt64{Int64: tp.LocationID.Value, Valid: tp.LocationID.Valid}
+		task.LocationName = sql.NullString{}
+	}
+	if tp.Priority.Set {
+		task.Priority = sql.NullString{String: tp.Priority.Value, Valid: tp.Priority.Valid}
+	}
+	if tp.Status.Set {
+		task.Status = sql.NullString{String: tp.Status.Value, Valid: tp.Status.Valid}
+	}
+	if tp.AssignedTo.Set {
+		task.AssignedTo = sql.NullInt64{Int64: tp.AssignedTo.Value, Valid: tp.AssignedTo.Valid}
+	}
+	if tp.EstimatedCompletionDate.Set {
+		task.EstimatedCompletionDate = sql.NullTime{Time: tp.EstimatedCompletionDate.Value, Valid: tp.EstimatedCompletionDate.Valid}
 	}
+	if tp.Cost.Set {
+		task.Cost = sql.NullFloat64{Float64: tp.Cost.Value, Valid: tp.Cost.Valid}
+	}
+	if tp.IsRecurring.Set {
+		task.IsRecurring = tp.IsRecurring.Value
+	}
+	if tp.RecurrenceType.Set {
+		task.RecurrenceType = sql.NullString{String: tp.RecurrenceType.Value, Valid: tp.RecurrenceType.Valid}
+	}
+	if tp.RecurrenceInterval.Set {
+		task.RecurrenceInterval = tp.RecurrenceInterval.Value
+	}
+	if tp.RecurrenceUnit.Set {
+		task.RecurrenceUnit = sql.NullString{String: tp.RecurrenceUnit.Value, Valid: tp.RecurrenceUnit.Valid}
+	}
+	if tp.ParentTaskID.Set {
+		task.ParentTaskID = sql.NullInt64{Int64: tp.ParentTaskID.Value, Valid: tp.ParentTaskID.Valid}
+	}
+	if tp.UpdatedAt != nil {
+		task.UpdatedAt = *tp.UpdatedAt
+	}
+}
 
-	var cost sql.NullFloat64
-	if tr.Cost != "" {
-		num, err := strconv.ParseFloat(tr.Cost, 64)
-		if err == nil {
-			cost.Float64 = num
-			cost.Valid = true
-		}
+func setColumn[T any](columns map[string]any, column string, field patch.Field[T]) {
+	if field.Set {
+		columns[column] = field.Any()
 	}
+}
 
-	var estimatedCompletionDate sql.NullTime
-	if tr.EstimatedCompletionDate != "" {
-		t, err := time.Parse(time.RFC3339, tr.EstimatedCompletionDate)
-		if err == nil {
-			estimatedCompletionDate.Time = t
-			estimatedCompletionDate.Valid = true
-		}
+func nullInt64Field(value sql.NullInt64) patch.Field[int64] {
+	return patch.Of(value.Int64, value.Valid)
+}
+
+func nullString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
+
+// dateLayout is the format of date inputs.
+const dateLayout = "2006-01-02"
+
+// EndOfDay returns the last second of the day of t in zone, when a task due
+// that day becomes overdue.
+func EndOfDay(t time.Time, zone *time.Location) time.Time {
+	year, month, day := t.In(zone).Date()
+	return time.Date(year, month, day, 23, 59, 59, 0, zone)
+}
+
+// requestParser parses the string fields of form requests and collects a
+// violation for each one that is malformed. Empty values are null. Dates
+// without a time are read in zone, or UTC.
+// Task.Validate collects its violations with it too.
+type requestParser struct {
+	zone       *time.Location
+	violations []*responses.ViolationsDetail
+}
+
+func (p *requestParser) invalid(field, message string) {
+	p.violations = append(p.violations, &responses.ViolationsDetail{Name: field, Message: message})
+}
+
+// id treats 0, what selects post when nothing is chosen, as null.
+func (p *requestParser) id(field, value string) sql.NullInt64 {
+	if value == "" || value == "0" {
+		return sql.NullInt64{}
 	}
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || id < 1 {
+		p.invalid(field, "Should be a valid id")
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: id, Valid: true}
+}
 
-	var recurrenceType sql.NullString
-	if tr.RecurrenceType != "" {
-		recurrenceType.String = string(RecurrenceType(tr.RecurrenceType))
-		recurrenceType.Valid = true
+func (p *requestParser) float(field, value string) sql.NullFloat64 {
+	if value == "" {
+		return sql.NullFloat64{}
 	}
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		p.invalid(field, "Should be a number")
+		return sql.NullFloat64{}
+	}
+	return sql.NullFloat64{Float64: number, Valid: true}
+}
 
-	var recurrenceUnit sql.NullString
-	if tr.RecurrenceUnit != "" {
-		recurrenceUnit.String = string(RecurrenceUnit(tr.RecurrenceUnit))
-		recurrenceUnit.Valid = true
+// date accepts a date input, which is the end of that day, or an RFC 3339
+// timestamp.
+func (p *requestParser) date(field, value string) sql.NullTime {
+	if value == "" {
+		return sql.NullTime{}
+	}
+	zone := p.zone
+	if zone == nil {
+		zone = time.UTC
+	}
+	if t, err := time.ParseInLocation(dateLayout, value, zone); err == nil {
+		return sql.NullTime{Time: EndOfDay(t, zone), Valid: true}
 	}
+	return p.timestamp(field, value)
+}
 
-	var priority sql.NullString
-	if tr.TaskPriority != "" {
-		priority.String = string(Priority(tr.TaskPriority))
-		priority.Valid = true
+func (p *requestParser) timestamp(field, value string) sql.NullTime {
+	if value == "" {
+		return sql.NullTime{}
+	}
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		p.invalid(field, "Should be a date")
+		return sql.NullTime{}
 	}
+	return sql.NullTime{Time: t, Valid: true}
+}
 
-	var status sql.NullString
-	if tr.TaskStatus != "" {
-		status.String = string(Status(tr.TaskStatus))
-		status.Valid = true
+func (p *requestParser) err() error {
+	if len(p.violations) == 0 {
+		return nil
 	}
+	parameters := make([]string, len(p.violations))
+	for i, violation := range p.violations {
+		parameters[i] = violation.Name
+	}
+	return responses.NewValidationError("Validation failed", parameters, p.violations)
+}
 
-	return &Task{
-		Title:                   tr.Title,
-		Description:             tr.Description,
-		CategoryID:              categoryID,
-		LocationID:              locationID,
-		Priority:                priority,
-		Status:                  status,
-		AssignedTo:              assignedTo,
-		EstimatedCompletionDate: estimatedCompletionDate,
-		Cost:                    cost,
-		IsRecurring:             tr.IsRecurring,
-		RecurrenceType:          recurrenceType,
-		RecurrenceInterval:      tr.RecurrenceInterval,
-		RecurrenceUnit:          recurrenceUnit,
-		ParentTaskID:            parentTaskID,
+// TaskChange is a field of the edit form that differs between two versions
+// of a task. From and To are formatted for display.
+type TaskChange struct {
+	Field string
+	From  string
+	To    string
+}
+
+var taskChangeFields = []struct {
+	name   string
+	format func(t *Task, zone *time.Location) string
+}{
+	{"title", func(t *Task, _ *time.Location) string { return t.Title }},
+	{"description", func(t *Task, _ *time.Location) string { return t.Description }},
+	{"category_id", func(t *Task, _ *time.Location) string { return formatReference(t.CategoryID, t.CategoryName) }},
+	{"location_id", func(t *Task, _ *time.Location) string { return formatReference(t.LocationID, t.LocationName) }},
+	{"priority", func(t *Task, _ *time.Location) string { return t.Priority.String }},
+	{"status", func(t *Task, _ *time.Location) string { return t.Status.String }},
+	{"estimated_completion_date", func(t *Task, zone *time.Location) string {
+		if !t.EstimatedCompletionDate.Valid {
+			return ""
+		}
+		return t.EstimatedCompletionDate.Time.In(zone).Format("Jan 2, 2006")
+	}},
+	{"cost", func(t *Task, _ *time.Location) string { return fmt.Sprintf("%.2f", t.Cost.Float64) }},
+	{"parent_task_id", func(t *Task, _ *time.Location) string { return formatReference(t.ParentTaskID, sql.NullString{}) }},
+	{"is_recurring", func(t *Task, _ *time.Location) string { return strconv.FormatBool(t.IsRecurring) }},
+	{"recurrence_type", func(t *Task, _ *time.Location) string { return t.RecurrenceType.String }},
+	{"recurrence_interval", func(t *Task, _ *time.Location) string { return strconv.Itoa(t.RecurrenceInterval) }},
+	{"recurrence_unit", func(t *Task, _ *time.Location) string { return t.RecurrenceUnit.String }},
+}
+
+// Changes lists the fields of the edit form that differ from t to other,
+// with dates in zone. Category and location are compared by name, so both
+// tasks need them loaded.
+func (t *Task) Changes(other *Task, zone *time.Location) []TaskChange {
+	var changes []TaskChange
+	for _, field := range taskChangeFields {
+		from, to := field.format(t, zone), field.format(other, zone)
+		if from != to {
+			changes = append(changes, TaskChange{Field: field.name, From: from, To: to})
+		}
+	}
+	return changes
+}
+
+func formatReference(id sql.NullInt64, name sql.NullString) string {
+	switch {
+	case name.Valid:
+		return name.String
+	case id.Valid && id.Int64 != 0:
+		return "#" + strconv.FormatInt(id.Int64, 10)
+	default:
+		return ""
 	}
 }
@@ -0,0 +1,98 @@
+package domain
+
+import (
+	"os"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// schemaEnum returns the labels of the enum name in schema.sql.
+func schemaEnum(t *testing.T, name string) []string {
+	t.Helper()
+	schema, err := os.ReadFile("../schema.sql")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	match := regexp.MustCompile(`CREATE TYPE ` + name + ` AS ENUM \(([^)]*)\)`).FindSubmatch(schema)
+	if match == nil {
+		t.Fatalf("Expected schema.sql to define the %s enum", name)
+	}
+	var labels []string
+	for _, label := range strings.Split(string(match[1]), ",") {
+		labels = append(labels, strings.Trim(strings.TrimSpace(label), "'"))
+	}
+	return labels
+}
+
+// validateField checks value against the validate tag of field of v.
+func validateField(t *testing.T, v any, field string, value any) error {
+	t.Helper()
+	f, ok := reflect.TypeOf(v).FieldByName(field)
+	if !ok {
+		t.Fatalf("Expected a %s field", field)
+	}
+	return validator.New().Var(value, f.Tag.Get("validate"))
+}
+
+func TestCustomRecurrenceRoundTrip(t *testing.T) {
+	units := []RecurrenceUnit{RecurrenceUnitDays, RecurrenceUnitWeeks, RecurrenceUnitMonths, RecurrenceUnitYears}
+	var names []string
+	for _, unit := range units {
+		names = append(names, string(unit))
+	}
+	if labels := schemaEnum(t, "recurrence_unit"); !slices.Equal(labels, names) {
+		t.Errorf("Expected the recurrence_unit enum to be %v, got %v", names, labels)
+	}
+
+	due := time.Date(2026, time.January, 31, 23, 59, 59, 0, time.UTC)
+	expected := map[RecurrenceUnit]time.Time{
+		RecurrenceUnitDays:   due.AddDate(0, 0, 2),
+		RecurrenceUnitWeeks:  due.AddDate(0, 0, 14),
+		RecurrenceUnitMonths: time.Date(2026, time.March, 31, 23, 59, 59, 0, time.UTC),
+		RecurrenceUnitYears:  due.AddDate(2, 0, 0),
+	}
+
+	for _, unit := range units {
+		t.Run(string(unit), func(t *testing.T) {
+			request := TaskRequest{
+				Title:                   "Replace filters",
+				IsRecurring:             true,
+				RecurrenceType:          string(RecurrentTypeCustom),
+				RecurrenceInterval:      2,
+				RecurrenceUnit:          string(unit),
+				EstimatedCompletionDate: due.Format(time.DateOnly),
+			}
+			for _, v := range []any{request, TaskPatch{}} {
+				if err := validateField(t, v, "RecurrenceUnit", request.RecurrenceUnit); err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+			}
+
+			task, err := request.ToDomain(time.UTC)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if err := task.Validate(); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if task.RecurrenceUnit.String != string(unit) {
+				t.Errorf("Expected %q, got %q", unit, task.RecurrenceUnit.String)
+			}
+
+			occurrences := task.Occurrences(due.Add(time.Second), due.AddDate(3, 0, 0), time.UTC)
+			if len(occurrences) == 0 || !occurrences[0].Due.Equal(expected[unit]) {
+				t.Errorf("Expected the next occurrence on %v, got %v", expected[unit], occurrences)
+			}
+		})
+	}
+
+	if err := validateField(t, TaskRequest{}, "RecurrenceUnit", "Day"); err == nil {
+		t.Errorf("Expected %q to be rejected", "Day")
+	}
+}
@@ -1,6 +1,10 @@
 package domain
 
-import "database/sql"
+import (
+	"database/sql"
+	"log/slog"
+	"time"
+)
 
 type UserRole string
 
@@ -10,24 +14,61 @@ const (
 )
 
 type User struct {
-	ID           int64        `db:"id"`
-	FirstName    string       `db:"first_name"`
-	LastName     string       `db:"last_name"`
-	Email        string       `db:"email"`
-	PasswordHash string       `db:"password_hash"`
-	Role         UserRole     `db:"role"`
-	CreatedAt    sql.NullTime `db:"created_at"`
+	ID               int64        `db:"id"`
+	FirstName        string       `db:"first_name"`
+	LastName         string       `db:"last_name"`
+	Email            string       `db:"email"`
+	PasswordHash     string       `db:"password_hash"`
+	Role             UserRole     `db:"role"`
+	EmailVerifiedAt  sql.NullTime `db:"email_verified_at"`
+	FailedLoginCount int          `db:"failed_login_count"`
+	LockedUntil      sql.NullTime `db:"locked_until"`
+	// TOTPSecret is set once enrollment starts, TOTPEnabledAt once the
+	// first code has been confirmed.
+	TOTPSecret      sql.NullString `db:"totp_secret"`
+	TOTPEnabledAt   sql.NullTime   `db:"totp_enabled_at"`
+	TOTPLastCounter int64          `db:"totp_last_counter"`
+	// Locale is the language the user picked; without one it follows the
+	// Accept-Language header.
+	Locale sql.NullString `db:"locale"`
+	// TimeZone is the IANA name of the time zone the user picked; without
+	// one it is the time zone of the organization.
+	TimeZone  sql.NullString `db:"time_zone"`
+	CreatedAt sql.NullTime   `db:"created_at"`
 }
 
 func (u *User) IsAdmin() bool {
 	return u.Role == RoleAdmin
 }
 
+func (u *User) IsLocked(now time.Time) bool {
+	return u.LockedUntil.Valid && now.Before(u.LockedUntil.Time)
+}
+
+func (u *User) HasTwoFactor() bool {
+	return u.TOTPEnabledAt.Valid && u.TOTPSecret.Valid
+}
+
+func (u *User) IsEmailVerified() bool {
+	return u.EmailVerifiedAt.Valid
+}
+
+// LogValue keeps the password hash and TOTP secret out of structured logs.
+func (u *User) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Int64("id", u.ID),
+		slog.String("email", u.Email),
+		slog.String("role", string(u.Role)),
+	)
+}
+
 type UserRequest struct {
 	FirstName string `form:"first_name" validate:"required"`
 	LastName  string `form:"last_name" validate:"required"`
 	Email     string `form:"email" validate:"required,email"`
-	Password  string `form:"password" validate:"required"`
+	Password  string `form:"password" validate:"required,min=8,max=72"`
+	// InvitationToken is required while registration is invite-only.
+	InvitationToken string `form:"invitation_token"`
 }
 
 func (ur *UserRequest) ToDomain() *User {
@@ -38,3 +79,8 @@ func (ur *UserRequest) ToDomain() *User {
 		Role:      RoleUser,
 	}
 }
+
+type PreferencesRequest struct {
+	Locale   string `json:"locale" form:"locale" validate:"omitempty,oneof=en es"`
+	TimeZone string `json:"time_zone" form:"time_zone" validate:"omitempty,timezone"`
+}
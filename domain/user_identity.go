@@ -0,0 +1,18 @@
+package domain
+
+import (
+	"database/sql"
+	"time"
+)
+
+// UserIdentity links a user to an account at a single sign-on provider.
+// Subject is the provider's stable identifier for that account.
+type UserIdentity struct {
+	ID          int64        `db:"id"`
+	UserID      int64        `db:"user_id"`
+	Provider    string       `db:"provider"`
+	Subject     string       `db:"subject"`
+	Email       string       `db:"email"`
+	LastLoginAt sql.NullTime `db:"last_login_at"`
+	CreatedAt   time.Time    `db:"created_at"`
+}
@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"database/sql"
+	"time"
+)
+
+type TokenPurpose string
+
+const (
+	PurposeEmailVerification TokenPurpose = "email_verification"
+	PurposePasswordReset     TokenPurpose = "password_reset"
+)
+
+// TTL is how long a link for the purpose stays valid.
+func (p TokenPurpose) TTL() time.Duration {
+	switch p {
+	case PurposePasswordReset:
+		return time.Hour
+	default:
+		return 48 * time.Hour
+	}
+}
+
+// UserToken backs the single-use links sent by email. Only the hash of the
+// token in the link is stored.
+type UserToken struct {
+	ID        int64        `db:"id"`
+	UserID    int64        `db:"user_id"`
+	Purpose   TokenPurpose `db:"purpose"`
+	TokenHash string       `db:"token_hash"`
+	ExpiresAt time.Time    `db:"expires_at"`
+	UsedAt    sql.NullTime `db:"used_at"`
+	CreatedAt time.Time    `db:"created_at"`
+}
+
+type ForgotPasswordRequest struct {
+	Email string `form:"email" validate:"required,email"`
+}
+
+type ResetPasswordRequest struct {
+	Token                string `form:"token" validate:"required"`
+	Password             string `form:"password" validate:"required,min=8,max=72"`
+	PasswordConfirmation string `form:"password-confirmation" validate:"required,eqfield=Password"`
+}
@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"database/sql"
+	"slices"
+	"time"
+)
+
+// WebhookTestEvent is sent by the "send test event" button. Webhooks do not
+// subscribe to it.
+const WebhookTestEvent TaskEventKind = "webhook.test"
+
+// WebhookEvents are the task events a webhook can subscribe to.
+var WebhookEvents = []TaskEventKind{
+	TaskEventCreated,
+	TaskEventAssigned,
+	TaskEventStatusChanged,
+	TaskEventCommented,
+	TaskEventDueSoon,
+	TaskEventOverdue,
+}
+
+const (
+	// WebhookMaxAttempts is how often a delivery is tried before it is
+	// given up, with exponential backoff between WebhookRetryBase and
+	// WebhookRetryMax.
+	WebhookMaxAttempts = 8
+	WebhookRetryBase   = 30 * time.Second
+	WebhookRetryMax    = 6 * time.Hour
+	// WebhookLease is how long a claimed delivery is hidden from other
+	// senders so it is not sent twice.
+	WebhookLease = 2 * time.Minute
+)
+
+type Webhook struct {
+	ID        int64         `db:"id"`
+	Name      string        `db:"name"`
+	URL       string        `db:"url"`
+	Secret    string        `db:"secret"`
+	Events    []string      `db:"events"`
+	IsActive  bool          `db:"is_active"`
+	CreatedBy sql.NullInt64 `db:"created_by"`
+	CreatedAt time.Time     `db:"created_at"`
+	UpdatedAt time.Time     `db:"updated_at"`
+}
+
+func (w *Webhook) Subscribes(kind TaskEventKind) bool {
+	return slices.Contains(w.Events, string(kind))
+}
+
+type WebhookRequest struct {
+	Name string `form:"name" validate:"required,max=100"`
+	URL  string `form:"url" validate:"required,http_url,max=2000"`
+	// Secret is generated when left empty on create and kept when left
+	// empty on update.
+	Secret   string   `form:"secret" validate:"omitempty,min=16,max=200"`
+	Events   []string `form:"events" validate:"required,min=1,dive,oneof=task.created task.assigned task.status_changed comment.created task.due_soon task.overdue"`
+	IsActive bool     `form:"is_active"`
+}
+
+func (r *WebhookRequest) ToDomain() *Webhook {
+	return &Webhook{
+		Name:     r.Name,
+		URL:      r.URL,
+		Secret:   r.Secret,
+		Events:   r.Events,
+		IsActive: r.IsActive,
+	}
+}
+
+// WebhookDelivery is one event sent, or waiting to be sent, to a webhook.
+type WebhookDelivery struct {
+	ID             int64          `db:"id"`
+	WebhookID      int64          `db:"webhook_id"`
+	Event          TaskEventKind  `db:"event"`
+	Payload        []byte         `db:"payload"`
+	Attempts       int            `db:"attempts"`
+	NextAttemptAt  time.Time      `db:"next_attempt_at"`
+	ResponseStatus sql.NullInt32  `db:"response_status"`
+	ResponseBody   sql.NullString `db:"response_body"`
+	LastError      sql.NullString `db:"last_error"`
+	DeliveredAt    sql.NullTime   `db:"delivered_at"`
+	FailedAt       sql.NullTime   `db:"failed_at"`
+	CreatedAt      time.Time      `db:"created_at"`
+}
+
+func (d *WebhookDelivery) IsPending() bool {
+	return !d.DeliveredAt.Valid && !d.FailedAt.Valid
+}
@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/mjmarrazzo/maintenance-app/auth"
+	"github.com/mjmarrazzo/maintenance-app/components/admin_views"
+	"github.com/mjmarrazzo/maintenance-app/domain"
+	"github.com/mjmarrazzo/maintenance-app/internal/api"
+	"github.com/mjmarrazzo/maintenance-app/internal/database"
+	"github.com/mjmarrazzo/maintenance-app/internal/mail"
+	"github.com/mjmarrazzo/maintenance-app/internal/validation"
+	"github.com/mjmarrazzo/maintenance-app/service"
+)
+
+type AdminHandler interface {
+	api.Handler
+	GetInvitations(c echo.Context) error
+	CreateInvitation(c echo.Context) error
+	RevokeInvitation(c echo.Context) error
+	GetSettings(c echo.Context) error
+	UpdateSettings(c echo.Context) error
+	GetLockedAccounts(c echo.Context) error
+	UnlockAccount(c echo.Context) error
+}
+
+type adminHandler struct {
+	invitationService service.InvitationService
+	settingService    service.SettingService
+	userService       service.UserService
+}
+
+func (h *adminHandler) RegisterRoutes(e *echo.Echo) {
+	group := e.Group("/admin")
+	group.Use(auth.AuthenticatedMiddleware(), auth.RequireRole(domain.RoleAdmin))
+
+	group.GET("/invitations", h.GetInvitations)
+	group.POST("/invitations", h.CreateInvitation)
+	group.DELETE("/invitations/:id", h.RevokeInvitation)
+	group.GET("/settings", h.GetSettings)
+	group.PUT("/settings", h.UpdateSettings)
+	group.GET("/locked-accounts", h.GetLockedAccounts)
+	group.DELETE("/locked-accounts/:id", h.UnlockAccount)
+}
+
+func NewAdminHandler(db *database.Client, mailer mail.Sender) AdminHandler {
+	return &adminHandler{
+		invitationService: service.NewInvitationService(db.Pool(), mailer, api.BaseURL()),
+		settingService:    service.NewSettingService(db.Pool()),
+		userService:       service.NewUserService(db.Pool()),
+	}
+}
+
+func (h *adminHandler) GetInvitations(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	invitations, err := h.invitationService.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	mode, err := h.settingService.GetRegistrationMode(ctx)
+	if err != nil {
+		return err
+	}
+
+	page := admin_views.Invitations(admin_views.InvitationsProps{
+		Invitations:      invitations,
+		RegistrationMode: mode,
+		Now:              time.Now(),
+	})
+	return api.Render(c, http.StatusOK, page)
+}
+
+func (h *adminHandler) CreateInvitation(c echo.Context) error {
+	authCtx, err := auth.GetAuthContext(c)
+	if err != nil {
+		return err
+	}
+
+	var invitationRequest domain.InvitationRequest
+	if err := validation.BindBody(c, &invitationRequest); err != nil {
+		return err
+	}
+
+	if _, err := h.invitationService.Create(c.Request().Context(), authCtx.User, &invitationRequest); err != nil {
+		return err
+	}
+
+	c.Response().Header().Set("Hx-Refresh", "true")
+	return c.NoContent(http.StatusCreated)
+}
+
+type InvitationIDParam struct {
+	ID int64 `param:"id" validate:"required,gt=0"`
+}
+
+func (h *adminHandler) RevokeInvitation(c echo.Context) error {
+	var params InvitationIDParam
+	if err := validation.BindPathParams(c, &params); err != nil {
+		return err
+	}
+
+	if err := h.invitationService.Revoke(c.Request().Context(), params.ID); err != nil {
+		return err
+	}
+
+	c.Response().Header().Set("Hx-Refresh", "true")
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *adminHandler) GetSettings(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	mode, err := h.settingService.GetRegistrationMode(ctx)
+	if err != nil {
+		return err
+	}
+
+	requireTwoFactor, err := h.settingService.RequiresAdminTwoFactor(ctx)
+	if err != nil {
+		return err
+	}
+
+	timeZone, err := h.settingService.GetTimeZone(ctx)
+	if err != nil {
+		return err
+	}
+
+	wipLimits, err := h.settingService.GetWIPLimits(ctx)
+	if err != nil {
+		return err
+	}
+
+	page := admin_views.Settings(admin_views.SettingsProps{
+		RegistrationMode:      mode,
+		RequireAdminTwoFactor: requireTwoFactor,
+		TimeZone:              timeZone.String(),
+		WIPLimits:             wipLimits,
+	})
+	return api.Render(c, http.StatusOK, page)
+}
+
+func (h *adminHandler) UpdateSettings(c echo.Context) error {
+	var settingsRequest domain.SettingsRequest
+	if err := validation.BindBody(c, &settingsRequest); err != nil {
+		return err
+	}
+
+	if err := h.settingService.Update(c.Request().Context(), &settingsRequest); err != nil {
+		return err
+	}
+
+	c.Response().Header().Set("Hx-Refresh", "true")
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *adminHandler) GetLockedAccounts(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	users, err := h.userService.GetLocked(ctx)
+	if err != nil {
+		return err
+	}
+
+	attempts, err := h.userService.GetRecentFailedLogins(ctx)
+	if err != nil {
+		return err
+	}
+
+	page := admin_views.LockedAccounts(admin_views.LockedAccountsProps{
+		Users:    users,
+		Attempts: attempts,
+	})
+	return api.Render(c, http.StatusOK, page)
+}
+
+type UserIDParam struct {
+	ID int64 `param:"id" validate:"required,gt=0"`
+}
+
+func (h *adminHandler) UnlockAccount(c echo.Context) error {
+	var params UserIDParam
+	if err := validation.BindPathParams(c, &params); err != nil {
+		return err
+	}
+
+	if err := h.userService.Unlock(c.Request().Context(), params.ID); err != nil {
+		return err
+	}
+
+	c.Response().Header().Set("Hx-Refresh", "true")
+	return c.NoContent(http.StatusNoContent)
+}
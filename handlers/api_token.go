@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/mjmarrazzo/maintenance-app/auth"
+	"github.com/mjmarrazzo/maintenance-app/components/token_views"
+	"github.com/mjmarrazzo/maintenance-app/domain"
+	"github.com/mjmarrazzo/maintenance-app/internal/api"
+	"github.com/mjmarrazzo/maintenance-app/internal/database"
+	"github.com/mjmarrazzo/maintenance-app/internal/validation"
+	"github.com/mjmarrazzo/maintenance-app/service"
+)
+
+type APITokenHandler interface {
+	api.Handler
+	GetAllTokens(c echo.Context) error
+	Create(c echo.Context) error
+	Revoke(c echo.Context) error
+}
+
+type apiTokenHandler struct {
+	service service.APITokenService
+}
+
+func (h *apiTokenHandler) RegisterRoutes(e *echo.Echo) {
+	group := e.Group("/settings/tokens")
+	group.Use(auth.AuthenticatedMiddleware())
+
+	group.GET("", h.GetAllTokens)
+	group.POST("", h.Create)
+	group.DELETE("/:id", h.Revoke)
+}
+
+func NewAPITokenHandler(db *database.Client) APITokenHandler {
+	return &apiTokenHandler{service: service.NewAPITokenService(db.Pool())}
+}
+
+func (h *apiTokenHandler) GetAllTokens(c echo.Context) error {
+	props, err := h.listProps(c)
+	if err != nil {
+		return err
+	}
+
+	return api.Render(c, http.StatusOK, token_views.List(*props))
+}
+
+func (h *apiTokenHandler) Create(c echo.Context) error {
+	authCtx, err := auth.GetAuthContext(c)
+	if err != nil {
+		return err
+	}
+
+	var tokenRequest domain.APITokenRequest
+	if err := validation.BindBody(c, &tokenRequest); err != nil {
+		return err
+	}
+
+	token, plaintext, err := h.service.Create(c.Request().Context(), authCtx.User.ID, &tokenRequest)
+	if err != nil {
+		return err
+	}
+
+	props, err := h.listProps(c)
+	if err != nil {
+		return err
+	}
+
+	return api.Render(c, http.StatusCreated, token_views.Created(token, plaintext, *props))
+}
+
+type APITokenIDParam struct {
+	ID int64 `param:"id" validate:"required,gt=0"`
+}
+
+func (h *apiTokenHandler) Revoke(c echo.Context) error {
+	authCtx, err := auth.GetAuthContext(c)
+	if err != nil {
+		return err
+	}
+
+	var params APITokenIDParam
+	if err := validation.BindPathParams(c, &params); err != nil {
+		return err
+	}
+
+	if err := h.service.Revoke(c.Request().Context(), authCtx.User.ID, params.ID); err != nil {
+		return err
+	}
+
+	c.Response().Header().Set("Hx-Refresh", "true")
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *apiTokenHandler) listProps(c echo.Context) (*token_views.ListProps, error) {
+	authCtx, err := auth.GetAuthContext(c)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens, err := h.service.GetByUserID(c.Request().Context(), authCtx.User.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &token_views.ListProps{Tokens: tokens, Now: time.Now()}, nil
+}
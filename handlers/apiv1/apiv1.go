@@ -0,0 +1,57 @@
+package apiv1
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/mjmarrazzo/maintenance-app/auth"
+	"github.com/mjmarrazzo/maintenance-app/domain"
+	"github.com/mjmarrazzo/maintenance-app/internal/api"
+	"github.com/mjmarrazzo/maintenance-app/internal/database"
+	"github.com/mjmarrazzo/maintenance-app/internal/storage"
+	"github.com/mjmarrazzo/maintenance-app/service"
+)
+
+const BasePath = "/api/v1"
+
+type router struct {
+	tokens   service.APITokenService
+	handlers []api.GroupHandler
+}
+
+// NewRouter mounts the versioned JSON API. It reuses the same services as the
+// htmx handlers; only the transport differs.
+func NewRouter(db *database.Client, store storage.Store) api.Handler {
+	return &router{
+		tokens: service.NewAPITokenService(db.Pool()),
+		handlers: []api.GroupHandler{
+			NewTaskHandler(db),
+			NewCommentHandler(db),
+			NewAttachmentHandler(db, store),
+			NewLocationHandler(db),
+			NewCategoryHandler(db),
+			NewUserHandler(db),
+		},
+	}
+}
+
+func (r *router) RegisterRoutes(e *echo.Echo) {
+	e.GET(SpecPath, serveSpec)
+
+	group := e.Group(BasePath)
+	group.Use(auth.BearerTokenMiddleware(r.tokens), auth.AuthenticatedMiddleware())
+
+	for _, h := range r.handlers {
+		h.RegisterRoutes(group)
+	}
+}
+
+type IDParam struct {
+	ID int64 `param:"id" validate:"required,gt=0"`
+}
+
+func currentUser(c echo.Context) (*domain.User, error) {
+	authCtx, err := auth.GetAuthContext(c)
+	if err != nil {
+		return nil, err
+	}
+	return authCtx.User, nil
+}
@@ -0,0 +1,166 @@
+package apiv1
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/mjmarrazzo/maintenance-app/internal/api"
+	"github.com/mjmarrazzo/maintenance-app/internal/database"
+	"github.com/mjmarrazzo/maintenance-app/internal/responses"
+	"github.com/mjmarrazzo/maintenance-app/internal/storage"
+	"github.com/mjmarrazzo/maintenance-app/internal/validation"
+	"github.com/mjmarrazzo/maintenance-app/service"
+)
+
+const maxAttachmentSize = "25M"
+
+type AttachmentHandler interface {
+	api.GroupHandler
+	List(c echo.Context) error
+	Upload(c echo.Context) error
+	Get(c echo.Context) error
+	Download(c echo.Context) error
+	Delete(c echo.Context) error
+}
+
+type attachmentHandler struct {
+	service service.AttachmentService
+}
+
+func NewAttachmentHandler(db *database.Client, store storage.Store) AttachmentHandler {
+	return &attachmentHandler{service: service.NewAttachmentService(db.Pool(), store)}
+}
+
+func (h *attachmentHandler) RegisterRoutes(g *echo.Group) {
+	g.GET("/tasks/:id/attachments", h.List)
+	g.POST("/tasks/:id/attachments", h.Upload, middleware.BodyLimit(maxAttachmentSize))
+	g.GET("/attachments/:id", h.Get)
+	g.GET("/attachments/:id/content", h.Download)
+	g.DELETE("/attachments/:id", h.Delete)
+}
+
+func (h *attachmentHandler) List(c echo.Context) error {
+	var params IDParam
+	if err := validation.BindPathParams(c, &params); err != nil {
+		return err
+	}
+
+	attachments, err := h.service.GetByTaskID(c.Request().Context(), params.ID)
+	if err != nil {
+		return err
+	}
+
+	resources := mapResources(attachments, NewAttachmentResource)
+	return c.JSON(http.StatusOK, responses.NewListEnvelope(resources, 0, 0))
+}
+
+func (h *attachmentHandler) Upload(c echo.Context) error {
+	var params IDParam
+	if err := validation.BindPathParams(c, &params); err != nil {
+		return err
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return responses.NewValidationError("Validation failed", []string{"file"}, []*responses.ViolationsDetail{
+			{Name: "file", Message: "This field is required"},
+		})
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	mimeType, err := detectMimeType(file, fileHeader.Header.Get(echo.HeaderContentType))
+	if err != nil {
+		return err
+	}
+
+	user, err := currentUser(c)
+	if err != nil {
+		return err
+	}
+
+	filename := filepath.Base(fileHeader.Filename)
+	attachment, err := h.service.Upload(c.Request().Context(), user.ID, params.ID, filename, mimeType, file)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, responses.NewEnvelope(NewAttachmentResource(attachment)))
+}
+
+// detectMimeType prefers the declared content type and sniffs the content
+// when the client did not send a usable one.
+func detectMimeType(file io.ReadSeeker, declared string) (string, error) {
+	if mediaType, _, err := mime.ParseMediaType(declared); err == nil && mediaType != "application/octet-stream" {
+		return mediaType, nil
+	}
+
+	buf := make([]byte, 512)
+	n, err := file.Read(buf)
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	return http.DetectContentType(buf[:n]), nil
+}
+
+func (h *attachmentHandler) Get(c echo.Context) error {
+	var params IDParam
+	if err := validation.BindPathParams(c, &params); err != nil {
+		return err
+	}
+
+	attachment, err := h.service.GetByID(c.Request().Context(), params.ID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, responses.NewEnvelope(NewAttachmentResource(attachment)))
+}
+
+func (h *attachmentHandler) Download(c echo.Context) error {
+	var params IDParam
+	if err := validation.BindPathParams(c, &params); err != nil {
+		return err
+	}
+
+	attachment, content, err := h.service.Open(c.Request().Context(), params.ID)
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+
+	c.Response().Header().Set(echo.HeaderContentDisposition,
+		mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename}))
+	c.Response().Header().Set(echo.HeaderContentLength, fmt.Sprintf("%d", attachment.SizeBytes))
+	return c.Stream(http.StatusOK, attachment.MimeType, content)
+}
+
+func (h *attachmentHandler) Delete(c echo.Context) error {
+	var params IDParam
+	if err := validation.BindPathParams(c, &params); err != nil {
+		return err
+	}
+
+	user, err := currentUser(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.service.Delete(c.Request().Context(), user, params.ID); err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,108 @@
+package apiv1
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/mjmarrazzo/maintenance-app/domain"
+	"github.com/mjmarrazzo/maintenance-app/internal/api"
+	"github.com/mjmarrazzo/maintenance-app/internal/database"
+	"github.com/mjmarrazzo/maintenance-app/internal/responses"
+	"github.com/mjmarrazzo/maintenance-app/internal/validation"
+	"github.com/mjmarrazzo/maintenance-app/service"
+)
+
+type CategoryHandler interface {
+	api.GroupHandler
+	List(c echo.Context) error
+	Create(c echo.Context) error
+	Get(c echo.Context) error
+	Update(c echo.Context) error
+	Delete(c echo.Context) error
+}
+
+type categoryHandler struct {
+	service service.CategoryService
+}
+
+func NewCategoryHandler(db *database.Client) CategoryHandler {
+	return &categoryHandler{service: service.NewCategoryService(db.Pool())}
+}
+
+func (h *categoryHandler) RegisterRoutes(g *echo.Group) {
+	g.GET("/categories", h.List)
+	g.POST("/categories", h.Create)
+	g.GET("/categories/:id", h.Get)
+	g.PUT("/categories/:id", h.Update)
+	g.DELETE("/categories/:id", h.Delete)
+}
+
+func (h *categoryHandler) List(c echo.Context) error {
+	categories, err := h.service.GetAll(c.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	resources := mapResources(categories, NewCategoryResource)
+	return c.JSON(http.StatusOK, responses.NewListEnvelope(resources, 0, 0))
+}
+
+func (h *categoryHandler) Create(c echo.Context) error {
+	var categoryRequest domain.CategoryRequest
+	if err := validation.BindBody(c, &categoryRequest); err != nil {
+		return err
+	}
+
+	category, err := h.service.Create(c.Request().Context(), &categoryRequest)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, responses.NewEnvelope(NewCategoryResource(category)))
+}
+
+func (h *categoryHandler) Get(c echo.Context) error {
+	var params IDParam
+	if err := validation.BindPathParams(c, &params); err != nil {
+		return err
+	}
+
+	category, err := h.service.GetByID(c.Request().Context(), params.ID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, responses.NewEnvelope(NewCategoryResource(category)))
+}
+
+func (h *categoryHandler) Update(c echo.Context) error {
+	var params IDParam
+	if err := validation.BindPathParams(c, &params); err != nil {
+		return err
+	}
+
+	var categoryRequest domain.CategoryRequest
+	if err := validation.BindBody(c, &categoryRequest); err != nil {
+		return err
+	}
+
+	category, err := h.service.Update(c.Request().Context(), params.ID, &categoryRequest)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, responses.NewEnvelope(NewCategoryResource(category)))
+}
+
+func (h *categoryHandler) Delete(c echo.Context) error {
+	var params IDParam
+	if err := validation.BindPathParams(c, &params); err != nil {
+		return err
+	}
+
+	if err := h.service.Delete(c.Request().Context(), params.ID); err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
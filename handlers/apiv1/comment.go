@@ -0,0 +1,91 @@
+package apiv1
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/mjmarrazzo/maintenance-app/domain"
+	"github.com/mjmarrazzo/maintenance-app/internal/api"
+	"github.com/mjmarrazzo/maintenance-app/internal/database"
+	"github.com/mjmarrazzo/maintenance-app/internal/responses"
+	"github.com/mjmarrazzo/maintenance-app/internal/validation"
+	"github.com/mjmarrazzo/maintenance-app/service"
+)
+
+type CommentHandler interface {
+	api.GroupHandler
+	List(c echo.Context) error
+	Create(c echo.Context) error
+	Delete(c echo.Context) error
+}
+
+type commentHandler struct {
+	service service.CommentService
+}
+
+func NewCommentHandler(db *database.Client) CommentHandler {
+	return &commentHandler{service: service.NewCommentService(db.Pool())}
+}
+
+func (h *commentHandler) RegisterRoutes(g *echo.Group) {
+	g.GET("/tasks/:id/comments", h.List)
+	g.POST("/tasks/:id/comments", h.Create)
+	g.DELETE("/comments/:id", h.Delete)
+}
+
+func (h *commentHandler) List(c echo.Context) error {
+	var params IDParam
+	if err := validation.BindPathParams(c, &params); err != nil {
+		return err
+	}
+
+	comments, err := h.service.GetByTaskID(c.Request().Context(), params.ID)
+	if err != nil {
+		return err
+	}
+
+	resources := mapResources(comments, NewCommentResource)
+	return c.JSON(http.StatusOK, responses.NewListEnvelope(resources, 0, 0))
+}
+
+func (h *commentHandler) Create(c echo.Context) error {
+	var params IDParam
+	if err := validation.BindPathParams(c, &params); err != nil {
+		return err
+	}
+
+	var commentRequest domain.CommentRequest
+	if err := validation.BindBody(c, &commentRequest); err != nil {
+		return err
+	}
+
+	user, err := currentUser(c)
+	if err != nil {
+		return err
+	}
+
+	comment, err := h.service.Create(c.Request().Context(), user.ID, params.ID, &commentRequest)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, responses.NewEnvelope(NewCommentResource(comment)))
+}
+
+func (h *commentHandler) Delete(c echo.Context) error {
+	var params IDParam
+	if err := validation.BindPathParams(c, &params); err != nil {
+		return err
+	}
+
+	user, err := currentUser(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.service.Delete(c.Request().Context(), user, params.ID); err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
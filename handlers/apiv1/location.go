@@ -0,0 +1,108 @@
+package apiv1
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/mjmarrazzo/maintenance-app/domain"
+	"github.com/mjmarrazzo/maintenance-app/internal/api"
+	"github.com/mjmarrazzo/maintenance-app/internal/database"
+	"github.com/mjmarrazzo/maintenance-app/internal/responses"
+	"github.com/mjmarrazzo/maintenance-app/internal/validation"
+	"github.com/mjmarrazzo/maintenance-app/service"
+)
+
+type LocationHandler interface {
+	api.GroupHandler
+	List(c echo.Context) error
+	Create(c echo.Context) error
+	Get(c echo.Context) error
+	Update(c echo.Context) error
+	Delete(c echo.Context) error
+}
+
+type locationHandler struct {
+	service service.LocationService
+}
+
+func NewLocationHandler(db *database.Client) LocationHandler {
+	return &locationHandler{service: service.NewLocationService(db.Pool())}
+}
+
+func (h *locationHandler) RegisterRoutes(g *echo.Group) {
+	g.GET("/locations", h.List)
+	g.POST("/locations", h.Create)
+	g.GET("/locations/:id", h.Get)
+	g.PUT("/locations/:id", h.Update)
+	g.DELETE("/locations/:id", h.Delete)
+}
+
+func (h *locationHandler) List(c echo.Context) error {
+	locations, err := h.service.GetAll(c.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	resources := mapResources(locations, NewLocationResource)
+	return c.JSON(http.StatusOK, responses.NewListEnvelope(resources, 0, 0))
+}
+
+func (h *locationHandler) Create(c echo.Context) error {
+	var locationRequest domain.LocationRequest
+	if err := validation.BindBody(c, &locationRequest); err != nil {
+		return err
+	}
+
+	location, err := h.service.Create(c.Request().Context(), &locationRequest)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, responses.NewEnvelope(NewLocationResource(location)))
+}
+
+func (h *locationHandler) Get(c echo.Context) error {
+	var params IDParam
+	if err := validation.BindPathParams(c, &params); err != nil {
+		return err
+	}
+
+	location, err := h.service.GetByID(c.Request().Context(), params.ID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, responses.NewEnvelope(NewLocationResource(location)))
+}
+
+func (h *locationHandler) Update(c echo.Context) error {
+	var params IDParam
+	if err := validation.BindPathParams(c, &params); err != nil {
+		return err
+	}
+
+	var locationRequest domain.LocationRequest
+	if err := validation.BindBody(c, &locationRequest); err != nil {
+		return err
+	}
+
+	location, err := h.service.Update(c.Request().Context(), params.ID, &locationRequest)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, responses.NewEnvelope(NewLocationResource(location)))
+}
+
+func (h *locationHandler) Delete(c echo.Context) error {
+	var params IDParam
+	if err := validation.BindPathParams(c, &params); err != nil {
+		return err
+	}
+
+	if err := h.service.Delete(c.Request().Context(), params.ID); err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,198 @@
+package apiv1
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/labstack/echo/v4"
+	"github.com/mjmarrazzo/maintenance-app/domain"
+	"github.com/mjmarrazzo/maintenance-app/internal/openapi"
+	"github.com/mjmarrazzo/maintenance-app/internal/patch"
+	"github.com/mjmarrazzo/maintenance-app/internal/responses"
+)
+
+const SpecPath = "/api/openapi.json"
+
+// operation documents one route registered by the handlers in this package.
+// Request and response types are the same structs the handlers bind and
+// render, so their json and validate tags are the source of the schemas.
+type operation struct {
+	method   string
+	path     string
+	id       string
+	summary  string
+	tag      string
+	params   any
+	query    any
+	body     any
+	bodyType string
+	response any
+	list     bool
+	status   int
+}
+
+var operations = []operation{
+	{method: http.MethodGet, path: "/tasks", id: "listTasks", summary: "List tasks", tag: "Tasks", query: TaskListParams{}, response: TaskResource{}, list: true},
+	{method: http.MethodPost, path: "/tasks", id: "createTask", summary: "Create a task", tag: "Tasks", body: domain.TaskRequest{}, response: TaskResource{}, status: http.StatusCreated},
+	{method: http.MethodGet, path: "/tasks/:id", id: "getTask", summary: "Get a task", tag: "Tasks", params: IDParam{}, response: TaskResource{}},
+	{method: http.MethodPut, path: "/tasks/:id", id: "updateTask", summary: "Replace a task", tag: "Tasks", params: IDParam{}, body: domain.TaskRequest{}, response: TaskResource{}},
+	{method: http.MethodPatch, path: "/tasks/:id", id: "patchTask", summary: "Change some fields of a task", tag: "Tasks", params: IDParam{}, body: domain.TaskPatch{}, bodyType: patch.MIMEMergePatchJSON, response: TaskResource{}},
+	{method: http.MethodDelete, path: "/tasks/:id", id: "deleteTask", summary: "Delete a task", tag: "Tasks", params: IDParam{}, status: http.StatusNoContent},
+	{method: http.MethodPost, path: "/tasks/:id/status", id: "updateTaskStatus", summary: "Change the status of a task", tag: "Tasks", params: IDParam{}, body: StatusRequest{}, response: TaskResource{}},
+
+	{method: http.MethodGet, path: "/tasks/:id/comments", id: "listTaskComments", summary: "List the comments on a task", tag: "Comments", params: IDParam{}, response: CommentResource{}, list: true},
+	{method: http.MethodPost, path: "/tasks/:id/comments", id: "createTaskComment", summary: "Comment on a task", tag: "Comments", params: IDParam{}, body: domain.CommentRequest{}, response: CommentResource{}, status: http.StatusCreated},
+	{method: http.MethodDelete, path: "/comments/:id", id: "deleteComment", summary: "Delete a comment", tag: "Comments", params: IDParam{}, status: http.StatusNoContent},
+
+	{method: http.MethodGet, path: "/tasks/:id/attachments", id: "listTaskAttachments", summary: "List the attachments of a task", tag: "Attachments", params: IDParam{}, response: AttachmentResource{}, list: true},
+	{method: http.MethodPost, path: "/tasks/:id/attachments", id: "uploadTaskAttachment", summary: "Upload an attachment", tag: "Attachments", params: IDParam{}, body: uploadSchema(), bodyType: echo.MIMEMultipartForm, response: AttachmentResource{}, status: http.StatusCreated},
+	{method: http.MethodGet, path: "/attachments/:id", id: "getAttachment", summary: "Get attachment metadata", tag: "Attachments", params: IDParam{}, response: AttachmentResource{}},
+	{method: http.MethodGet, path: "/attachments/:id/content", id: "downloadAttachment", summary: "Download attachment content", tag: "Attachments", params: IDParam{}, response: &openapi.Schema{Type: "string", Format: "binary"}},
+	{method: http.MethodDelete, path: "/attachments/:id", id: "deleteAttachment", summary: "Delete an attachment", tag: "Attachments", params: IDParam{}, status: http.StatusNoContent},
+
+	{method: http.MethodGet, path: "/locations", id: "listLocations", summary: "List locations", tag: "Locations", response: LocationResource{}, list: true},
+	{method: http.MethodPost, path: "/locations", id: "createLocation", summary: "Create a location", tag: "Locations", body: domain.LocationRequest{}, response: LocationResource{}, status: http.StatusCreated},
+	{method: http.MethodGet, path: "/locations/:id", id: "getLocation", summary: "Get a location", tag: "Locations", params: IDParam{}, response: LocationResource{}},
+	{method: http.MethodPut, path: "/locations/:id", id: "updateLocation", summary: "Replace a location", tag: "Locations", params: IDParam{}, body: domain.LocationRequest{}, response: LocationResource{}},
+	{method: http.MethodDelete, path: "/locations/:id", id: "deleteLocation", summary: "Delete a location", tag: "Locations", params: IDParam{}, status: http.StatusNoContent},
+
+	{method: http.MethodGet, path: "/categories", id: "listCategories", summary: "List categories", tag: "Categories", response: CategoryResource{}, list: true},
+	{method: http.MethodPost, path: "/categories", id: "createCategory", summary: "Create a category", tag: "Categories", body: domain.CategoryRequest{}, response: CategoryResource{}, status: http.StatusCreated},
+	{method: http.MethodGet, path: "/categories/:id", id: "getCategory", summary: "Get a category", tag: "Categories", params: IDParam{}, response: CategoryResource{}},
+	{method: http.MethodPut, path: "/categories/:id", id: "updateCategory", summary: "Replace a category", tag: "Categories", params: IDParam{}, body: domain.CategoryRequest{}, response: CategoryResource{}},
+	{method: http.MethodDelete, path: "/categories/:id", id: "deleteCategory", summary: "Delete a category", tag: "Categories", params: IDParam{}, status: http.StatusNoContent},
+
+	{method: http.MethodGet, path: "/users", id: "listUsers", summary: "List users", tag: "Users", response: UserResource{}, list: true},
+	{method: http.MethodGet, path: "/users/me", id: "getCurrentUser", summary: "Get the authenticated user", tag: "Users", response: UserResource{}},
+	{method: http.MethodGet, path: "/users/:id", id: "getUser", summary: "Get a user", tag: "Users", params: IDParam{}, response: UserResource{}},
+}
+
+func uploadSchema() *openapi.Schema {
+	return &openapi.Schema{
+		Type:     "object",
+		Required: []string{"file"},
+		Properties: map[string]*openapi.Schema{
+			"file": {Type: "string", Format: "binary"},
+		},
+	}
+}
+
+// Spec builds the OpenAPI document for the routes in this package.
+func Spec() *openapi.Document {
+	gen := openapi.NewGenerator()
+	doc := openapi.NewDocument("Maintenance App API", "1.0.0")
+	doc.Security = []openapi.SecurityRequirement{{"bearerToken": {}}, {"sessionCookie": {}}}
+
+	validationError := gen.Schema(responses.ValidationError{})
+	appError := gen.Schema(responses.AppError{})
+	meta := gen.Schema(responses.Meta{})
+
+	for _, o := range operations {
+		op := &openapi.Operation{
+			OperationID: o.id,
+			Summary:     o.summary,
+			Tags:        []string{o.tag},
+			Responses:   make(map[string]*openapi.Response),
+		}
+
+		if o.params != nil {
+			op.Parameters = append(op.Parameters, gen.Parameters(o.params, "param")...)
+		}
+		if o.query != nil {
+			op.Parameters = append(op.Parameters, gen.Parameters(o.query, "query")...)
+		}
+		if o.body != nil {
+			bodyType := o.bodyType
+			if bodyType == "" {
+				bodyType = echo.MIMEApplicationJSON
+			}
+			op.RequestBody = &openapi.RequestBody{
+				Required: true,
+				Content: map[string]*openapi.MediaType{
+					bodyType: {Schema: gen.Schema(o.body)},
+				},
+			}
+		}
+
+		status := o.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		op.Responses[strconv.Itoa(status)] = successResponse(gen, o, status, meta)
+
+		if o.params != nil || o.query != nil || o.body != nil {
+			op.Responses["400"] = &openapi.Response{
+				Description: "Invalid parameters or request body",
+				Content:     openapi.JSONContent(validationError),
+			}
+		}
+		op.Responses["default"] = &openapi.Response{
+			Description: "Error",
+			Content:     openapi.JSONContent(appError),
+		}
+
+		doc.AddOperation(o.method, BasePath+o.path, op)
+	}
+
+	doc.Components = &openapi.Components{
+		Schemas: gen.Schemas(),
+		SecuritySchemes: map[string]*openapi.SecurityScheme{
+			"bearerToken": {
+				Type:        "http",
+				Scheme:      "bearer",
+				Description: "Personal API token created under /settings/tokens. GET requests need the read scope, all others the write scope.",
+			},
+			"sessionCookie": {
+				Type:        "apiKey",
+				In:          "cookie",
+				Name:        "user-session",
+				Description: "Session cookie set by POST /login",
+			},
+		},
+	}
+
+	return doc
+}
+
+func successResponse(gen *openapi.Generator, o operation, status int, meta *openapi.Schema) *openapi.Response {
+	response := &openapi.Response{Description: http.StatusText(status)}
+	if o.response == nil {
+		return response
+	}
+
+	data := gen.Schema(o.response)
+	if data.Type == "string" && data.Format == "binary" {
+		response.Content = map[string]*openapi.MediaType{
+			echo.MIMEOctetStream: {Schema: data},
+		}
+		return response
+	}
+
+	envelope := &openapi.Schema{
+		Type:       "object",
+		Required:   []string{"data"},
+		Properties: map[string]*openapi.Schema{"data": data},
+	}
+	if o.list {
+		envelope.Required = append(envelope.Required, "meta")
+		envelope.Properties["data"] = &openapi.Schema{Type: "array", Items: data}
+		envelope.Properties["meta"] = meta
+	}
+	response.Content = openapi.JSONContent(envelope)
+
+	return response
+}
+
+var specOnce = sync.OnceValues(func() ([]byte, error) {
+	return json.Marshal(Spec())
+})
+
+func serveSpec(c echo.Context) error {
+	spec, err := specOnce()
+	if err != nil {
+		return err
+	}
+	return c.Blob(http.StatusOK, echo.MIMEApplicationJSON, spec)
+}
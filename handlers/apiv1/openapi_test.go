@@ -0,0 +1,61 @@
+package apiv1
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/mjmarrazzo/maintenance-app/internal/database"
+	"github.com/mjmarrazzo/maintenance-app/internal/openapi"
+)
+
+func TestSpecMatchesRoutes(t *testing.T) {
+	e := echo.New()
+	NewRouter(&database.Client{}, nil).RegisterRoutes(e)
+
+	routes := make(map[string]bool)
+	for _, r := range e.Routes() {
+		if r.Method == echo.RouteNotFound || !strings.HasPrefix(r.Path, BasePath+"/") {
+			continue
+		}
+		routes[strings.ToLower(r.Method)+" "+openapi.PathFromRoute(r.Path)] = true
+	}
+
+	documented := make(map[string]bool)
+	for path, item := range Spec().Paths {
+		for method := range *item {
+			documented[method+" "+path] = true
+		}
+	}
+
+	for route := range routes {
+		if !documented[route] {
+			t.Errorf("Expected route '%s' to be documented in the OpenAPI spec", route)
+		}
+	}
+
+	for route := range documented {
+		if !routes[route] {
+			t.Errorf("Expected documented operation '%s' to be a registered route", route)
+		}
+	}
+}
+
+func TestSpecIsServed(t *testing.T) {
+	e := echo.New()
+	NewRouter(&database.Client{}, nil).RegisterRoutes(e)
+
+	req := httptest.NewRequest(http.MethodGet, SpecPath, nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+
+	if !strings.Contains(rec.Body.String(), `"openapi":"3.1.0"`) {
+		t.Errorf("Expected an OpenAPI 3.1 document, got '%s'", rec.Body.String())
+	}
+}
@@ -0,0 +1,192 @@
+package apiv1
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/mjmarrazzo/maintenance-app/domain"
+)
+
+type TaskResource struct {
+	ID                      int64      `json:"id"`
+	Title                   string     `json:"title"`
+	Description             string     `json:"description"`
+	CategoryID              *int64     `json:"category_id"`
+	CategoryName            *string    `json:"category_name"`
+	LocationID              *int64     `json:"location_id"`
+	LocationName            *string    `json:"location_name"`
+	Priority                *string    `json:"priority"`
+	Status                  *string    `json:"status"`
+	CreatedBy               int64      `json:"created_by"`
+	AssignedTo              *int64     `json:"assigned_to"`
+	CreatedAt               time.Time  `json:"created_at"`
+	UpdatedAt               time.Time  `json:"updated_at"`
+	EstimatedCompletionDate *time.Time `json:"estimated_completion_date"`
+	Cost                    *float64   `json:"cost"`
+	IsRecurring             bool       `json:"is_recurring"`
+	RecurrenceType          *string    `json:"recurrence_type"`
+	RecurrenceInterval      int        `json:"recurrence_interval"`
+	RecurrenceUnit          *string    `json:"recurrence_unit"`
+	ParentTaskID            *int64     `json:"parent_task_id"`
+	NextOccurrence          *time.Time `json:"next_occurrence"`
+	CompletedAt             *time.Time `json:"completed_at"`
+}
+
+func NewTaskResource(t *domain.Task) *TaskResource {
+	return &TaskResource{
+		ID:                      t.ID,
+		Title:                   t.Title,
+		Description:             t.Description,
+		CategoryID:              nullInt64(t.CategoryID),
+		CategoryName:            nullString(t.CategoryName),
+		LocationID:              nullInt64(t.LocationID),
+		LocationName:            nullString(t.LocationName),
+		Priority:                nullString(t.Priority),
+		Status:                  nullString(t.Status),
+		CreatedBy:               t.CreatedBy,
+		AssignedTo:              nullInt64(t.AssignedTo),
+		CreatedAt:               t.CreatedAt,
+		UpdatedAt:               t.UpdatedAt,
+		EstimatedCompletionDate: nullTime(t.EstimatedCompletionDate),
+		Cost:                    nullFloat64(t.Cost),
+		IsRecurring:             t.IsRecurring,
+		RecurrenceType:          nullString(t.RecurrenceType),
+		RecurrenceInterval:      t.RecurrenceInterval,
+		RecurrenceUnit:          nullString(t.RecurrenceUnit),
+		ParentTaskID:            nullInt64(t.ParentTaskID),
+		NextOccurrence:          nullTime(t.NextOccurrence),
+		CompletedAt:             nullTime(t.CompletedAt),
+	}
+}
+
+type LocationResource struct {
+	ID                 int64   `json:"id"`
+	Name               string  `json:"name"`
+	Description        string  `json:"description"`
+	ParentLocationID   *int64  `json:"parent_location_id"`
+	ParentLocationName *string `json:"parent_location_name"`
+}
+
+func NewLocationResource(l *domain.Location) *LocationResource {
+	return &LocationResource{
+		ID:                 l.ID,
+		Name:               l.Name,
+		Description:        l.Description,
+		ParentLocationID:   nullInt64(l.ParentLocationId),
+		ParentLocationName: nullString(l.ParentLocationName),
+	}
+}
+
+type CategoryResource struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+func NewCategoryResource(c *domain.Category) *CategoryResource {
+	return &CategoryResource{
+		ID:          c.ID,
+		Name:        c.Name,
+		Description: c.Description,
+	}
+}
+
+type UserResource struct {
+	ID        int64      `json:"id"`
+	FirstName string     `json:"first_name"`
+	LastName  string     `json:"last_name"`
+	Email     string     `json:"email"`
+	Role      string     `json:"role"`
+	CreatedAt *time.Time `json:"created_at"`
+}
+
+func NewUserResource(u *domain.User) *UserResource {
+	return &UserResource{
+		ID:        u.ID,
+		FirstName: u.FirstName,
+		LastName:  u.LastName,
+		Email:     u.Email,
+		Role:      string(u.Role),
+		CreatedAt: nullTime(u.CreatedAt),
+	}
+}
+
+type CommentResource struct {
+	ID            int64     `json:"id"`
+	TaskID        int64     `json:"task_id"`
+	UserID        int64     `json:"user_id"`
+	UserFirstName *string   `json:"user_first_name"`
+	UserLastName  *string   `json:"user_last_name"`
+	Content       string    `json:"content"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+func NewCommentResource(c *domain.Comment) *CommentResource {
+	return &CommentResource{
+		ID:            c.ID,
+		TaskID:        c.TaskID,
+		UserID:        c.UserID,
+		UserFirstName: nullString(c.UserFirstName),
+		UserLastName:  nullString(c.UserLastName),
+		Content:       c.Content,
+		CreatedAt:     c.CreatedAt,
+	}
+}
+
+type AttachmentResource struct {
+	ID         int64     `json:"id"`
+	TaskID     int64     `json:"task_id"`
+	Filename   string    `json:"filename"`
+	MimeType   string    `json:"mime_type"`
+	SizeBytes  int64     `json:"size_bytes"`
+	UploadedBy int64     `json:"uploaded_by"`
+	UploadDate time.Time `json:"upload_date"`
+}
+
+func NewAttachmentResource(a *domain.Attachment) *AttachmentResource {
+	return &AttachmentResource{
+		ID:         a.ID,
+		TaskID:     a.TaskID,
+		Filename:   a.Filename,
+		MimeType:   a.MimeType,
+		SizeBytes:  a.SizeBytes,
+		UploadedBy: a.UploadedBy,
+		UploadDate: a.UploadDate,
+	}
+}
+
+func mapResources[T any, R any](items []T, fn func(T) R) []R {
+	resources := make([]R, 0, len(items))
+	for _, item := range items {
+		resources = append(resources, fn(item))
+	}
+	return resources
+}
+
+func nullInt64(v sql.NullInt64) *int64 {
+	if !v.Valid {
+		return nil
+	}
+	return &v.Int64
+}
+
+func nullString(v sql.NullString) *string {
+	if !v.Valid {
+		return nil
+	}
+	return &v.String
+}
+
+func nullFloat64(v sql.NullFloat64) *float64 {
+	if !v.Valid {
+		return nil
+	}
+	return &v.Float64
+}
+
+func nullTime(v sql.NullTime) *time.Time {
+	if !v.Valid {
+		return nil
+	}
+	return &v.Time
+}
@@ -0,0 +1,266 @@
+package apiv1
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/mjmarrazzo/maintenance-app/domain"
+	"github.com/mjmarrazzo/maintenance-app/internal/api"
+	"github.com/mjmarrazzo/maintenance-app/internal/database"
+	"github.com/mjmarrazzo/maintenance-app/internal/responses"
+	"github.com/mjmarrazzo/maintenance-app/internal/validation"
+	"github.com/mjmarrazzo/maintenance-app/repository"
+	"github.com/mjmarrazzo/maintenance-app/service"
+)
+
+const (
+	defaultTaskLimit = 50
+	dateLayout       = "2006-01-02"
+)
+
+type TaskHandler interface {
+	api.GroupHandler
+	List(c echo.Context) error
+	Create(c echo.Context) error
+	Get(c echo.Context) error
+	Update(c echo.Context) error
+	Patch(c echo.Context) error
+	Delete(c echo.Context) error
+	UpdateStatus(c echo.Context) error
+}
+
+type taskHandler struct {
+	service service.TaskService
+}
+
+func NewTaskHandler(db *database.Client) TaskHandler {
+	return &taskHandler{service: service.NewTaskService(db.Pool())}
+}
+
+func (h *taskHandler) RegisterRoutes(g *echo.Group) {
+	g.GET("/tasks", h.List)
+	g.POST("/tasks", h.Create)
+	g.GET("/tasks/:id", h.Get)
+	g.PUT("/tasks/:id", h.Update)
+	g.PATCH("/tasks/:id", h.Patch)
+	g.DELETE("/tasks/:id", h.Delete)
+	g.POST("/tasks/:id/status", h.UpdateStatus)
+}
+
+// TaskListParams exposes repository.TaskFilters as query parameters.
+type TaskListParams struct {
+	Status      string `query:"status" json:"status" validate:"omitempty,oneof=New 'In Progress' Completed 'On Hold'"`
+	Priority    string `query:"priority" json:"priority" validate:"omitempty,oneof=Low Medium High Urgent"`
+	CategoryID  int64  `query:"category_id" json:"category_id" validate:"omitempty,gt=0"`
+	LocationID  int64  `query:"location_id" json:"location_id" validate:"omitempty,gt=0"`
+	AssignedTo  int64  `query:"assigned_to" json:"assigned_to" validate:"omitempty,gt=0"`
+	CreatedBy   int64  `query:"created_by" json:"created_by" validate:"omitempty,gt=0"`
+	IsCompleted string `query:"is_completed" json:"is_completed" validate:"omitempty,boolean"`
+	IsRecurring string `query:"is_recurring" json:"is_recurring" validate:"omitempty,boolean"`
+	Query       string `query:"q" json:"q" validate:"omitempty,max=255"`
+	DateFrom    string `query:"date_from" json:"date_from" validate:"omitempty,datetime=2006-01-02"`
+	DateTo      string `query:"date_to" json:"date_to" validate:"omitempty,datetime=2006-01-02"`
+	Limit       int    `query:"limit" json:"limit" validate:"omitempty,gte=1,lte=200"`
+	Offset      int    `query:"offset" json:"offset" validate:"omitempty,gte=0"`
+	Sort        string `query:"sort" json:"sort" validate:"omitempty,oneof=id title priority status created_at updated_at estimated_completion_date"`
+	Order       string `query:"order" json:"order" validate:"omitempty,oneof=asc desc"`
+}
+
+func (p *TaskListParams) ToFilters() repository.TaskFilters {
+	filters := repository.TaskFilters{
+		SearchQuery: p.Query,
+		Limit:       p.Limit,
+		Offset:      p.Offset,
+		SortField:   p.Sort,
+		SortOrder:   p.Order,
+	}
+	if filters.Limit == 0 {
+		filters.Limit = defaultTaskLimit
+	}
+
+	if p.Status != "" {
+		status := domain.Status(p.Status)
+		filters.Status = &status
+	}
+	if p.Priority != "" {
+		priority := domain.Priority(p.Priority)
+		filters.Priority = &priority
+	}
+	if p.CategoryID != 0 {
+		filters.CategoryID = &p.CategoryID
+	}
+	if p.LocationID != 0 {
+		filters.LocationID = &p.LocationID
+	}
+	if p.AssignedTo != 0 {
+		filters.AssignedTo = &p.AssignedTo
+	}
+	if p.CreatedBy != 0 {
+		filters.CreatedBy = &p.CreatedBy
+	}
+	if p.IsCompleted != "" {
+		isCompleted := p.IsCompleted == "true" || p.IsCompleted == "1"
+		filters.IsCompleted = &isCompleted
+	}
+	if p.IsRecurring != "" {
+		isRecurring := p.IsRecurring == "true" || p.IsRecurring == "1"
+		filters.IsRecurring = &isRecurring
+	}
+	if from, err := time.Parse(dateLayout, p.DateFrom); err == nil {
+		filters.DateFrom = &from
+	}
+	if to, err := time.Parse(dateLayout, p.DateTo); err == nil {
+		to = to.Add(24*time.Hour - time.Nanosecond)
+		filters.DateTo = &to
+	}
+
+	return filters
+}
+
+func (h *taskHandler) List(c echo.Context) error {
+	var params TaskListParams
+	if err := validation.BindQueryParams(c, &params); err != nil {
+		return err
+	}
+
+	filters := params.ToFilters()
+	tasks, err := h.service.List(c.Request().Context(), filters)
+	if err != nil {
+		return err
+	}
+
+	resources := mapResources(tasks, NewTaskResource)
+	return c.JSON(http.StatusOK, responses.NewListEnvelope(resources, filters.Limit, filters.Offset))
+}
+
+func (h *taskHandler) Create(c echo.Context) error {
+	var taskRequest domain.TaskRequest
+	if err := validation.BindBody(c, &taskRequest); err != nil {
+		return err
+	}
+
+	user, err := currentUser(c)
+	if err != nil {
+		return err
+	}
+
+	task, err := h.service.Create(c.Request().Context(), user.ID, &taskRequest)
+	if err != nil {
+		return err
+	}
+
+	task, err = h.service.GetByID(c.Request().Context(), task.ID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, responses.NewEnvelope(NewTaskResource(task)))
+}
+
+func (h *taskHandler) Get(c echo.Context) error {
+	var params IDParam
+	if err := validation.BindPathParams(c, &params); err != nil {
+		return err
+	}
+
+	task, err := h.service.GetByID(c.Request().Context(), params.ID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, responses.NewEnvelope(NewTaskResource(task)))
+}
+
+func (h *taskHandler) Update(c echo.Context) error {
+	var params IDParam
+	if err := validation.BindPathParams(c, &params); err != nil {
+		return err
+	}
+
+	var taskRequest domain.TaskRequest
+	if err := validation.BindBody(c, &taskRequest); err != nil {
+		return err
+	}
+
+	user, err := currentUser(c)
+	if err != nil {
+		return err
+	}
+
+	if _, err := h.service.Update(c.Request().Context(), user.ID, params.ID, &taskRequest); err != nil {
+		return err
+	}
+
+	task, err := h.service.GetByID(c.Request().Context(), params.ID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, responses.NewEnvelope(NewTaskResource(task)))
+}
+
+func (h *taskHandler) Patch(c echo.Context) error {
+	var params IDParam
+	if err := validation.BindPathParams(c, &params); err != nil {
+		return err
+	}
+
+	var taskPatch domain.TaskPatch
+	if err := validation.BindMergePatch(c, &taskPatch); err != nil {
+		return err
+	}
+
+	user, err := currentUser(c)
+	if err != nil {
+		return err
+	}
+
+	task, err := h.service.Patch(c.Request().Context(), user.ID, params.ID, &taskPatch)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, responses.NewEnvelope(NewTaskResource(task)))
+}
+
+func (h *taskHandler) Delete(c echo.Context) error {
+	var params IDParam
+	if err := validation.BindPathParams(c, &params); err != nil {
+		return err
+	}
+
+	if err := h.service.Delete(c.Request().Context(), params.ID); err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+type StatusRequest struct {
+	Status string `json:"status" form:"status" validate:"required,oneof=New 'In Progress' Completed 'On Hold'"`
+}
+
+func (h *taskHandler) UpdateStatus(c echo.Context) error {
+	var params IDParam
+	if err := validation.BindPathParams(c, &params); err != nil {
+		return err
+	}
+
+	var statusRequest StatusRequest
+	if err := validation.BindBody(c, &statusRequest); err != nil {
+		return err
+	}
+
+	user, err := currentUser(c)
+	if err != nil {
+		return err
+	}
+
+	task, err := h.service.UpdateStatus(c.Request().Context(), user.ID, params.ID, domain.Status(statusRequest.Status))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, responses.NewEnvelope(NewTaskResource(task)))
+}
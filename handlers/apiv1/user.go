@@ -0,0 +1,71 @@
+package apiv1
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/mjmarrazzo/maintenance-app/internal/api"
+	"github.com/mjmarrazzo/maintenance-app/internal/database"
+	"github.com/mjmarrazzo/maintenance-app/internal/responses"
+	"github.com/mjmarrazzo/maintenance-app/internal/validation"
+	"github.com/mjmarrazzo/maintenance-app/service"
+)
+
+type UserHandler interface {
+	api.GroupHandler
+	List(c echo.Context) error
+	Get(c echo.Context) error
+	Me(c echo.Context) error
+}
+
+type userHandler struct {
+	service service.UserService
+}
+
+func NewUserHandler(db *database.Client) UserHandler {
+	return &userHandler{service: service.NewUserService(db.Pool())}
+}
+
+func (h *userHandler) RegisterRoutes(g *echo.Group) {
+	g.GET("/users", h.List)
+	g.GET("/users/me", h.Me)
+	g.GET("/users/:id", h.Get)
+}
+
+func (h *userHandler) List(c echo.Context) error {
+	users, err := h.service.GetAll(c.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	resources := mapResources(users, NewUserResource)
+	return c.JSON(http.StatusOK, responses.NewListEnvelope(resources, 0, 0))
+}
+
+func (h *userHandler) Get(c echo.Context) error {
+	var params IDParam
+	if err := validation.BindPathParams(c, &params); err != nil {
+		return err
+	}
+
+	user, err := h.service.GetByID(c.Request().Context(), params.ID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, responses.NewEnvelope(NewUserResource(user)))
+}
+
+func (h *userHandler) Me(c echo.Context) error {
+	sessionUser, err := currentUser(c)
+	if err != nil {
+		return err
+	}
+
+	user, err := h.service.GetByID(c.Request().Context(), sessionUser.ID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, responses.NewEnvelope(NewUserResource(user)))
+}
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/mjmarrazzo/maintenance-app/auth"
+	"github.com/mjmarrazzo/maintenance-app/components/calendar_feed_views"
+	"github.com/mjmarrazzo/maintenance-app/internal/api"
+	"github.com/mjmarrazzo/maintenance-app/internal/database"
+	"github.com/mjmarrazzo/maintenance-app/internal/ical"
+	"github.com/mjmarrazzo/maintenance-app/internal/validation"
+	"github.com/mjmarrazzo/maintenance-app/service"
+)
+
+// calendarFeedPath serves the feeds. The secret goes in the query string,
+// which unlike the path is not logged.
+const calendarFeedPath = "/calendar.ics"
+
+type CalendarFeedHandler interface {
+	api.Handler
+	GetCalendarFeed(c echo.Context) error
+	Regenerate(c echo.Context) error
+	Delete(c echo.Context) error
+	Feed(c echo.Context) error
+}
+
+type calendarFeedHandler struct {
+	service service.CalendarFeedService
+}
+
+func (h *calendarFeedHandler) RegisterRoutes(e *echo.Echo) {
+	group := e.Group("/settings/calendar-feed")
+	group.Use(auth.AuthenticatedMiddleware())
+
+	group.GET("", h.GetCalendarFeed)
+	group.POST("", h.Regenerate)
+	group.DELETE("", h.Delete)
+
+	// Calendar apps fetch the feed without a session, the secret is the
+	// credential.
+	e.GET(calendarFeedPath, h.Feed)
+}
+
+func NewCalendarFeedHandler(db *database.Client) CalendarFeedHandler {
+	return &calendarFeedHandler{service: service.NewCalendarFeedService(db.Pool(), api.BaseURL())}
+}
+
+func (h *calendarFeedHandler) GetCalendarFeed(c echo.Context) error {
+	authCtx, err := auth.GetAuthContext(c)
+	if err != nil {
+		return err
+	}
+
+	feed, err := h.service.Get(c.Request().Context(), authCtx.User.ID)
+	if err != nil {
+		return err
+	}
+
+	return api.Render(c, http.StatusOK, calendar_feed_views.CalendarFeed(feed))
+}
+
+func (h *calendarFeedHandler) Regenerate(c echo.Context) error {
+	authCtx, err := auth.GetAuthContext(c)
+	if err != nil {
+		return err
+	}
+
+	secret, err := h.service.Regenerate(c.Request().Context(), authCtx.User.ID)
+	if err != nil {
+		return err
+	}
+
+	return api.Render(c, http.StatusCreated, calendar_feed_views.Created(api.BaseURL()+calendarFeedPath+"?token="+secret))
+}
+
+func (h *calendarFeedHandler) Delete(c echo.Context) error {
+	authCtx, err := auth.GetAuthContext(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.service.Delete(c.Request().Context(), authCtx.User.ID); err != nil {
+		return err
+	}
+
+	c.Response().Header().Set("Hx-Refresh", "true")
+	return c.NoContent(http.StatusNoContent)
+}
+
+type CalendarFeedTokenParam struct {
+	Token string `query:"token" validate:"required"`
+}
+
+func (h *calendarFeedHandler) Feed(c echo.Context) error {
+	var params CalendarFeedTokenParam
+	if err := validation.BindQueryParams(c, &params); err != nil {
+		return err
+	}
+
+	body, err := h.service.Render(c.Request().Context(), params.Token, time.Now())
+	if err != nil {
+		return err
+	}
+
+	c.Response().Header().Set(echo.HeaderCacheControl, "private, max-age=900")
+	return c.Blob(http.StatusOK, ical.ContentType, body)
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/mjmarrazzo/maintenance-app/domain"
 	"github.com/mjmarrazzo/maintenance-app/internal/api"
 	"github.com/mjmarrazzo/maintenance-app/internal/database"
+	"github.com/mjmarrazzo/maintenance-app/internal/validation"
 	"github.com/mjmarrazzo/maintenance-app/service"
 )
 
@@ -44,7 +45,7 @@ func NewCategoryHandler(db *database.Client) CategoryHandler {
 
 func (h *categoryHandler) Create(c echo.Context) error {
 	var categoryRequest domain.CategoryRequest
-	if err := c.Bind(&categoryRequest); err != nil {
+	if err := validation.BindBody(c, &categoryRequest); err != nil {
 		return err
 	}
 
@@ -104,7 +105,7 @@ func (h *categoryHandler) Update(c echo.Context) error {
 	}
 
 	var category domain.CategoryRequest
-	if err := c.Bind(&category); err != nil {
+	if err := validation.BindBody(c, &category); err != nil {
 		return err
 	}
 
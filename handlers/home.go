@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/labstack/echo/v4"
+	"github.com/mjmarrazzo/maintenance-app/auth"
 	"github.com/mjmarrazzo/maintenance-app/components/auth_views"
 	"github.com/mjmarrazzo/maintenance-app/components/home_views"
 	"github.com/mjmarrazzo/maintenance-app/internal/api"
@@ -27,7 +28,7 @@ func NewHomeHandler() HomeHandler {
 }
 
 func (h *homeHandler) Index(c echo.Context) error {
-	login := auth_views.Login(auth_views.LoginProps{})
+	login := auth_views.Login(auth_views.LoginProps{Providers: auth.LoginProviders()})
 	return api.Render(c, http.StatusOK, login)
 }
 
@@ -117,7 +117,7 @@ func (h *locationHandler) Update(c echo.Context) error {
 	}
 
 	var locationRequest domain.LocationRequest
-	if err := c.Bind(&locationRequest); err != nil {
+	if err := validation.BindBody(c, &locationRequest); err != nil {
 		return err
 	}
 
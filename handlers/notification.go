@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/mjmarrazzo/maintenance-app/auth"
+	"github.com/mjmarrazzo/maintenance-app/components/notification_views"
+	"github.com/mjmarrazzo/maintenance-app/domain"
+	"github.com/mjmarrazzo/maintenance-app/internal/api"
+	"github.com/mjmarrazzo/maintenance-app/internal/database"
+	"github.com/mjmarrazzo/maintenance-app/internal/validation"
+	"github.com/mjmarrazzo/maintenance-app/service"
+)
+
+// notificationsChanged is triggered on the page after notifications were
+// read so the bell in common.Page reloads its badge.
+const notificationsChanged = "notificationsChanged"
+
+type NotificationHandler interface {
+	api.Handler
+	GetInbox(c echo.Context) error
+	GetBadge(c echo.Context) error
+	MarkRead(c echo.Context) error
+	MarkAllRead(c echo.Context) error
+	GetEmailPreferences(c echo.Context) error
+	UpdateEmailPreferences(c echo.Context) error
+}
+
+type notificationHandler struct {
+	service      service.NotificationService
+	emailService service.EmailNotificationService
+}
+
+func (h *notificationHandler) RegisterRoutes(e *echo.Echo) {
+	group := e.Group("/notifications")
+	group.Use(auth.AuthenticatedMiddleware())
+
+	group.GET("", h.GetInbox)
+	group.GET("/badge", h.GetBadge)
+	group.POST("/read", h.MarkAllRead)
+	group.POST("/:id/read", h.MarkRead)
+
+	settings := e.Group("/settings/notifications")
+	settings.Use(auth.AuthenticatedMiddleware())
+
+	settings.GET("", h.GetEmailPreferences)
+	settings.PUT("", h.UpdateEmailPreferences)
+}
+
+func NewNotificationHandler(db *database.Client) NotificationHandler {
+	return &notificationHandler{
+		service:      service.NewNotificationService(db.Pool()),
+		emailService: service.NewEmailNotificationService(db.Pool(), api.BaseURL()),
+	}
+}
+
+func (h *notificationHandler) GetInbox(c echo.Context) error {
+	authCtx, err := auth.GetAuthContext(c)
+	if err != nil {
+		return err
+	}
+
+	notifications, err := h.service.GetInbox(c.Request().Context(), authCtx.User.ID)
+	if err != nil {
+		return err
+	}
+
+	return api.Render(c, http.StatusOK, notification_views.Inbox(notification_views.InboxProps{
+		Notifications: notifications,
+	}))
+}
+
+func (h *notificationHandler) GetBadge(c echo.Context) error {
+	authCtx, err := auth.GetAuthContext(c)
+	if err != nil {
+		return err
+	}
+
+	count, err := h.service.CountUnread(c.Request().Context(), authCtx.User.ID)
+	if err != nil {
+		return err
+	}
+
+	return api.Render(c, http.StatusOK, notification_views.Badge(count))
+}
+
+type NotificationIDParam struct {
+	ID int64 `param:"id" validate:"required,gt=0"`
+}
+
+func (h *notificationHandler) MarkRead(c echo.Context) error {
+	authCtx, err := auth.GetAuthContext(c)
+	if err != nil {
+		return err
+	}
+
+	var params NotificationIDParam
+	if err := validation.BindPathParams(c, &params); err != nil {
+		return err
+	}
+
+	notification, err := h.service.MarkRead(c.Request().Context(), authCtx.User.ID, params.ID)
+	if err != nil {
+		return err
+	}
+
+	c.Response().Header().Set("HX-Trigger", notificationsChanged)
+	return api.Render(c, http.StatusOK, notification_views.Item(notification))
+}
+
+func (h *notificationHandler) MarkAllRead(c echo.Context) error {
+	authCtx, err := auth.GetAuthContext(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.service.MarkAllRead(c.Request().Context(), authCtx.User.ID); err != nil {
+		return err
+	}
+
+	c.Response().Header().Set("Hx-Refresh", "true")
+	return c.NoContent(http.StatusOK)
+}
+
+func (h *notificationHandler) GetEmailPreferences(c echo.Context) error {
+	authCtx, err := auth.GetAuthContext(c)
+	if err != nil {
+		return err
+	}
+
+	preferences, err := h.emailService.GetPreferences(c.Request().Context(), authCtx.User.ID)
+	if err != nil {
+		return err
+	}
+
+	return api.Render(c, http.StatusOK, notification_views.EmailSettings(preferences))
+}
+
+func (h *notificationHandler) UpdateEmailPreferences(c echo.Context) error {
+	authCtx, err := auth.GetAuthContext(c)
+	if err != nil {
+		return err
+	}
+
+	var request domain.EmailPreferencesRequest
+	if err := validation.BindBody(c, &request); err != nil {
+		return err
+	}
+
+	if err := h.emailService.UpdatePreferences(c.Request().Context(), authCtx.User.ID, &request); err != nil {
+		return err
+	}
+
+	c.Response().Header().Set("Hx-Refresh", "true")
+	return c.NoContent(http.StatusOK)
+}
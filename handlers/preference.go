@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/mjmarrazzo/maintenance-app/auth"
+	"github.com/mjmarrazzo/maintenance-app/components/preference_views"
+	"github.com/mjmarrazzo/maintenance-app/domain"
+	"github.com/mjmarrazzo/maintenance-app/internal/api"
+	"github.com/mjmarrazzo/maintenance-app/internal/database"
+	"github.com/mjmarrazzo/maintenance-app/internal/validation"
+	"github.com/mjmarrazzo/maintenance-app/service"
+)
+
+type PreferenceHandler interface {
+	api.Handler
+	GetPreferences(c echo.Context) error
+	UpdatePreferences(c echo.Context) error
+}
+
+type preferenceHandler struct {
+	service service.UserService
+}
+
+func (h *preferenceHandler) RegisterRoutes(e *echo.Echo) {
+	group := e.Group("/settings/preferences")
+	group.Use(auth.AuthenticatedMiddleware())
+
+	group.GET("", h.GetPreferences)
+	group.PUT("", h.UpdatePreferences)
+}
+
+func NewPreferenceHandler(db *database.Client) PreferenceHandler {
+	return &preferenceHandler{service: service.NewUserService(db.Pool())}
+}
+
+func (h *preferenceHandler) GetPreferences(c echo.Context) error {
+	authCtx, err := auth.GetAuthContext(c)
+	if err != nil {
+		return err
+	}
+
+	return api.Render(c, http.StatusOK, preference_views.Preferences(authCtx.User))
+}
+
+func (h *preferenceHandler) UpdatePreferences(c echo.Context) error {
+	authCtx, err := auth.GetAuthContext(c)
+	if err != nil {
+		return err
+	}
+
+	var request domain.PreferencesRequest
+	if err := validation.BindBody(c, &request); err != nil {
+		return err
+	}
+
+	if err := h.service.UpdatePreferences(c.Request().Context(), authCtx.User.ID, &request); err != nil {
+		return err
+	}
+
+	c.Response().Header().Set("Hx-Refresh", "true")
+	return c.NoContent(http.StatusOK)
+}
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/mjmarrazzo/maintenance-app/auth"
+	"github.com/mjmarrazzo/maintenance-app/components/session_views"
+	"github.com/mjmarrazzo/maintenance-app/internal/api"
+	"github.com/mjmarrazzo/maintenance-app/internal/database"
+	"github.com/mjmarrazzo/maintenance-app/internal/validation"
+	"github.com/mjmarrazzo/maintenance-app/service"
+)
+
+type SessionHandler interface {
+	api.Handler
+	GetAllSessions(c echo.Context) error
+	Revoke(c echo.Context) error
+	RevokeAll(c echo.Context) error
+}
+
+type sessionHandler struct {
+	service service.SessionService
+}
+
+func (h *sessionHandler) RegisterRoutes(e *echo.Echo) {
+	group := e.Group("/settings/sessions")
+	group.Use(auth.AuthenticatedMiddleware())
+
+	group.GET("", h.GetAllSessions)
+	group.DELETE("/:id", h.Revoke)
+	group.POST("/revoke-all", h.RevokeAll)
+}
+
+func NewSessionHandler(db *database.Client) SessionHandler {
+	return &sessionHandler{service: service.NewSessionService(db.Pool())}
+}
+
+func (h *sessionHandler) GetAllSessions(c echo.Context) error {
+	authCtx, err := auth.GetAuthContext(c)
+	if err != nil {
+		return err
+	}
+
+	sessions, err := h.service.GetActiveByUserID(c.Request().Context(), authCtx.User.ID)
+	if err != nil {
+		return err
+	}
+
+	props := session_views.ListProps{Sessions: sessions}
+	if authCtx.Session != nil {
+		props.CurrentSessionID = authCtx.Session.ID
+	}
+
+	return api.Render(c, http.StatusOK, session_views.List(props))
+}
+
+type SessionIDParam struct {
+	ID int64 `param:"id" validate:"required,gt=0"`
+}
+
+func (h *sessionHandler) Revoke(c echo.Context) error {
+	authCtx, err := auth.GetAuthContext(c)
+	if err != nil {
+		return err
+	}
+
+	var params SessionIDParam
+	if err := validation.BindPathParams(c, &params); err != nil {
+		return err
+	}
+
+	if err := h.service.Revoke(c.Request().Context(), authCtx.User.ID, params.ID); err != nil {
+		return err
+	}
+
+	if authCtx.Session != nil && authCtx.Session.ID == params.ID {
+		if err := auth.ClearSession(c); err != nil {
+			return err
+		}
+		c.Response().Header().Set("Hx-Redirect", "/")
+		return c.NoContent(http.StatusOK)
+	}
+
+	c.Response().Header().Set("Hx-Refresh", "true")
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *sessionHandler) RevokeAll(c echo.Context) error {
+	authCtx, err := auth.GetAuthContext(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.service.RevokeAll(c.Request().Context(), authCtx.User.ID); err != nil {
+		return err
+	}
+
+	if err := auth.ClearSession(c); err != nil {
+		return err
+	}
+
+	c.Response().Header().Set("Hx-Redirect", "/")
+	return c.NoContent(http.StatusOK)
+}
@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/sessions"
+	"github.com/labstack/echo-contrib/session"
+	"github.com/labstack/echo/v4"
+	"github.com/mjmarrazzo/maintenance-app/components/auth_views"
+	"github.com/mjmarrazzo/maintenance-app/internal/api"
+	"github.com/mjmarrazzo/maintenance-app/internal/database"
+	"github.com/mjmarrazzo/maintenance-app/internal/hashing"
+	"github.com/mjmarrazzo/maintenance-app/internal/logging"
+	"github.com/mjmarrazzo/maintenance-app/internal/responses"
+	"github.com/mjmarrazzo/maintenance-app/internal/sso"
+	"github.com/mjmarrazzo/maintenance-app/internal/validation"
+	"github.com/mjmarrazzo/maintenance-app/service"
+	"golang.org/x/oauth2"
+)
+
+// ssoFlowCookie holds state, nonce and PKCE verifier between the redirect to
+// the provider and its callback. It is SameSite=Lax because the callback is
+// a cross-site navigation, on which the strict session cookie is not sent.
+const (
+	ssoFlowCookie    = "oidc-flow"
+	ssoFlowMaxAge    = 10 * 60
+	ssoProviderValue = "Provider"
+	ssoStateValue    = "State"
+	ssoNonceValue    = "Nonce"
+	ssoVerifierValue = "Verifier"
+	ssoRedirectValue = "Redirect"
+)
+
+type SSOHandler interface {
+	api.Handler
+	Start(c echo.Context) error
+	Callback(c echo.Context) error
+}
+
+type ssoHandler struct {
+	registry         *sso.Registry
+	service          service.SSOService
+	twoFactorService service.TwoFactorService
+}
+
+func (h *ssoHandler) RegisterRoutes(e *echo.Echo) {
+	e.GET("/auth/oidc/:provider", h.Start)
+	e.GET("/auth/oidc/:provider/callback", h.Callback)
+}
+
+func NewSSOHandler(db *database.Client, registry *sso.Registry) SSOHandler {
+	return &ssoHandler{
+		registry:         registry,
+		service:          service.NewSSOService(db.Pool()),
+		twoFactorService: service.NewTwoFactorService(db.Pool()),
+	}
+}
+
+type SSOStartParams struct {
+	Provider string `param:"provider" validate:"required"`
+}
+
+type SSORedirectParams struct {
+	Redirect string `query:"redirect"`
+}
+
+type SSOCallbackParams struct {
+	Provider         string `param:"provider" validate:"required"`
+	Code             string `query:"code"`
+	State            string `query:"state"`
+	Error            string `query:"error"`
+	ErrorDescription string `query:"error_description"`
+}
+
+func (h *ssoHandler) provider(name string) (*sso.Provider, error) {
+	provider, err := h.registry.Lookup(name)
+	if errors.Is(err, sso.ErrUnknownProvider) {
+		return nil, responses.NewNotFoundError("Unknown sign-in provider")
+	}
+	return provider, err
+}
+
+func (h *ssoHandler) Start(c echo.Context) error {
+	var params SSOStartParams
+	if err := validation.BindPathParams(c, &params); err != nil {
+		return err
+	}
+	var query SSORedirectParams
+	if err := validation.BindQueryParams(c, &query); err != nil {
+		return err
+	}
+
+	provider, err := h.provider(params.Provider)
+	if err != nil {
+		return err
+	}
+
+	state, err := hashing.GenerateSecret()
+	if err != nil {
+		return err
+	}
+	nonce, err := hashing.GenerateSecret()
+	if err != nil {
+		return err
+	}
+	verifier := oauth2.GenerateVerifier()
+
+	authURL, err := provider.AuthCodeURL(c.Request().Context(), state, nonce, verifier)
+	if err != nil {
+		return err
+	}
+
+	flow, err := session.Get(ssoFlowCookie, c)
+	if err != nil {
+		return err
+	}
+	flow.Options = &sessions.Options{
+		Path:     "/auth/oidc",
+		MaxAge:   ssoFlowMaxAge,
+		HttpOnly: true,
+		Secure:   api.SecureCookies(),
+		SameSite: http.SameSiteLaxMode,
+	}
+	flow.Values = map[any]any{
+		ssoProviderValue: provider.Name,
+		ssoStateValue:    state,
+		ssoNonceValue:    nonce,
+		ssoVerifierValue: verifier,
+		ssoRedirectValue: localRedirect(query.Redirect),
+	}
+	if err := flow.Save(c.Request(), c.Response()); err != nil {
+		return err
+	}
+
+	return c.Redirect(http.StatusSeeOther, authURL)
+}
+
+func (h *ssoHandler) Callback(c echo.Context) error {
+	ctx := c.Request().Context()
+	logger := logging.FromContext(ctx)
+
+	var params SSOCallbackParams
+	if err := validation.BindPathParams(c, &params); err != nil {
+		return err
+	}
+	if err := validation.BindQueryParams(c, &params); err != nil {
+		return err
+	}
+
+	provider, err := h.provider(params.Provider)
+	if err != nil {
+		return err
+	}
+
+	flow, err := session.Get(ssoFlowCookie, c)
+	if err != nil {
+		return err
+	}
+	providerName, _ := flow.Values[ssoProviderValue].(string)
+	state, _ := flow.Values[ssoStateValue].(string)
+	nonce, _ := flow.Values[ssoNonceValue].(string)
+	verifier, _ := flow.Values[ssoVerifierValue].(string)
+	redirect, _ := flow.Values[ssoRedirectValue].(string)
+
+	// The flow is single use whatever the outcome.
+	flow.Options.MaxAge = -1
+	if err := flow.Save(c.Request(), c.Response()); err != nil {
+		return err
+	}
+
+	if params.Error != "" {
+		logger.Info("single sign-on cancelled", "provider", provider.Name, "error", params.Error, "description", params.ErrorDescription)
+		return responses.NewUnauthorizedError("Sign-in with " + provider.DisplayName + " was cancelled")
+	}
+
+	if state == "" || providerName != provider.Name || subtle.ConstantTimeCompare([]byte(state), []byte(params.State)) != 1 {
+		logger.Warn("single sign-on state mismatch", "provider", provider.Name)
+		return responses.NewUnauthorizedError("This sign-in link has expired. Please try again.")
+	}
+
+	identity, err := provider.Exchange(ctx, params.Code, verifier, nonce)
+	if err != nil {
+		logger.Warn("single sign-on failed", "provider", provider.Name, "error", err)
+		return responses.NewUnauthorizedError("Sign-in with " + provider.DisplayName + " failed")
+	}
+
+	user, err := h.service.Authenticate(ctx, provider, identity, c.RealIP())
+	if err != nil {
+		return err
+	}
+
+	next, err := beginSession(c, h.twoFactorService, user, redirect)
+	if err != nil {
+		return err
+	}
+
+	// The session cookie is SameSite=Strict, so it would not be sent on a
+	// redirect that is still part of the navigation from the provider. A page
+	// that navigates on its own starts a same-site navigation instead.
+	return api.Render(c, http.StatusOK, auth_views.SignInRedirect(next))
+}
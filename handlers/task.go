@@ -1,15 +1,39 @@
 package handlers
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
 	"github.com/labstack/echo/v4"
 	"github.com/mjmarrazzo/maintenance-app/auth"
 	"github.com/mjmarrazzo/maintenance-app/components/task_views"
 	"github.com/mjmarrazzo/maintenance-app/domain"
 	"github.com/mjmarrazzo/maintenance-app/internal/api"
 	"github.com/mjmarrazzo/maintenance-app/internal/database"
+	"github.com/mjmarrazzo/maintenance-app/internal/i18n"
+	"github.com/mjmarrazzo/maintenance-app/internal/logging"
+	"github.com/mjmarrazzo/maintenance-app/internal/realtime"
+	"github.com/mjmarrazzo/maintenance-app/internal/responses"
+	"github.com/mjmarrazzo/maintenance-app/internal/validation"
+	"github.com/mjmarrazzo/maintenance-app/repository"
 	"github.com/mjmarrazzo/maintenance-app/service"
 )
 
+// boardCompletedLimit is how many of the most recently completed tasks the
+// board shows.
+const boardCompletedLimit = 20
+
+// sseHeartbeatInterval keeps idle event streams from being closed by
+// proxies.
+const sseHeartbeatInterval = 30 * time.Second
+
 type TaskHandler interface {
 	api.Handler
 	Create(c echo.Context) error
@@ -19,10 +43,20 @@ type TaskHandler interface {
 	Update(c echo.Context) error
 	Delete(c echo.Context) error
 	GetSelect(c echo.Context) error
+	Events(c echo.Context) error
+	GetCalendar(c echo.Context) error
+	Reschedule(c echo.Context) error
+	GetBoard(c echo.Context) error
+	MoveTask(c echo.Context) error
 }
 
 type taskHandler struct {
-	service service.TaskService
+	service    service.TaskService
+	categories service.CategoryService
+	locations  service.LocationService
+	users      service.UserService
+	settings   service.SettingService
+	hub        *realtime.Hub
 }
 
 func (c taskHandler) RegisterRoutes(e *echo.Echo) {
@@ -33,18 +67,37 @@ func (c taskHandler) RegisterRoutes(e *echo.Echo) {
 	group.GET("", c.GetAllTasks)
 	group.GET("/form", c.GetForm)
 	group.GET("/:id/form", c.GetEditForm)
-	group.PUT("/:id", c.Update)
+	group.PATCH("/:id", c.Update)
 	group.DELETE("/:id", c.Delete)
 	group.GET("/select", c.GetSelect)
+	group.GET("/events", c.Events)
+	group.GET("/calendar", c.GetCalendar)
+	group.PATCH("/:id/due", c.Reschedule)
+	group.GET("/board", c.GetBoard)
+	group.POST("/:id/status", c.MoveTask)
 }
 
-func NewTaskHandler(db *database.Client) TaskHandler {
-	return &taskHandler{service: service.NewTaskService(db.Pool())}
+func NewTaskHandler(db *database.Client, hub *realtime.Hub) TaskHandler {
+	return &taskHandler{
+		service:    service.NewTaskService(db.Pool()),
+		categories: service.NewCategoryService(db.Pool()),
+		locations:  service.NewLocationService(db.Pool()),
+		users:      service.NewUserService(db.Pool()),
+		settings:   service.NewSettingService(db.Pool()),
+		hub:        hub,
+	}
+}
+
+// closeTaskModal is the HX-Trigger that closes the task dialog once the
+// form succeeded.
+func closeTaskModal(c echo.Context) {
+	trigger, _ := json.Marshal(map[string]string{"closeModal": "task_modal"})
+	c.Response().Header().Set("HX-Trigger", string(trigger))
 }
 
 func (h *taskHandler) Create(c echo.Context) error {
 	var taskRequest domain.TaskRequest
-	if err := c.Bind(&taskRequest); err != nil {
+	if err := validation.BindBody(c, &taskRequest); err != nil {
 		return err
 	}
 
@@ -54,13 +107,14 @@ func (h *taskHandler) Create(c echo.Context) error {
 	}
 
 	user := authCtx.User
-	_, err = h.service.Create(c.Request().Context(), user.ID, &taskRequest)
+	task, err := h.service.Create(c.Request().Context(), user.ID, &taskRequest)
 	if err != nil {
 		return err
 	}
 
-	c.Response().Header().Set("Hx-Refresh", "true")
-	return c.NoContent(201)
+	closeTaskModal(c)
+	c.Response().Header().Set("HX-Reswap", "none")
+	return api.Render(c, 201, task_views.CreatedRow(task))
 }
 
 func (h *taskHandler) GetAllTasks(c echo.Context) error {
@@ -110,17 +164,42 @@ func (h *taskHandler) Update(c echo.Context) error {
 	}
 
 	var taskRequest domain.TaskRequest
-	if err := c.Bind(&taskRequest); err != nil {
+	if err := validation.BindBody(c, &taskRequest); err != nil {
+		return err
+	}
+
+	// Only the fields the form posted are changed.
+	form, err := c.FormParams()
+	if err != nil {
+		return err
+	}
+	taskPatch, err := taskRequest.ToPatch(slices.Collect(maps.Keys(form)), i18n.TimeZone(c.Request().Context()))
+	if err != nil {
 		return err
 	}
 
-	_, err := h.service.Update(c.Request().Context(), params.TaskID, &taskRequest)
+	authCtx, err := auth.GetAuthContext(c)
 	if err != nil {
 		return err
 	}
 
-	c.Response().Header().Set("Hx-Refresh", "true")
-	return c.NoContent(200)
+	task, err := h.service.Patch(c.Request().Context(), authCtx.User.ID, params.TaskID, taskPatch)
+	var conflict *service.TaskConflictError
+	if errors.As(err, &conflict) {
+		c.Response().Header().Set("HX-Reswap", "innerHTML")
+		return api.Render(c, http.StatusConflict, task_views.Conflict(task_views.ConflictProps{
+			Form:    form,
+			Current: conflict.Current,
+			Mine:    conflict.Mine,
+		}))
+	}
+	if err != nil {
+		return err
+	}
+
+	closeTaskModal(c)
+	c.Response().Header().Set("HX-Reswap", "none")
+	return api.Render(c, 200, task_views.UpdatedRow(task))
 }
 
 func (h *taskHandler) Delete(c echo.Context) error {
@@ -133,8 +212,8 @@ func (h *taskHandler) Delete(c echo.Context) error {
 		return err
 	}
 
-	c.Response().Header().Set("Hx-Refresh", "true")
-	return c.NoContent(204)
+	// An empty 200 lets htmx swap the row away; it ignores 204 responses.
+	return c.NoContent(200)
 }
 
 type TaskSelectIDParams struct {
@@ -168,3 +247,320 @@ func (h *taskHandler) GetSelect(c echo.Context) error {
 	})
 	return api.Render(c, 200, taskSelect)
 }
+
+// Events streams the rows of created, updated and deleted tasks to the
+// task list as Server-Sent Events until the browser goes away.
+func (h *taskHandler) Events(c echo.Context) error {
+	ctx := c.Request().Context()
+	logger := logging.FromContext(ctx)
+
+	changes, unsubscribe := h.hub.Subscribe()
+	defer unsubscribe()
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set(echo.HeaderCacheControl, "no-cache")
+	res.Header().Set("X-Accel-Buffering", "no")
+	res.WriteHeader(http.StatusOK)
+	res.Flush()
+
+	heartbeat := time.NewTicker(sseHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		var err error
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-heartbeat.C:
+			_, err = io.WriteString(res, realtime.Comment("heartbeat"))
+		case change, ok := <-changes:
+			if !ok {
+				return nil
+			}
+			event, found, eventErr := h.taskEvent(ctx, change)
+			if eventErr != nil {
+				logger.Error("failed to render task change", "task_id", change.TaskID, "error", eventErr)
+				continue
+			}
+			if !found {
+				continue
+			}
+			_, err = event.WriteTo(res)
+		}
+		if err != nil {
+			return nil
+		}
+		res.Flush()
+	}
+}
+
+// taskEvent renders change as the event the task list listens to. found is
+// false for changes to tasks that no longer exist by the time they are
+// rendered; their deletion follows.
+func (h *taskHandler) taskEvent(ctx context.Context, change realtime.TaskChange) (event realtime.Event, found bool, err error) {
+	rowID := task_views.RowID(change.TaskID)
+	if change.Op == realtime.OpDelete {
+		return realtime.Event{ID: rowID, Name: rowID}, true, nil
+	}
+
+	task, err := h.service.GetByID(ctx, change.TaskID)
+	if appErr, ok := responses.IsAppError(err); ok && appErr.Kind == responses.KindNotFound {
+		return event, false, nil
+	}
+	if err != nil {
+		return event, false, err
+	}
+
+	var row strings.Builder
+	if err := task_views.Row(task).Render(ctx, &row); err != nil {
+		return event, false, err
+	}
+
+	name := rowID
+	if change.Op == realtime.OpInsert {
+		name = task_views.CreatedEvent
+	}
+	return realtime.Event{ID: rowID, Name: name, Data: row.String()}, true, nil
+}
+
+// TaskFilterParams are the filters of the task pages.
+type TaskFilterParams struct {
+	CategoryID int64 `query:"category_id" form:"category_id" validate:"omitempty,gt=0"`
+	LocationID int64 `query:"location_id" form:"location_id" validate:"omitempty,gt=0"`
+	AssignedTo int64 `query:"assigned_to" form:"assigned_to" validate:"omitempty,gt=0"`
+}
+
+func (p *TaskFilterParams) ToFilters() repository.TaskFilters {
+	var filters repository.TaskFilters
+	if p.CategoryID != 0 {
+		filters.CategoryID = &p.CategoryID
+	}
+	if p.LocationID != 0 {
+		filters.LocationID = &p.LocationID
+	}
+	if p.AssignedTo != 0 {
+		filters.AssignedTo = &p.AssignedTo
+	}
+	return filters
+}
+
+// filtersProps loads the options of the filters of the task pages.
+func (h *taskHandler) filtersProps(ctx context.Context, params TaskFilterParams) (task_views.FiltersProps, error) {
+	categories, err := h.categories.GetAll(ctx)
+	if err != nil {
+		return task_views.FiltersProps{}, err
+	}
+	locations, err := h.locations.GetAll(ctx)
+	if err != nil {
+		return task_views.FiltersProps{}, err
+	}
+	users, err := h.users.GetAll(ctx)
+	if err != nil {
+		return task_views.FiltersProps{}, err
+	}
+
+	return task_views.FiltersProps{
+		Categories: categories,
+		Locations:  locations,
+		Users:      users,
+		CategoryID: params.CategoryID,
+		LocationID: params.LocationID,
+		AssignedTo: params.AssignedTo,
+	}, nil
+}
+
+// CalendarParams picks the month or week of the calendar by one of its
+// dates, today by default.
+type CalendarParams struct {
+	TaskFilterParams
+	View string `query:"view" form:"view" validate:"omitempty,oneof=month week"`
+	Date string `query:"date" form:"date" validate:"omitempty,datetime=2006-01-02"`
+}
+
+func (h *taskHandler) GetCalendar(c echo.Context) error {
+	var params CalendarParams
+	if err := validation.BindQueryParams(c, &params); err != nil {
+		return err
+	}
+
+	props, err := h.calendarProps(c.Request().Context(), params)
+	if err != nil {
+		return err
+	}
+
+	// Filters and navigation only swap the calendar itself.
+	if api.IsHtmxRequest(c) {
+		return api.Render(c, http.StatusOK, task_views.CalendarBody(props))
+	}
+	return api.Render(c, http.StatusOK, task_views.Calendar(props))
+}
+
+func (h *taskHandler) calendarProps(ctx context.Context, params CalendarParams) (task_views.CalendarProps, error) {
+	zone := i18n.TimeZone(ctx)
+	date := time.Now().In(zone)
+	if params.Date != "" {
+		// Validated by CalendarParams.
+		date, _ = time.ParseInLocation(time.DateOnly, params.Date, zone)
+	}
+	view := task_views.CalendarView(params.View)
+	if view == "" {
+		view = task_views.CalendarMonth
+	}
+
+	from, to := task_views.CalendarRange(view, date, i18n.FirstWeekday(ctx))
+	occurrences, err := h.service.Calendar(ctx, params.ToFilters(), from, to)
+	if err != nil {
+		return task_views.CalendarProps{}, err
+	}
+
+	filters, err := h.filtersProps(ctx, params.TaskFilterParams)
+	if err != nil {
+		return task_views.CalendarProps{}, err
+	}
+
+	return task_views.CalendarProps{
+		View:        view,
+		Date:        date,
+		From:        from,
+		To:          to,
+		Today:       time.Now().In(zone),
+		Occurrences: occurrences,
+		Filters:     filters,
+	}, nil
+}
+
+// RescheduleParams moves a task dropped on another day of the calendar,
+// which is rendered again with its params.
+type RescheduleParams struct {
+	CalendarParams
+	TaskID                  int64  `param:"id"`
+	EstimatedCompletionDate string `form:"estimated_completion_date" validate:"required,datetime=2006-01-02"`
+}
+
+func (h *taskHandler) Reschedule(c echo.Context) error {
+	var params RescheduleParams
+	if err := validation.BindBody(c, &params); err != nil {
+		return err
+	}
+
+	authCtx, err := auth.GetAuthContext(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	request := domain.TaskRequest{EstimatedCompletionDate: params.EstimatedCompletionDate}
+	taskPatch, err := request.ToPatch([]string{"estimated_completion_date"}, i18n.TimeZone(ctx))
+	if err != nil {
+		return err
+	}
+
+	if _, err := h.service.Patch(ctx, authCtx.User.ID, params.TaskID, taskPatch); err != nil {
+		return err
+	}
+
+	props, err := h.calendarProps(ctx, params.CalendarParams)
+	if err != nil {
+		return err
+	}
+	return api.Render(c, http.StatusOK, task_views.CalendarBody(props))
+}
+
+func (h *taskHandler) GetBoard(c echo.Context) error {
+	var params TaskFilterParams
+	if err := validation.BindQueryParams(c, &params); err != nil {
+		return err
+	}
+
+	props, err := h.boardProps(c.Request().Context(), params)
+	if err != nil {
+		return err
+	}
+
+	// Filters only swap the board itself.
+	if api.IsHtmxRequest(c) {
+		return api.Render(c, http.StatusOK, task_views.BoardBody(props))
+	}
+	return api.Render(c, http.StatusOK, task_views.Board(props))
+}
+
+// boardProps loads the open tasks matching params and the ones completed
+// last. Work in progress limits apply to all tasks, so with filters the
+// counts are loaded twice.
+func (h *taskHandler) boardProps(ctx context.Context, params TaskFilterParams) (task_views.BoardProps, error) {
+	open, completed := params.ToFilters(), params.ToFilters()
+	isCompleted, status := false, domain.StatusCompleted
+	open.IsCompleted, open.SortField = &isCompleted, "estimated_completion_date"
+	completed.Status, completed.SortField, completed.SortOrder = &status, "completed_at", "DESC"
+	completed.Limit = boardCompletedLimit
+
+	var tasks []*domain.Task
+	for _, filters := range []repository.TaskFilters{open, completed} {
+		list, err := h.service.List(ctx, filters)
+		if err != nil {
+			return task_views.BoardProps{}, err
+		}
+		tasks = append(tasks, list...)
+	}
+
+	counts, err := h.service.CountByStatus(ctx, params.ToFilters())
+	if err != nil {
+		return task_views.BoardProps{}, err
+	}
+	totals := counts
+	if params != (TaskFilterParams{}) {
+		if totals, err = h.service.CountByStatus(ctx, repository.TaskFilters{}); err != nil {
+			return task_views.BoardProps{}, err
+		}
+	}
+
+	limits, err := h.settings.GetWIPLimits(ctx)
+	if err != nil {
+		return task_views.BoardProps{}, err
+	}
+
+	filters, err := h.filtersProps(ctx, params)
+	if err != nil {
+		return task_views.BoardProps{}, err
+	}
+
+	return task_views.BoardProps{
+		Tasks:   tasks,
+		Counts:  counts,
+		Totals:  totals,
+		Limits:  limits,
+		Filters: filters,
+	}, nil
+}
+
+// MoveTaskParams moves a task dropped on another column of the board, which
+// is rendered again with its filters.
+type MoveTaskParams struct {
+	TaskFilterParams
+	TaskID int64  `param:"id"`
+	Status string `form:"status" validate:"required,oneof=New 'In Progress' Completed 'On Hold'"`
+}
+
+func (h *taskHandler) MoveTask(c echo.Context) error {
+	var params MoveTaskParams
+	if err := validation.BindBody(c, &params); err != nil {
+		return err
+	}
+
+	authCtx, err := auth.GetAuthContext(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	if _, err := h.service.UpdateStatus(ctx, authCtx.User.ID, params.TaskID, domain.Status(params.Status)); err != nil {
+		return err
+	}
+
+	props, err := h.boardProps(ctx, params.TaskFilterParams)
+	if err != nil {
+		return err
+	}
+	return api.Render(c, http.StatusOK, task_views.BoardBody(props))
+}
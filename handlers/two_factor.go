@@ -0,0 +1,314 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/mjmarrazzo/maintenance-app/auth"
+	"github.com/mjmarrazzo/maintenance-app/components/auth_views"
+	"github.com/mjmarrazzo/maintenance-app/components/two_factor_views"
+	"github.com/mjmarrazzo/maintenance-app/domain"
+	"github.com/mjmarrazzo/maintenance-app/internal/api"
+	"github.com/mjmarrazzo/maintenance-app/internal/database"
+	"github.com/mjmarrazzo/maintenance-app/internal/logging"
+	"github.com/mjmarrazzo/maintenance-app/internal/validation"
+	"github.com/mjmarrazzo/maintenance-app/service"
+	"github.com/skip2/go-qrcode"
+)
+
+type TwoFactorHandler interface {
+	api.Handler
+	GetChallenge(c echo.Context) error
+	VerifyChallenge(c echo.Context) error
+	GetLoginEnrollment(c echo.Context) error
+	ConfirmLoginEnrollment(c echo.Context) error
+	GetSettings(c echo.Context) error
+	Enable(c echo.Context) error
+	RegenerateRecoveryCodes(c echo.Context) error
+	Disable(c echo.Context) error
+}
+
+type twoFactorHandler struct {
+	service     service.TwoFactorService
+	userService service.UserService
+}
+
+func (h *twoFactorHandler) RegisterRoutes(e *echo.Echo) {
+	e.GET("/login/two-factor", h.GetChallenge)
+	e.POST("/login/two-factor", h.VerifyChallenge)
+	e.GET("/login/two-factor/setup", h.GetLoginEnrollment)
+	e.POST("/login/two-factor/setup", h.ConfirmLoginEnrollment)
+
+	group := e.Group("/settings/two-factor")
+	group.Use(auth.AuthenticatedMiddleware())
+
+	group.GET("", h.GetSettings)
+	group.POST("", h.Enable)
+	group.POST("/recovery-codes", h.RegenerateRecoveryCodes)
+	group.POST("/disable", h.Disable)
+}
+
+func NewTwoFactorHandler(db *database.Client) TwoFactorHandler {
+	return &twoFactorHandler{
+		service:     service.NewTwoFactorService(db.Pool()),
+		userService: service.NewUserService(db.Pool()),
+	}
+}
+
+// completeLogin signs user in after an htmx login form and redirects to
+// the next step, see beginSession.
+func completeLogin(c echo.Context, twoFactor service.TwoFactorService, user *domain.User, redirect string) error {
+	next, err := beginSession(c, twoFactor, user, redirect)
+	if err != nil {
+		return err
+	}
+
+	c.Response().Header().Set("Hx-Redirect", next)
+	return c.NoContent(http.StatusOK)
+}
+
+// defaultLoginRedirect is where users land after signing in when the login
+// did not ask for a page on this site.
+const defaultLoginRedirect = "/home"
+
+// localRedirect only allows paths on this site, so the login flow cannot be
+// used as an open redirect.
+func localRedirect(target string) string {
+	if !strings.HasPrefix(target, "/") || strings.HasPrefix(target, "//") || strings.HasPrefix(target, "/\\") {
+		return defaultLoginRedirect
+	}
+	return target
+}
+
+// beginSession signs user in once their primary credentials have been
+// checked and returns where to send them. Users with two-factor enabled, or
+// who are required to enroll, are sent to the second step instead and only
+// get a session once it succeeds. redirect is checked by localRedirect.
+func beginSession(c echo.Context, twoFactor service.TwoFactorService, user *domain.User, redirect string) (string, error) {
+	redirect = localRedirect(redirect)
+	required, err := twoFactor.IsRequired(c.Request().Context(), user)
+	if err != nil {
+		return "", err
+	}
+
+	if user.HasTwoFactor() || required {
+		if err := auth.SavePendingLogin(c, user.ID, redirect); err != nil {
+			return "", err
+		}
+		if user.HasTwoFactor() {
+			return "/login/two-factor", nil
+		}
+		return "/login/two-factor/setup", nil
+	}
+
+	if err := auth.SaveUserToSession(c, user); err != nil {
+		return "", err
+	}
+	return redirect, nil
+}
+
+// pendingLogin returns the login waiting for its second step. Without one
+// the browser is sent back to the login page.
+func (h *twoFactorHandler) pendingLogin(c echo.Context) (*auth.PendingLogin, bool) {
+	pending, err := auth.GetPendingLogin(c)
+	if err != nil {
+		logging.FromContext(c.Request().Context()).Info("no pending login", "error", err)
+		return nil, false
+	}
+	return pending, true
+}
+
+func redirectToLogin(c echo.Context) error {
+	if api.IsHtmxRequest(c) {
+		c.Response().Header().Set("Hx-Redirect", "/")
+		return c.NoContent(http.StatusOK)
+	}
+	return c.Redirect(http.StatusSeeOther, "/")
+}
+
+func (h *twoFactorHandler) GetChallenge(c echo.Context) error {
+	if _, ok := h.pendingLogin(c); !ok {
+		return redirectToLogin(c)
+	}
+	return api.Render(c, http.StatusOK, auth_views.TwoFactorChallenge())
+}
+
+func (h *twoFactorHandler) VerifyChallenge(c echo.Context) error {
+	pending, ok := h.pendingLogin(c)
+	if !ok {
+		return redirectToLogin(c)
+	}
+
+	var request domain.TwoFactorRequest
+	if err := validation.BindBody(c, &request); err != nil {
+		return err
+	}
+
+	user, err := h.service.Verify(c.Request().Context(), pending.UserID, request.Code, c.RealIP())
+	if err != nil {
+		return err
+	}
+
+	if err := auth.SaveUserToSession(c, user); err != nil {
+		return err
+	}
+
+	c.Response().Header().Set("Hx-Redirect", pending.Redirect)
+	return c.NoContent(http.StatusOK)
+}
+
+func (h *twoFactorHandler) GetLoginEnrollment(c echo.Context) error {
+	pending, ok := h.pendingLogin(c)
+	if !ok {
+		return redirectToLogin(c)
+	}
+
+	user, err := h.userService.GetByID(c.Request().Context(), pending.UserID)
+	if err != nil {
+		return err
+	}
+	if user.HasTwoFactor() {
+		return c.Redirect(http.StatusSeeOther, "/login/two-factor")
+	}
+
+	enrollment, err := h.enrollment(c, user)
+	if err != nil {
+		return err
+	}
+
+	return api.Render(c, http.StatusOK, auth_views.TwoFactorSetup(enrollment))
+}
+
+func (h *twoFactorHandler) ConfirmLoginEnrollment(c echo.Context) error {
+	pending, ok := h.pendingLogin(c)
+	if !ok {
+		return redirectToLogin(c)
+	}
+
+	var request domain.TwoFactorRequest
+	if err := validation.BindBody(c, &request); err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	user, err := h.userService.GetByID(ctx, pending.UserID)
+	if err != nil {
+		return err
+	}
+
+	codes, err := h.service.ConfirmEnrollment(ctx, user, request.Code)
+	if err != nil {
+		return err
+	}
+
+	if err := auth.SaveUserToSession(c, user); err != nil {
+		return err
+	}
+
+	return api.Render(c, http.StatusOK, two_factor_views.RecoveryCodes(codes, pending.Redirect))
+}
+
+func (h *twoFactorHandler) GetSettings(c echo.Context) error {
+	authCtx, err := auth.GetAuthContext(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	required, err := h.service.IsRequired(ctx, authCtx.User)
+	if err != nil {
+		return err
+	}
+
+	props := two_factor_views.SettingsProps{Required: required}
+	if authCtx.User.HasTwoFactor() {
+		props.Enabled = true
+		props.RemainingCodes, err = h.service.RemainingRecoveryCodes(ctx, authCtx.User)
+	} else {
+		props.Enrollment, err = h.enrollment(c, authCtx.User)
+	}
+	if err != nil {
+		return err
+	}
+
+	return api.Render(c, http.StatusOK, two_factor_views.Settings(props))
+}
+
+func (h *twoFactorHandler) Enable(c echo.Context) error {
+	authCtx, err := auth.GetAuthContext(c)
+	if err != nil {
+		return err
+	}
+
+	var request domain.TwoFactorRequest
+	if err := validation.BindBody(c, &request); err != nil {
+		return err
+	}
+
+	codes, err := h.service.ConfirmEnrollment(c.Request().Context(), authCtx.User, request.Code)
+	if err != nil {
+		return err
+	}
+
+	return api.Render(c, http.StatusOK, two_factor_views.RecoveryCodes(codes, "/settings/two-factor"))
+}
+
+func (h *twoFactorHandler) RegenerateRecoveryCodes(c echo.Context) error {
+	authCtx, err := auth.GetAuthContext(c)
+	if err != nil {
+		return err
+	}
+
+	var request domain.TwoFactorRequest
+	if err := validation.BindBody(c, &request); err != nil {
+		return err
+	}
+
+	codes, err := h.service.RegenerateRecoveryCodes(c.Request().Context(), authCtx.User, request.Code)
+	if err != nil {
+		return err
+	}
+
+	return api.Render(c, http.StatusOK, two_factor_views.RecoveryCodes(codes, "/settings/two-factor"))
+}
+
+func (h *twoFactorHandler) Disable(c echo.Context) error {
+	authCtx, err := auth.GetAuthContext(c)
+	if err != nil {
+		return err
+	}
+
+	var request domain.TwoFactorRequest
+	if err := validation.BindBody(c, &request); err != nil {
+		return err
+	}
+
+	if err := h.service.Disable(c.Request().Context(), authCtx.User, request.Code); err != nil {
+		return err
+	}
+
+	c.Response().Header().Set("Hx-Refresh", "true")
+	return c.NoContent(http.StatusNoContent)
+}
+
+// enrollment starts or resumes enrollment for user and renders the
+// provisioning URI as a QR code for authenticator apps to scan.
+func (h *twoFactorHandler) enrollment(c echo.Context, user *domain.User) (*two_factor_views.Enrollment, error) {
+	enrollment, err := h.service.BeginEnrollment(c.Request().Context(), user)
+	if err != nil {
+		return nil, err
+	}
+
+	png, err := qrcode.Encode(enrollment.URI, qrcode.Medium, 256)
+	if err != nil {
+		return nil, err
+	}
+
+	return &two_factor_views.Enrollment{
+		Secret:    enrollment.Secret,
+		URI:       enrollment.URI,
+		QRCodeURL: "data:image/png;base64," + base64.StdEncoding.EncodeToString(png),
+	}, nil
+}
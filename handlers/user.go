@@ -9,6 +9,8 @@ import (
 	"github.com/mjmarrazzo/maintenance-app/domain"
 	"github.com/mjmarrazzo/maintenance-app/internal/api"
 	"github.com/mjmarrazzo/maintenance-app/internal/database"
+	"github.com/mjmarrazzo/maintenance-app/internal/mail"
+	"github.com/mjmarrazzo/maintenance-app/internal/responses"
 	"github.com/mjmarrazzo/maintenance-app/internal/validation"
 	"github.com/mjmarrazzo/maintenance-app/service"
 )
@@ -19,10 +21,20 @@ type UserHandler interface {
 	Logout(c echo.Context) error
 	GetRegisterForm(c echo.Context) error
 	Register(c echo.Context) error
+	GetForgotPasswordForm(c echo.Context) error
+	ForgotPassword(c echo.Context) error
+	GetResetPasswordForm(c echo.Context) error
+	ResetPassword(c echo.Context) error
+	VerifyEmail(c echo.Context) error
+	ResendVerification(c echo.Context) error
 }
 
 type userHandler struct {
-	service service.UserService
+	service           service.UserService
+	accountService    service.AccountService
+	settingService    service.SettingService
+	invitationService service.InvitationService
+	twoFactorService  service.TwoFactorService
 }
 
 func (h *userHandler) RegisterRoutes(e *echo.Echo) {
@@ -30,11 +42,21 @@ func (h *userHandler) RegisterRoutes(e *echo.Echo) {
 	e.GET("/logout", h.Logout)
 	e.GET("/register", h.GetRegisterForm)
 	e.POST("/register", h.Register)
+	e.GET("/forgot-password", h.GetForgotPasswordForm)
+	e.POST("/forgot-password", h.ForgotPassword)
+	e.GET("/reset-password", h.GetResetPasswordForm)
+	e.POST("/reset-password", h.ResetPassword)
+	e.GET("/verify-email", h.VerifyEmail)
+	e.POST("/verify-email/resend", h.ResendVerification)
 }
 
-func NewAuthHandler(db *database.Client) UserHandler {
+func NewAuthHandler(db *database.Client, mailer mail.Sender) UserHandler {
 	return &userHandler{
-		service: service.NewUserService(db.Pool()),
+		service:           service.NewUserService(db.Pool()),
+		accountService:    service.NewAccountService(db.Pool(), mailer, api.BaseURL()),
+		settingService:    service.NewSettingService(db.Pool()),
+		invitationService: service.NewInvitationService(db.Pool(), mailer, api.BaseURL()),
+		twoFactorService:  service.NewTwoFactorService(db.Pool()),
 	}
 }
 
@@ -50,22 +72,12 @@ func (h *userHandler) Login(c echo.Context) error {
 		return err
 	}
 
-	user, err := h.service.Authenticate(c.Request().Context(), loginParams.Email, loginParams.Password)
+	user, err := h.service.Authenticate(c.Request().Context(), loginParams.Email, loginParams.Password, c.RealIP())
 	if err != nil {
 		return err
 	}
 
-	if err := auth.SaveUserToSession(c, user); err != nil {
-		return err
-	}
-
-	redirectUrl := loginParams.OriginalUrl
-	if redirectUrl == "" {
-		redirectUrl = "/home"
-	}
-
-	c.Response().Header().Set("Hx-Redirect", redirectUrl)
-	return nil
+	return completeLogin(c, h.twoFactorService, user, loginParams.OriginalUrl)
 }
 
 func (h *userHandler) Logout(c echo.Context) error {
@@ -77,19 +89,38 @@ func (h *userHandler) Logout(c echo.Context) error {
 }
 
 type RegistrationParams struct {
-	Email string `query:"email" `
+	Email string `query:"email"`
+	Token string `query:"token"`
 }
 
 func (h *userHandler) GetRegisterForm(c echo.Context) error {
 	params := &RegistrationParams{}
-	if err := validation.BindPathParams(c, params); err != nil {
+	if err := validation.BindQueryParams(c, params); err != nil {
 		return err
 	}
 
-	registerForm := auth_views.Register(auth_views.RegisterProps{
-		Email: params.Email,
-	})
-	return api.Render(c, http.StatusOK, registerForm)
+	ctx := c.Request().Context()
+	mode, err := h.settingService.GetRegistrationMode(ctx)
+	if err != nil {
+		return err
+	}
+
+	props := auth_views.RegisterProps{Email: params.Email}
+	switch {
+	case mode == domain.RegistrationClosed:
+		return responses.NewForbiddenError("Registration is closed")
+	case params.Token != "":
+		invitation, err := h.invitationService.GetPending(ctx, params.Token)
+		if err != nil {
+			return err
+		}
+		props.Email = invitation.Email
+		props.InvitationToken = params.Token
+	case mode == domain.RegistrationInviteOnly:
+		return responses.NewForbiddenError("Registration is by invitation only. Ask an administrator for an invitation.")
+	}
+
+	return api.Render(c, http.StatusOK, auth_views.Register(props))
 }
 
 func (h *userHandler) Register(c echo.Context) error {
@@ -98,11 +129,102 @@ func (h *userHandler) Register(c echo.Context) error {
 		return err
 	}
 
-	if err := h.service.Create(c.Request().Context(), userRequest); err != nil {
+	user, err := h.service.Create(c.Request().Context(), userRequest)
+	if err != nil {
+		return err
+	}
+
+	if user.IsEmailVerified() {
+		return completeLogin(c, h.twoFactorService, user, defaultLoginRedirect)
+	}
+
+	if err := h.accountService.SendVerification(c.Request().Context(), user); err != nil {
+		return err
+	}
+
+	return api.Render(c, http.StatusCreated, auth_views.RegistrationPending(user.Email))
+}
+
+func (h *userHandler) GetForgotPasswordForm(c echo.Context) error {
+	return api.Render(c, http.StatusOK, auth_views.ForgotPassword())
+}
+
+func (h *userHandler) ForgotPassword(c echo.Context) error {
+	request := &domain.ForgotPasswordRequest{}
+	if err := validation.BindBody(c, request); err != nil {
+		return err
+	}
+
+	if err := h.accountService.RequestPasswordReset(c.Request().Context(), request.Email); err != nil {
 		return err
 	}
 
-	c.Response().Header().Set("Hx-Redirect", "/home")
+	return api.Render(c, http.StatusOK, auth_views.ForgotPasswordSent())
+}
+
+type TokenParams struct {
+	Token string `query:"token"`
+}
+
+func (h *userHandler) GetResetPasswordForm(c echo.Context) error {
+	params := &TokenParams{}
+	if err := validation.BindQueryParams(c, params); err != nil {
+		return err
+	}
+
+	if params.Token == "" {
+		return c.Redirect(http.StatusSeeOther, "/forgot-password")
+	}
+
+	resetForm := auth_views.ResetPassword(auth_views.ResetPasswordProps{
+		Token: params.Token,
+	})
+	return api.Render(c, http.StatusOK, resetForm)
+}
+
+func (h *userHandler) ResetPassword(c echo.Context) error {
+	request := &domain.ResetPasswordRequest{}
+	if err := validation.BindBody(c, request); err != nil {
+		return err
+	}
+
+	if err := h.accountService.ResetPassword(c.Request().Context(), request); err != nil {
+		return err
+	}
+
+	return api.Render(c, http.StatusOK, auth_views.ResetPasswordDone())
+}
+
+func (h *userHandler) VerifyEmail(c echo.Context) error {
+	params := &TokenParams{}
+	if err := validation.BindQueryParams(c, params); err != nil {
+		return err
+	}
+
+	if params.Token == "" {
+		return api.Render(c, http.StatusOK, auth_views.VerifyEmail(auth_views.VerifyEmailProps{}))
+	}
+
+	if _, err := h.accountService.VerifyEmail(c.Request().Context(), params.Token); err != nil {
+		if ve, ok := responses.IsValidationError(err); ok {
+			page := auth_views.VerifyEmail(auth_views.VerifyEmailProps{Message: ve.Message})
+			return api.Render(c, http.StatusBadRequest, page)
+		}
+		return err
+	}
+
+	return api.Render(c, http.StatusOK, auth_views.VerifyEmail(auth_views.VerifyEmailProps{Verified: true}))
+}
+
+func (h *userHandler) ResendVerification(c echo.Context) error {
+	request := &domain.ForgotPasswordRequest{}
+	if err := validation.BindBody(c, request); err != nil {
+		return err
+	}
+
+	if err := h.accountService.ResendVerification(c.Request().Context(), request.Email); err != nil {
+		return err
+	}
 
-	return nil
+	return api.Render(c, http.StatusOK, auth_views.VerificationSent())
 }
@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/mjmarrazzo/maintenance-app/auth"
+	"github.com/mjmarrazzo/maintenance-app/components/admin_views"
+	"github.com/mjmarrazzo/maintenance-app/domain"
+	"github.com/mjmarrazzo/maintenance-app/internal/api"
+	"github.com/mjmarrazzo/maintenance-app/internal/database"
+	"github.com/mjmarrazzo/maintenance-app/internal/validation"
+	"github.com/mjmarrazzo/maintenance-app/service"
+)
+
+type WebhookHandler interface {
+	api.Handler
+	GetWebhooks(c echo.Context) error
+	GetNewWebhook(c echo.Context) error
+	CreateWebhook(c echo.Context) error
+	GetWebhook(c echo.Context) error
+	UpdateWebhook(c echo.Context) error
+	DeleteWebhook(c echo.Context) error
+	SendTestEvent(c echo.Context) error
+}
+
+type webhookHandler struct {
+	service service.WebhookService
+}
+
+func (h *webhookHandler) RegisterRoutes(e *echo.Echo) {
+	group := e.Group("/admin/webhooks")
+	group.Use(auth.AuthenticatedMiddleware(), auth.RequireRole(domain.RoleAdmin))
+
+	group.GET("", h.GetWebhooks)
+	group.GET("/new", h.GetNewWebhook)
+	group.POST("", h.CreateWebhook)
+	group.GET("/:id", h.GetWebhook)
+	group.PUT("/:id", h.UpdateWebhook)
+	group.DELETE("/:id", h.DeleteWebhook)
+	group.POST("/:id/test", h.SendTestEvent)
+}
+
+func NewWebhookHandler(db *database.Client) WebhookHandler {
+	return &webhookHandler{service: service.NewWebhookService(db.Pool(), api.BaseURL())}
+}
+
+type WebhookIDParam struct {
+	ID int64 `param:"id" validate:"required,gt=0"`
+}
+
+func (h *webhookHandler) GetWebhooks(c echo.Context) error {
+	webhooks, err := h.service.GetAll(c.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	return api.Render(c, http.StatusOK, admin_views.Webhooks(webhooks))
+}
+
+func (h *webhookHandler) GetNewWebhook(c echo.Context) error {
+	return api.Render(c, http.StatusOK, admin_views.WebhookDetail(admin_views.WebhookDetailProps{}))
+}
+
+func (h *webhookHandler) CreateWebhook(c echo.Context) error {
+	authCtx, err := auth.GetAuthContext(c)
+	if err != nil {
+		return err
+	}
+
+	var webhookRequest domain.WebhookRequest
+	if err := validation.BindBody(c, &webhookRequest); err != nil {
+		return err
+	}
+
+	webhook, err := h.service.Create(c.Request().Context(), authCtx.User, &webhookRequest)
+	if err != nil {
+		return err
+	}
+
+	c.Response().Header().Set("Hx-Redirect", fmt.Sprintf("/admin/webhooks/%d", webhook.ID))
+	return c.NoContent(http.StatusCreated)
+}
+
+func (h *webhookHandler) GetWebhook(c echo.Context) error {
+	var params WebhookIDParam
+	if err := validation.BindPathParams(c, &params); err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	webhook, err := h.service.GetByID(ctx, params.ID)
+	if err != nil {
+		return err
+	}
+
+	deliveries, err := h.service.GetDeliveries(ctx, params.ID)
+	if err != nil {
+		return err
+	}
+
+	return api.Render(c, http.StatusOK, admin_views.WebhookDetail(admin_views.WebhookDetailProps{
+		Webhook:    webhook,
+		Deliveries: deliveries,
+	}))
+}
+
+func (h *webhookHandler) UpdateWebhook(c echo.Context) error {
+	var params WebhookIDParam
+	if err := validation.BindPathParams(c, &params); err != nil {
+		return err
+	}
+
+	var webhookRequest domain.WebhookRequest
+	if err := validation.BindBody(c, &webhookRequest); err != nil {
+		return err
+	}
+
+	if err := h.service.Update(c.Request().Context(), params.ID, &webhookRequest); err != nil {
+		return err
+	}
+
+	c.Response().Header().Set("Hx-Refresh", "true")
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *webhookHandler) DeleteWebhook(c echo.Context) error {
+	var params WebhookIDParam
+	if err := validation.BindPathParams(c, &params); err != nil {
+		return err
+	}
+
+	if err := h.service.Delete(c.Request().Context(), params.ID); err != nil {
+		return err
+	}
+
+	c.Response().Header().Set("Hx-Redirect", "/admin/webhooks")
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *webhookHandler) SendTestEvent(c echo.Context) error {
+	authCtx, err := auth.GetAuthContext(c)
+	if err != nil {
+		return err
+	}
+
+	var params WebhookIDParam
+	if err := validation.BindPathParams(c, &params); err != nil {
+		return err
+	}
+
+	if _, err := h.service.SendTest(c.Request().Context(), authCtx.User, params.ID); err != nil {
+		return err
+	}
+
+	c.Response().Header().Set("Hx-Refresh", "true")
+	return c.NoContent(http.StatusNoContent)
+}
@@ -1,7 +1,16 @@
 package api
 
 import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	ut "github.com/go-playground/universal-translator"
 	"github.com/labstack/echo/v4"
+	"github.com/mjmarrazzo/maintenance-app/components/common"
+	"github.com/mjmarrazzo/maintenance-app/internal/i18n"
+	"github.com/mjmarrazzo/maintenance-app/internal/logging"
 	"github.com/mjmarrazzo/maintenance-app/internal/responses"
 )
 
@@ -14,18 +23,131 @@ func ErrorMiddleware() echo.MiddlewareFunc {
 				return nil
 			}
 
-			if ve, ok := responses.IsValidationError(err); ok {
-				return e.JSON(400, ve)
+			logger := logging.FromContext(e.Request().Context())
+			if e.Response().Committed {
+				logger.Error("error after response was committed", "error", err)
+				return nil
 			}
 
-			if he, ok := err.(*echo.HTTPError); ok {
-				if he.Code == 404 {
-					return e.JSON(404, responses.NewNotFoundError("Route not found"))
+			trans := i18n.Translator(i18n.Locale(e.Request().Context()))
+			if ve, ok := responses.IsValidationError(err); ok {
+				logger.Info("request failed", "error", err)
+				ve = translateValidationError(ve, trans)
+				// Without violations there is no field to attach the message to.
+				if IsHtmxRequest(e) && len(ve.Violations) == 0 {
+					return renderToast(e, ve.StatusCode(), ve.Message)
 				}
+				return e.JSON(ve.StatusCode(), ve)
 			}
 
-			e.Logger().Error(err)
-			return e.JSON(500, responses.NewInternalServerError("Internal server error"))
+			appErr := toAppError(err)
+			status := appErr.StatusCode()
+			message := i18n.Translate(trans, appErr.Message)
+			logger.Log(e.Request().Context(), logLevel(appErr.Kind), "request failed", "error", err)
+
+			switch {
+			case IsHtmxRequest(e):
+				return renderToast(e, status, message)
+			case WantsHTML(e):
+				return Render(e, status, common.ErrorPage(status, message))
+			default:
+				return e.JSON(status, &responses.AppError{Code: appErr.Code, Message: message})
+			}
+		}
+	}
+}
+
+// logLevel is the level failed requests are logged at. Errors are logged
+// once, here, rather than where they are returned.
+func logLevel(kind responses.ErrorKind) slog.Level {
+	switch kind {
+	case responses.KindInternal:
+		return slog.LevelError
+	case responses.KindConflict, responses.KindForbidden, responses.KindRateLimited:
+		return slog.LevelWarn
+	case responses.KindNotFound:
+		return slog.LevelDebug
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// translateValidationError returns a copy of ve with the messages found in
+// the catalog translated. Violations with parameters are translated when
+// they are bound, see validation.TranslateValidationErrors.
+func translateValidationError(ve *responses.ValidationError, trans ut.Translator) *responses.ValidationError {
+	translated := *ve
+	translated.Message = i18n.Translate(trans, ve.Message)
+	if ve.Violations == nil {
+		return &translated
+	}
+
+	translated.Violations = make([]*responses.ViolationsDetail, len(ve.Violations))
+	for i, violation := range ve.Violations {
+		translated.Violations[i] = &responses.ViolationsDetail{
+			Name:    violation.Name,
+			Message: i18n.Translate(trans, violation.Message),
 		}
 	}
+	return &translated
+}
+
+func toAppError(err error) *responses.AppError {
+	if appErr, ok := responses.IsAppError(err); ok {
+		return appErr
+	}
+
+	var he *echo.HTTPError
+	if errors.As(err, &he) {
+		return fromHTTPError(he)
+	}
+
+	return responses.WrapInternalError(err)
+}
+
+func fromHTTPError(he *echo.HTTPError) *responses.AppError {
+	message := http.StatusText(he.Code)
+	if m, ok := he.Message.(string); ok {
+		message = m
+	}
+
+	switch {
+	case he.Code == http.StatusUnauthorized:
+		return responses.NewUnauthorizedError(message)
+	case he.Code == http.StatusForbidden:
+		return responses.NewForbiddenError(message)
+	case he.Code == http.StatusNotFound || he.Code == http.StatusMethodNotAllowed:
+		return responses.NewNotFoundError("Route not found")
+	case he.Code == http.StatusConflict:
+		return responses.NewConflictError(message)
+	case he.Code == http.StatusTooManyRequests:
+		return responses.NewTooManyRequestsError(message)
+	case he.Code < http.StatusInternalServerError:
+		return &responses.AppError{
+			Kind:    responses.KindValidation,
+			Code:    "BAD_REQUEST",
+			Message: message,
+			Err:     he,
+		}
+	default:
+		return responses.WrapInternalError(he)
+	}
+}
+
+// renderToast answers htmx requests with a toast partial and a showToast
+// trigger, which the page script picks up even though htmx does not swap
+// error responses.
+func renderToast(e echo.Context, status int, message string) error {
+	trigger, err := json.Marshal(map[string]any{
+		"showToast": map[string]string{
+			"message": message,
+			"type":    "error",
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	e.Response().Header().Set("HX-Trigger", string(trigger))
+	return Render(e, status, common.Toast(message, "error"))
 }
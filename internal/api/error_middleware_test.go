@@ -0,0 +1,166 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/mjmarrazzo/maintenance-app/internal/logging"
+	"github.com/mjmarrazzo/maintenance-app/internal/responses"
+)
+
+func TestErrorMiddlewareStatusMapping(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+		expectedCode   string
+		expectedLevel  string
+	}{
+		{
+			name:           "validation error",
+			err:            responses.NewValidationError("Validation failed", nil, nil),
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "INVALID_FORMAT",
+			expectedLevel:  "INFO",
+		},
+		{
+			name:           "not found error",
+			err:            responses.NewNotFoundError("task with ID 1 not found"),
+			expectedStatus: http.StatusNotFound,
+			expectedCode:   "NOT_FOUND",
+			expectedLevel:  "DEBUG",
+		},
+		{
+			name:           "conflict error",
+			err:            responses.NewConflictError("user already exists"),
+			expectedStatus: http.StatusConflict,
+			expectedCode:   "CONFLICT",
+			expectedLevel:  "WARN",
+		},
+		{
+			name:           "forbidden error",
+			err:            responses.NewForbiddenError("Administrators only"),
+			expectedStatus: http.StatusForbidden,
+			expectedCode:   "FORBIDDEN",
+			expectedLevel:  "WARN",
+		},
+		{
+			name:           "unauthorized error",
+			err:            responses.NewUnauthorizedError("Invalid credentials"),
+			expectedStatus: http.StatusUnauthorized,
+			expectedCode:   "UNAUTHORIZED",
+			expectedLevel:  "INFO",
+		},
+		{
+			name:           "rate limited error",
+			err:            responses.NewTooManyRequestsError("Too many login attempts"),
+			expectedStatus: http.StatusTooManyRequests,
+			expectedCode:   "TOO_MANY_REQUESTS",
+			expectedLevel:  "WARN",
+		},
+		{
+			name:           "unknown route",
+			err:            echo.ErrNotFound,
+			expectedStatus: http.StatusNotFound,
+			expectedCode:   "NOT_FOUND",
+			expectedLevel:  "DEBUG",
+		},
+		{
+			name:           "plain error",
+			err:            errors.New("connection refused"),
+			expectedStatus: http.StatusInternalServerError,
+			expectedCode:   "INTERNAL_SERVER_ERROR",
+			expectedLevel:  "ERROR",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set(echo.HeaderAccept, echo.MIMEApplicationJSON)
+			var logs bytes.Buffer
+			logger := logging.New(&logs, logging.FormatJSON, slog.LevelDebug)
+			req = req.WithContext(logging.WithLogger(req.Context(), logger))
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			handler := ErrorMiddleware()(func(c echo.Context) error {
+				return tt.err
+			})
+
+			if err := handler(c); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if rec.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+
+			var body map[string]any
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+
+			if body["code"] != tt.expectedCode {
+				t.Errorf("Expected code '%s', got '%v'", tt.expectedCode, body["code"])
+			}
+
+			if strings.Contains(rec.Body.String(), "connection refused") {
+				t.Error("Expected internal error details to be hidden")
+			}
+
+			lines := strings.Split(strings.TrimSpace(logs.String()), "\n")
+			if len(lines) != 1 {
+				t.Fatalf("Expected the error to be logged once, got %d lines", len(lines))
+			}
+			var record map[string]any
+			if err := json.Unmarshal([]byte(lines[0]), &record); err != nil {
+				t.Fatalf("Failed to decode log record: %v", err)
+			}
+			if record["level"] != tt.expectedLevel {
+				t.Errorf("Expected level '%s', got '%v'", tt.expectedLevel, record["level"])
+			}
+		})
+	}
+}
+
+func TestErrorMiddlewareHtmxToast(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodDelete, "/tasks/1", nil)
+	req.Header.Set("HX-Request", "true")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	handler := ErrorMiddleware()(func(c echo.Context) error {
+		return responses.NewNotFoundError("task with ID 1 not found")
+	})
+
+	if err := handler(c); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d, got %d", http.StatusNotFound, rec.Code)
+	}
+
+	var trigger map[string]map[string]string
+	if err := json.Unmarshal([]byte(rec.Header().Get("HX-Trigger")), &trigger); err != nil {
+		t.Fatalf("Failed to decode HX-Trigger header: %v", err)
+	}
+
+	if trigger["showToast"]["message"] != "task with ID 1 not found" {
+		t.Errorf("Unexpected toast message: %v", trigger["showToast"]["message"])
+	}
+
+	if !strings.Contains(rec.Body.String(), "alert-error") {
+		t.Errorf("Expected toast partial, got '%s'", rec.Body.String())
+	}
+}
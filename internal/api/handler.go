@@ -5,3 +5,9 @@ import "github.com/labstack/echo/v4"
 type Handler interface {
 	RegisterRoutes(g *echo.Echo)
 }
+
+// GroupHandler registers its routes on a shared route group, such as the
+// versioned JSON API.
+type GroupHandler interface {
+	RegisterRoutes(g *echo.Group)
+}
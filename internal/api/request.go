@@ -0,0 +1,38 @@
+package api
+
+import (
+	"os"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+func IsHtmxRequest(c echo.Context) bool {
+	return c.Request().Header.Get("HX-Request") == "true"
+}
+
+// WantsHTML reports whether the client is a browser navigating to a page
+// rather than htmx or an API client.
+func WantsHTML(c echo.Context) bool {
+	if IsHtmxRequest(c) {
+		return false
+	}
+	return strings.Contains(c.Request().Header.Get(echo.HeaderAccept), echo.MIMETextHTML)
+}
+
+// BaseURL is the public origin used for links that leave the app, such as
+// those in emails. It comes from APP_BASE_URL rather than the Host header so
+// clients cannot point links elsewhere.
+func BaseURL() string {
+	if base := os.Getenv("APP_BASE_URL"); base != "" {
+		return strings.TrimRight(base, "/")
+	}
+	return "http://localhost:1323"
+}
+
+// SecureCookies reports whether cookies should only be sent over HTTPS,
+// which is the case when BaseURL is an https origin. Browsers drop Secure
+// cookies on plain http, so local setups served over http keep working.
+func SecureCookies() bool {
+	return strings.HasPrefix(BaseURL(), "https://")
+}
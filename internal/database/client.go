@@ -4,7 +4,7 @@ import (
 	"context"
 	_ "embed"
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
 	"sync"
 
@@ -34,13 +34,13 @@ func InitPool() (*Client, error) {
 
 		pool, err = pgxpool.New(context.Background(), connString)
 		if err != nil {
-			log.Printf("Error creating connection pool: %v", err)
+			slog.Error("error creating connection pool", "error", err)
 			return
 		}
 
 		err = pool.Ping(context.Background())
 		if err != nil {
-			log.Printf("Error pinging database: %v", err)
+			slog.Error("error pinging database", "error", err)
 			return
 		}
 
@@ -3,12 +3,16 @@ package database
 import (
 	"errors"
 	"fmt"
+	"regexp"
+	"strings"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/mjmarrazzo/maintenance-app/internal/responses"
 )
 
+var keyColumnRegex = regexp.MustCompile(`Key \(([^)]+)\)`)
+
 func IsPgError(err error, code string) bool {
 	var pgErr *pgconn.PgError
 	return errors.As(err, &pgErr) && pgErr.Code == code
@@ -26,37 +30,72 @@ func IsNotNullViolation(err error) bool {
 	return IsPgError(err, NotNullViolation)
 }
 
+func IsCheckViolation(err error) bool {
+	return IsPgError(err, CheckViolation)
+}
+
 func IsNotFoundViolation(err error) bool {
 	return errors.Is(err, pgx.ErrNoRows)
 }
 
+// HandleError translates a database error into the application error
+// taxonomy. Errors it does not recognise are wrapped as internal errors so the
+// cause is kept but never rendered. It does not log, api.ErrorMiddleware logs
+// the error once when it reaches the response.
 func HandleError(err error, entityType string, id interface{}) error {
-	if IsUniqueViolation(err) {
-		return responses.NewConflictError(
-			fmt.Sprintf("%s with ID %v already exists", entityType, id),
-		)
-	} else if IsForeignKeyViolation(err) {
-		fmt.Printf("Foreign key violation: %v\n", err)
-		return &Error{
-			Err:        ErrNotFound,
-			EntityType: entityType,
-			EntityID:   id,
-			Op:         "delete",
+	var pgErr *pgconn.PgError
+	errors.As(err, &pgErr)
+
+	switch {
+	case IsUniqueViolation(err):
+		return &responses.AppError{
+			Kind:    responses.KindConflict,
+			Code:    "CONFLICT",
+			Message: fmt.Sprintf("%s already exists", describeEntity(entityType, id)),
+			Err:     err,
+		}
+	case IsForeignKeyViolation(err):
+		column := keyColumn(pgErr.Detail)
+		if strings.Contains(pgErr.Detail, "still referenced") {
+			return &responses.AppError{
+				Kind:    responses.KindConflict,
+				Code:    "CONFLICT",
+				Message: fmt.Sprintf("%s is still in use", describeEntity(entityType, id)),
+				Err:     err,
+			}
 		}
-	} else if IsNotNullViolation(err) {
-		fmt.Printf("Not null violation: %v\n", err)
-		return &Error{
-			Err:        ErrNotFound,
-			EntityType: entityType,
-			EntityID:   id,
-			Op:         "update",
+		return responses.NewValidationError("Validation failed", []string{column}, []*responses.ViolationsDetail{
+			{Name: column, Message: "Referenced record does not exist"},
+		})
+	case IsNotNullViolation(err):
+		return responses.NewValidationError("Validation failed", []string{pgErr.ColumnName}, []*responses.ViolationsDetail{
+			{Name: pgErr.ColumnName, Message: "This field is required"},
+		})
+	case IsCheckViolation(err):
+		return responses.NewValidationError("Validation failed", nil, nil)
+	case IsNotFoundViolation(err):
+		return &responses.AppError{
+			Kind:    responses.KindNotFound,
+			Code:    "NOT_FOUND",
+			Message: fmt.Sprintf("%s not found", describeEntity(entityType, id)),
+			Err:     err,
 		}
-	} else if IsNotFoundViolation(err) {
-		fmt.Printf("Not found violation: %v\n", err)
-		return responses.NewNotFoundError(
-			fmt.Sprintf("%s with ID %v not found", entityType, id),
-		)
 	}
 
-	return err
+	return responses.WrapInternalError(err)
+}
+
+func describeEntity(entityType string, id interface{}) string {
+	if id == nil {
+		return entityType
+	}
+	return fmt.Sprintf("%s with ID %v", entityType, id)
+}
+
+func keyColumn(detail string) string {
+	matches := keyColumnRegex.FindStringSubmatch(detail)
+	if len(matches) > 1 {
+		return matches[1]
+	}
+	return ""
 }
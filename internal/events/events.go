@@ -0,0 +1,48 @@
+// Package events delivers domain events from the services that raise them to
+// the subsystems that react to them, such as notifications.
+package events
+
+import (
+	"context"
+	"sync"
+
+	"github.com/mjmarrazzo/maintenance-app/domain"
+)
+
+// Handler reacts to a task event. Handlers run synchronously in the request
+// that raised the event and are expected to log their own failures; the
+// change itself has already been committed.
+type Handler func(ctx context.Context, event domain.TaskEvent)
+
+type Bus struct {
+	mu       sync.RWMutex
+	handlers []Handler
+}
+
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+func (b *Bus) Subscribe(handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers = append(b.handlers, handler)
+}
+
+func (b *Bus) Publish(ctx context.Context, event domain.TaskEvent) {
+	b.mu.RLock()
+	handlers := b.handlers
+	b.mu.RUnlock()
+
+	for _, handler := range handlers {
+		handler(ctx, event)
+	}
+}
+
+var defaultBus = NewBus()
+
+// Default returns the bus the services publish to. Subscribers are
+// registered once at startup.
+func Default() *Bus {
+	return defaultBus
+}
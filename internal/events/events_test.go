@@ -0,0 +1,36 @@
+package events
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mjmarrazzo/maintenance-app/domain"
+)
+
+func TestPublish(t *testing.T) {
+	bus := NewBus()
+
+	var received []string
+	bus.Subscribe(func(ctx context.Context, event domain.TaskEvent) {
+		received = append(received, "first:"+string(event.Kind))
+	})
+	bus.Subscribe(func(ctx context.Context, event domain.TaskEvent) {
+		received = append(received, "second:"+string(event.Kind))
+	})
+
+	bus.Publish(context.Background(), domain.TaskEvent{Kind: domain.TaskEventAssigned})
+
+	expected := []string{"first:task.assigned", "second:task.assigned"}
+	if len(received) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, received)
+	}
+	for i := range expected {
+		if received[i] != expected[i] {
+			t.Errorf("Expected %v, got %v", expected, received)
+		}
+	}
+}
+
+func TestPublishWithoutSubscribers(t *testing.T) {
+	NewBus().Publish(context.Background(), domain.TaskEvent{Kind: domain.TaskEventOverdue})
+}
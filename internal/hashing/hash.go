@@ -1,6 +1,8 @@
 package hashing
 
 import (
+	"sync"
+
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -13,3 +15,14 @@ func VerifyPassword(password, hash string) bool {
 	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
 	return err == nil
 }
+
+var dummyHash = sync.OnceValue(func() []byte {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("not a real password"), 12)
+	return hash
+})
+
+// SimulatePasswordCheck spends as long as VerifyPassword so callers can
+// answer for unknown accounts without revealing that they do not exist.
+func SimulatePasswordCheck(password string) {
+	_ = bcrypt.CompareHashAndPassword(dummyHash(), []byte(password))
+}
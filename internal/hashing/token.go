@@ -0,0 +1,38 @@
+package hashing
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+// TokenPrefix marks API tokens so they are recognisable in configs and
+// secret scanners.
+const TokenPrefix = "gw_"
+
+// GenerateToken returns a new random bearer token. Only its HashToken value
+// should be persisted.
+func GenerateToken() (string, error) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		return "", err
+	}
+	return TokenPrefix + secret, nil
+}
+
+// GenerateSecret returns 32 random bytes, URL safe base64 encoded.
+func GenerateSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// HashToken returns the hex encoded SHA-256 of token. Tokens carry enough
+// entropy that a fast, unsalted hash is sufficient and allows lookups by hash.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
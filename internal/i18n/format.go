@@ -0,0 +1,64 @@
+package i18n
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/go-playground/locales/currency"
+)
+
+// Currency is what costs are recorded in; only how amounts are written
+// depends on the locale.
+var Currency = currency.USD
+
+// FormatNumber writes n with digits decimals the way the locale of ctx does.
+func FormatNumber(ctx context.Context, n float64, digits uint64) string {
+	return Translator(Locale(ctx)).FmtNumber(n, digits)
+}
+
+// FormatCurrency writes amount in Currency the way the locale of ctx does.
+func FormatCurrency(ctx context.Context, amount float64) string {
+	return Translator(Locale(ctx)).FmtCurrency(amount, 2, Currency)
+}
+
+// FormatDate writes the date of t in the time zone of ctx, e.g. Mar 1, 2026
+// or 1 mar. 2026.
+func FormatDate(ctx context.Context, t time.Time) string {
+	return Translator(Locale(ctx)).FmtDateMedium(t.In(TimeZone(ctx)))
+}
+
+// FormatTime writes the time of day of t in the time zone of ctx, e.g.
+// 3:04 pm or 15:04.
+func FormatTime(ctx context.Context, t time.Time) string {
+	return Translator(Locale(ctx)).FmtTimeShort(t.In(TimeZone(ctx)))
+}
+
+// FormatDateTime writes the date and time of day of t.
+func FormatDateTime(ctx context.Context, t time.Time) string {
+	return FormatDate(ctx, t) + " " + FormatTime(ctx, t)
+}
+
+// firstWeekdays is the day calendars of each locale start their weeks on.
+var firstWeekdays = map[string]time.Weekday{
+	English: time.Sunday,
+	Spanish: time.Monday,
+}
+
+// FirstWeekday returns the day weeks start on in the locale of ctx.
+func FirstWeekday(ctx context.Context) time.Weekday {
+	return firstWeekdays[Locale(ctx)]
+}
+
+// FormatWeekday writes the abbreviated name of day, e.g. Mon or lun.
+func FormatWeekday(ctx context.Context, day time.Weekday) string {
+	return Translator(Locale(ctx)).WeekdayAbbreviated(day)
+}
+
+// FormatMonth writes the month and year of t in the time zone of ctx, e.g.
+// March 2026 or marzo de 2026.
+func FormatMonth(ctx context.Context, t time.Time) string {
+	t = t.In(TimeZone(ctx))
+	month := Translator(Locale(ctx)).MonthWide(t.Month())
+	return T(ctx, "{0} {1}", month, strconv.Itoa(t.Year()))
+}
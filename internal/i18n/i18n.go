@@ -0,0 +1,152 @@
+// Package i18n translates messages into the locale of a request. Messages
+// are keyed by their English text, so a message without a translation is
+// shown in English.
+package i18n
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/locales"
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/es"
+	ut "github.com/go-playground/universal-translator"
+)
+
+const (
+	English = "en"
+	Spanish = "es"
+
+	DefaultLocale = English
+)
+
+// Locales are the supported locales in the order they are offered.
+var Locales = []string{English, Spanish}
+
+// LocaleNames names every locale in its own language.
+var LocaleNames = map[string]string{
+	English: "English",
+	Spanish: "Español",
+}
+
+var catalogs = map[string]map[string]string{
+	Spanish: spanish,
+}
+
+// plurals holds the forms of messages with a count, keyed by the English
+// message for one. English needs an entry as well.
+var plurals = map[string]map[string]map[locales.PluralRule]string{
+	English: englishPlurals,
+	Spanish: spanishPlurals,
+}
+
+var universal = newUniversalTranslator()
+
+func newUniversalTranslator() *ut.UniversalTranslator {
+	universal := ut.New(en.New(), en.New(), es.New())
+	for locale, messages := range catalogs {
+		trans, _ := universal.GetTranslator(locale)
+		for key, text := range messages {
+			if err := trans.Add(key, text, false); err != nil {
+				panic(err)
+			}
+		}
+	}
+	for locale, messages := range plurals {
+		trans, _ := universal.GetTranslator(locale)
+		for key, forms := range messages {
+			for rule, text := range forms {
+				if err := trans.AddCardinal(key, text, rule, false); err != nil {
+					panic(err)
+				}
+			}
+		}
+	}
+	if err := universal.VerifyTranslations(); err != nil {
+		panic(err)
+	}
+	return universal
+}
+
+// IsSupported reports whether locale is one of Locales.
+func IsSupported(locale string) bool {
+	_, ok := LocaleNames[locale]
+	return ok
+}
+
+// Translator returns the translator of locale, falling back to the default
+// locale when it is not supported.
+func Translator(locale string) ut.Translator {
+	if trans, ok := universal.GetTranslator(locale); ok {
+		return trans
+	}
+	return universal.GetFallback()
+}
+
+// Translators returns the translators of all supported locales.
+func Translators() []ut.Translator {
+	translators := make([]ut.Translator, len(Locales))
+	for i, locale := range Locales {
+		translators[i] = Translator(locale)
+	}
+	return translators
+}
+
+// Translate looks up key, an English message with {0}, {1}... for params,
+// with trans.
+func Translate(trans ut.Translator, key string, params ...string) string {
+	if trans != nil && strings.Count(key, "{") == len(params) {
+		if text, err := trans.T(key, params...); err == nil {
+			return text
+		}
+	}
+	return Format(key, params...)
+}
+
+// TranslatePlural looks up the form of key, an English message with {0}
+// for the count, that count needs, with the count formatted for trans.
+func TranslatePlural(trans ut.Translator, key string, count int) string {
+	number := strconv.Itoa(count)
+	if trans != nil {
+		number = trans.FmtNumber(float64(count), 0)
+		if text, err := trans.C(key, float64(count), 0, number); err == nil {
+			return text
+		}
+	}
+	return Format(key, number)
+}
+
+// Format substitutes params into key without translating it.
+func Format(key string, params ...string) string {
+	for i, param := range params {
+		key = strings.Replace(key, "{"+strconv.Itoa(i)+"}", param, 1)
+	}
+	return key
+}
+
+type localeKey struct{}
+
+// WithLocale sets the locale messages rendered with ctx are translated into.
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// Locale returns the locale of ctx, or the default locale.
+func Locale(ctx context.Context) string {
+	if locale, ok := ctx.Value(localeKey{}).(string); ok {
+		return locale
+	}
+	return DefaultLocale
+}
+
+// T translates key into the locale of ctx. Components use it for their
+// labels.
+func T(ctx context.Context, key string, params ...string) string {
+	return Translate(Translator(Locale(ctx)), key, params...)
+}
+
+// N translates key, see TranslatePlural, into the locale of ctx.
+func N(ctx context.Context, key string, count int) string {
+	return TranslatePlural(Translator(Locale(ctx)), key, count)
+}
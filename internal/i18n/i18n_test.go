@@ -0,0 +1,132 @@
+package i18n
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		expected       string
+	}{
+		{"empty header", "", English},
+		{"supported language", "es", Spanish},
+		{"language with region", "es-MX,es;q=0.9", Spanish},
+		{"highest quality wins", "en;q=0.5,es;q=0.8", Spanish},
+		{"unsupported languages are skipped", "fr-FR,fr;q=0.9,es;q=0.7", Spanish},
+		{"nothing supported", "de,fr;q=0.5", DefaultLocale},
+		{"refused language", "es;q=0", DefaultLocale},
+		{"malformed quality", "es;q=high,en", English},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Match(tt.acceptLanguage); got != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	tests := []struct {
+		name     string
+		locale   string
+		key      string
+		params   []string
+		expected string
+	}{
+		{"english is the key", English, "Should be at least {0}", []string{"5"}, "Should be at least 5"},
+		{"spanish", Spanish, "Should be at least {0}", []string{"5"}, "Debe ser al menos 5"},
+		{"missing translation", Spanish, "Not in the catalog", nil, "Not in the catalog"},
+		{"missing parameters", Spanish, "Should be at least {0}", nil, "Should be at least {0}"},
+		{"unsupported locale", "fr", "This field is required", nil, "This field is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := WithLocale(context.Background(), tt.locale)
+			if got := T(ctx, tt.key, tt.params...); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestN(t *testing.T) {
+	tests := []struct {
+		name     string
+		locale   string
+		count    int
+		expected string
+	}{
+		{"english one", English, 1, "1 attempt"},
+		{"english other", English, 3, "3 attempts"},
+		{"english zero", English, 0, "0 attempts"},
+		{"spanish one", Spanish, 1, "1 intento"},
+		{"spanish other", Spanish, 2, "2 intentos"},
+		{"grouped count", English, 1200, "1,200 attempts"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := WithLocale(context.Background(), tt.locale)
+			if got := N(ctx, "{0} attempt", tt.count); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestFormat(t *testing.T) {
+	date := time.Date(2026, time.March, 1, 15, 4, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		locale   string
+		format   func(ctx context.Context) string
+		expected string
+	}{
+		{"english date", English, func(ctx context.Context) string { return FormatDate(ctx, date) }, "Mar 1, 2026"},
+		{"spanish date", Spanish, func(ctx context.Context) string { return FormatDate(ctx, date) }, "1 mar. 2026"},
+		{"english currency", English, func(ctx context.Context) string { return FormatCurrency(ctx, 1234.5) }, "$1,234.50"},
+		{"spanish currency", Spanish, func(ctx context.Context) string { return FormatCurrency(ctx, 1234.5) }, "1.234,50\u00a0US$"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := WithLocale(context.Background(), tt.locale)
+			if got := tt.format(ctx); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestTimeZone(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	instant := time.Date(2026, time.March, 1, 3, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		expected string
+	}{
+		{"defaults to UTC", context.Background(), "Mar 1, 2026 3:30 am"},
+		{"in the zone of the context", WithTimeZone(context.Background(), newYork), "Feb 28, 2026 10:30 pm"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatDateTime(tt.ctx, instant); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package i18n
+
+import "github.com/go-playground/locales"
+
+// English messages are their own keys; only plurals need the form for
+// other counts.
+var englishPlurals = map[string]map[locales.PluralRule]string{
+	"{0} attempt": {
+		locales.PluralRuleOne:   "{0} attempt",
+		locales.PluralRuleOther: "{0} attempts",
+	},
+	"{0} unread notification": {
+		locales.PluralRuleOne:   "{0} unread notification",
+		locales.PluralRuleOther: "{0} unread notifications",
+	},
+	"You have {0} unused recovery code.": {
+		locales.PluralRuleOne:   "You have {0} unused recovery code.",
+		locales.PluralRuleOther: "You have {0} unused recovery codes.",
+	},
+	"Showing the last {0} completed task": {
+		locales.PluralRuleOne:   "Showing the last {0} completed task",
+		locales.PluralRuleOther: "Showing the last {0} completed tasks",
+	},
+}
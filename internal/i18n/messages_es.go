@@ -0,0 +1,407 @@
+package i18n
+
+import "github.com/go-playground/locales"
+
+var spanish = map[string]string{
+	// Validation
+	"Validation failed":                               "La validación falló",
+	"Invalid JSON syntax":                             "Sintaxis JSON no válida",
+	"Unexpected field":                                "Campo inesperado",
+	"This field is required":                          "Este campo es obligatorio",
+	"Invalid email format":                            "Formato de correo electrónico no válido",
+	"Should be at least {0}":                          "Debe ser al menos {0}",
+	"Should be at most {0}":                           "Debe ser como máximo {0}",
+	"Should contain only ASCII characters":            "Solo debe contener caracteres ASCII",
+	"Should be a numeric string":                      "Debe ser una cadena numérica",
+	"Should be a number greater than {0}":             "Debe ser un número mayor que {0}",
+	"Should be a number greater than or equal to {0}": "Debe ser un número mayor o igual que {0}",
+	"Should be a number less than {0}":                "Debe ser un número menor que {0}",
+	"Should be a number less than or equal to {0}":    "Debe ser un número menor o igual que {0}",
+	"Should be one of {0}":                            "Debe ser uno de {0}",
+	"Should be a time zone like {0}":                  "Debe ser una zona horaria como {0}",
+	"Should be a date like {0}":                       "Debe ser una fecha como {0}",
+	"Should be formatted like {0}":                    "Debe tener el formato {0}",
+	"Should be an http or https URL":                  "Debe ser una URL http o https",
+	"Should be a positive number":                     "Debe ser un número positivo",
+	"Should be greater than {0}":                      "Debe ser mayor que {0}",
+	"Should be a positive number or zero":             "Debe ser un número positivo o cero",
+	"Should be greater than or equal to {0}":          "Debe ser mayor o igual que {0}",
+	"Should be a negative number":                     "Debe ser un número negativo",
+	"Should be less than {0}":                         "Debe ser menor que {0}",
+	"Should be a negative number or zero":             "Debe ser un número negativo o cero",
+	"Should be less than or equal to {0}":             "Debe ser menor o igual que {0}",
+	"Failed validation: {0}":                          "No superó la validación: {0}",
+	"Expected type <{0}>":                             "Se esperaba el tipo <{0}>",
+	"Expected type <{0}>, but got <{1}>":              "Se esperaba el tipo <{0}>, pero se recibió <{1}>",
+	"Should be a valid id":                            "Debe ser un id válido",
+	"Should be a number":                              "Debe ser un número",
+	"Should be a date":                                "Debe ser una fecha",
+	"Required for recurring tasks":                    "Obligatorio para tareas recurrentes",
+	"Required for custom recurrences":                 "Obligatorio para recurrencias personalizadas",
+	"A task cannot be its own parent":                 "Una tarea no puede ser su propia tarea principal",
+	"Does not exist":                                  "No existe",
+	"Referenced record does not exist":                "El registro referenciado no existe",
+	"Use the address the invitation was sent to":      "Usa la dirección a la que se envió la invitación",
+	"That code is not valid":                          "Ese código no es válido",
+
+	// Errors
+	"Internal server error":                                            "Error interno del servidor",
+	"Route not found":                                                  "Página no encontrada",
+	"Authentication required":                                          "Debes iniciar sesión",
+	"Session expired":                                                  "La sesión ha caducado",
+	"Administrators only":                                              "Solo para administradores",
+	"You do not have permission to do this":                            "No tienes permiso para hacer esto",
+	"Invalid credentials":                                              "Credenciales no válidas",
+	"Too many login attempts":                                          "Demasiados intentos de inicio de sesión",
+	"Too many login attempts. Please try again later.":                 "Demasiados intentos de inicio de sesión. Inténtalo de nuevo más tarde.",
+	"Please verify your email address before signing in":               "Verifica tu dirección de correo electrónico antes de iniciar sesión",
+	"A user with this email already exists":                            "Ya existe un usuario con este correo electrónico",
+	"Registration is closed":                                           "El registro está cerrado",
+	"Registration is by invitation only":                               "El registro es solo por invitación",
+	"This invitation is invalid or has expired":                        "Esta invitación no es válida o ha caducado",
+	"This link is invalid or has expired":                              "Este enlace no es válido o ha caducado",
+	"This page has expired. Please reload it and try again.":           "Esta página ha caducado. Recárgala e inténtalo de nuevo.",
+	"The task was changed by someone else since you loaded it":         "Otra persona cambió la tarea después de que la abrieras",
+	"Only the author or an administrator can delete this comment":      "Solo el autor o un administrador puede eliminar este comentario",
+	"Only the uploader or an administrator can delete this attachment": "Solo quien lo subió o un administrador puede eliminar este archivo adjunto",
+	"Back to home":                                                     "Volver al inicio",
+
+	// Navigation
+	"Home":            "Inicio",
+	"Tasks":           "Tareas",
+	"Locations":       "Ubicaciones",
+	"Categories":      "Categorías",
+	"Sessions":        "Sesiones",
+	"Two-Factor":      "Doble factor",
+	"Email":           "Correo electrónico",
+	"API Tokens":      "Tokens de API",
+	"Preferences":     "Preferencias",
+	"Invitations":     "Invitaciones",
+	"Locked Accounts": "Cuentas bloqueadas",
+	"Webhooks":        "Webhooks",
+	"Settings":        "Configuración",
+	"Notifications":   "Notificaciones",
+
+	// Forms
+	"Required":                       "Obligatorio",
+	"Cancel":                         "Cancelar",
+	"Save Changes":                   "Guardar cambios",
+	"Name":                           "Nombre",
+	"Enter a name":                   "Introduce un nombre",
+	"Description":                    "Descripción",
+	"Title":                          "Título",
+	"Category":                       "Categoría",
+	"Location":                       "Ubicación",
+	"Priority":                       "Prioridad",
+	"Select a priority":              "Selecciona una prioridad",
+	"Select a priority for the task": "Selecciona una prioridad para la tarea",
+	"Status":                         "Estado",
+	"Select a status":                "Selecciona un estado",
+	"Estimated Completion Date":      "Fecha estimada de finalización",
+	"Cost":                           "Costo",
+	"Parent Task":                    "Tarea principal",
+	"Recurring?":                     "¿Recurrente?",
+	"Recurring":                      "Recurrente",
+	"Recurrence Type":                "Tipo de recurrencia",
+	"Select a recurrence type":       "Selecciona un tipo de recurrencia",
+	"Recurrence Interval":            "Intervalo de recurrencia",
+	"Recurrence Unit":                "Unidad de recurrencia",
+	"Select a recurrence unit":       "Selecciona una unidad de recurrencia",
+	"Create Task":                    "Crear tarea",
+	"Edit Task":                      "Editar tarea",
+
+	// Statuses, priorities and recurrences
+	"New":         "Nueva",
+	"In Progress": "En curso",
+	"Completed":   "Completada",
+	"On Hold":     "En espera",
+	"Low":         "Baja",
+	"Medium":      "Media",
+	"High":        "Alta",
+	"Urgent":      "Urgente",
+	"Daily":       "Diaria",
+	"Weekly":      "Semanal",
+	"Monthly":     "Mensual",
+	"Yearly":      "Anual",
+	"Custom":      "Personalizada",
+	"Days":        "Días",
+	"Weeks":       "Semanas",
+	"Months":      "Meses",
+	"Years":       "Años",
+	"Week":        "Semana",
+	"Month":       "Mes",
+
+	// Task conflicts
+	"Task Changed": "Tarea modificada",
+	"Someone else saved this task at {0} while you were editing it.": "Otra persona guardó esta tarea a las {0} mientras la editabas.",
+	"Their changes are the same as yours.":                           "Sus cambios son iguales a los tuyos.",
+	"Saved version":                                                  "Versión guardada",
+	"Your version":                                                   "Tu versión",
+	"Discard Mine and Reload":                                        "Descartar los míos y recargar",
+	"Overwrite with Mine":                                            "Sobrescribir con los míos",
+
+	// Preferences
+	"Language":                              "Idioma",
+	"Time zone":                             "Zona horaria",
+	"Use the time zone of the organization": "Usar la zona horaria de la organización",
+	"Dates are shown and entered in this time zone unless users pick their own": "Las fechas se muestran e introducen en esta zona horaria, salvo que cada usuario elija la suya",
+	"Use my browser's language": "Usar el idioma de mi navegador",
+	"Save Preferences":          "Guardar preferencias",
+
+	// Tasks, categories and locations
+	"Dashboard":                         "Panel",
+	"Welcome":                           "Bienvenido",
+	"Due {0}":                           "Vence el {0}",
+	"No tasks found.":                   "No se encontraron tareas.",
+	"Create a new task to get started.": "Crea una tarea para empezar.",
+	"Are you sure you want to delete the '{0}' task and its subtasks?": "¿Seguro que quieres eliminar la tarea «{0}» y sus subtareas?",
+	"Calendar":          "Calendario",
+	"Previous":          "Anterior",
+	"Next":              "Siguiente",
+	"Today":             "Hoy",
+	"{0} {1}":           "{0} de {1}",
+	"Repeats {0}":       "Se repite: {0}",
+	"All locations":     "Todas las ubicaciones",
+	"All categories":    "Todas las categorías",
+	"Assignee":          "Responsable",
+	"Anyone":            "Cualquiera",
+	"Select a task":     "Selecciona una tarea",
+	"Create Category":   "Crear categoría",
+	"Edit Category":     "Editar categoría",
+	"Select a category": "Selecciona una categoría",
+	"Are you sure you want to delete the '{0}' category?": "¿Seguro que quieres eliminar la categoría «{0}»?",
+	"Create Location":                       "Crear ubicación",
+	"Edit Location":                         "Editar ubicación",
+	"Select a location":                     "Selecciona una ubicación",
+	"Parent Location":                       "Ubicación principal",
+	"No locations":                          "No hay ubicaciones",
+	"None":                                  "Ninguna",
+	"No locations found.":                   "No se encontraron ubicaciones.",
+	"Create a new location to get started.": "Crea una ubicación para empezar.",
+	"Are you sure you want to delete the '{0}' location and its sublocations?": "¿Seguro que quieres eliminar la ubicación «{0}» y sus sububicaciones?",
+	"No results found": "No se encontraron resultados",
+
+	// Sign-in and registration
+	"Welcome to Groundwork!":      "¡Te damos la bienvenida a Groundwork!",
+	"Login":                       "Iniciar sesión",
+	"Register":                    "Registrarse",
+	"Password":                    "Contraseña",
+	"New Password":                "Nueva contraseña",
+	"Confirm Password":            "Confirmar contraseña",
+	"First Name":                  "Nombre",
+	"Last Name":                   "Apellidos",
+	"At least 8 characters":       "Al menos 8 caracteres",
+	"Passwords must match":        "Las contraseñas deben coincidir",
+	"Passwords do not match":      "Las contraseñas no coinciden",
+	"Enter a valid email address": "Introduce una dirección de correo electrónico válida",
+	"Toggle password visibility":  "Mostrar u ocultar la contraseña",
+	"Forgot password?":            "¿Olvidaste tu contraseña?",
+	"Forgot your password?":       "¿Olvidaste tu contraseña?",
+	"Resend verification email":   "Reenviar el correo de verificación",
+	"or":                          "o",
+	"Sign in with {0}":            "Iniciar sesión con {0}",
+	"Back to sign in":             "Volver a iniciar sesión",
+	"Signing in":                  "Iniciando sesión",
+	"Signing you in.":             "Iniciando tu sesión.",
+	"Continue":                    "Continuar",
+	"Send reset link":             "Enviar enlace de restablecimiento",
+	"Choose a new password":       "Elige una nueva contraseña",
+	"Set password":                "Guardar contraseña",
+	"Verify your email":           "Verifica tu correo electrónico",
+	"Send verification link":      "Enviar enlace de verificación",
+	"Check your inbox":            "Revisa tu bandeja de entrada",
+	"Enter your email address and we will send you a link to choose a new password.":           "Introduce tu dirección de correo electrónico y te enviaremos un enlace para elegir una nueva contraseña.",
+	"Enter your email address and we will send you a new verification link.":                   "Introduce tu dirección de correo electrónico y te enviaremos un nuevo enlace de verificación.",
+	"If an account exists for that address, a password reset link is on its way.":              "Si existe una cuenta con esa dirección, te enviamos un enlace para restablecer la contraseña.",
+	"If that address belongs to an unverified account, a new verification link is on its way.": "Si esa dirección pertenece a una cuenta sin verificar, te enviamos un nuevo enlace de verificación.",
+	"Your email address is verified. You can sign in now.":                                     "Tu dirección de correo electrónico está verificada. Ya puedes iniciar sesión.",
+	"Your password has been changed and all other sessions were signed out.":                   "Tu contraseña se cambió y se cerraron todas las demás sesiones.",
+	"We sent a verification link to {0}. Open it to activate your account.":                    "Enviamos un enlace de verificación a {0}. Ábrelo para activar tu cuenta.",
+
+	// Two-factor authentication
+	"Two-Factor Authentication":                                                          "Autenticación de doble factor",
+	"Two-factor authentication":                                                          "Autenticación de doble factor",
+	"Set up two-factor authentication":                                                   "Configura la autenticación de doble factor",
+	"Two-factor authentication is enabled for your account.":                             "La autenticación de doble factor está activada en tu cuenta.",
+	"Each code signs you in once if you lose access to your authenticator app.":          "Cada código te permite iniciar sesión una vez si pierdes el acceso a tu aplicación de autenticación.",
+	"Generate new recovery codes? The current ones stop working.":                        "¿Generar nuevos códigos de recuperación? Los actuales dejarán de funcionar.",
+	"Generate New Recovery Codes":                                                        "Generar nuevos códigos de recuperación",
+	"Two-factor authentication is required for administrators and cannot be turned off.": "La autenticación de doble factor es obligatoria para los administradores y no se puede desactivar.",
+	"Turn off two-factor authentication?":                                                "¿Desactivar la autenticación de doble factor?",
+	"Turn Off":                                                                           "Desactivar",
+	"Administrators are required to use two-factor authentication.":                      "Los administradores deben usar la autenticación de doble factor.",
+	"Code":         "Código",
+	"Enter a code": "Introduce un código",
+	"Code from your authenticator app or a recovery code":                                                             "Código de tu aplicación de autenticación o un código de recuperación",
+	"Scan this QR code with an authenticator app such as Google Authenticator, Microsoft Authenticator or 1Password.": "Escanea este código QR con una aplicación de autenticación como Google Authenticator, Microsoft Authenticator o 1Password.",
+	"QR code for your authenticator app":                                                                              "Código QR para tu aplicación de autenticación",
+	"Can't scan it? Enter this key instead:":                                                                          "¿No puedes escanearlo? Introduce esta clave:",
+	"Open in an authenticator app on this device":                                                                     "Abrir en una aplicación de autenticación de este dispositivo",
+	"6-digit code":                     "Código de 6 dígitos",
+	"Enter the code shown in your app": "Introduce el código que muestra tu aplicación",
+	"Verify":                           "Verificar",
+	"Verify and Enable":                "Verificar y activar",
+	"Enter the code from your authenticator app. If you lost access to it, use one of your recovery codes.":  "Introduce el código de tu aplicación de autenticación. Si perdiste el acceso a ella, usa uno de tus códigos de recuperación.",
+	"Your account requires two-factor authentication. Set it up to finish signing in.":                       "Tu cuenta requiere autenticación de doble factor. Configúrala para terminar de iniciar sesión.",
+	"Save these recovery codes somewhere safe. Each one can be used once, and they will not be shown again.": "Guarda estos códigos de recuperación en un lugar seguro. Cada uno se puede usar una vez y no se volverán a mostrar.",
+
+	// Sessions and API tokens
+	"Active Sessions":    "Sesiones activas",
+	"Log out everywhere": "Cerrar sesión en todas partes",
+	"Sign out of every device, including this one?": "¿Cerrar sesión en todos los dispositivos, incluido este?",
+	"This device":            "Este dispositivo",
+	"Unknown device":         "Dispositivo desconocido",
+	"Signed in {0}":          "Inició sesión el {0}",
+	"Last active {0}":        "Última actividad el {0}",
+	"Sign out this session?": "¿Cerrar esta sesión?",
+	"Sign out":               "Cerrar sesión",
+	"Tokens let scripts and integrations call the API with an":  "Los tokens permiten que scripts e integraciones llamen a la API con un encabezado",
+	" header. They act as you, limited to the scopes you pick.": ". Actúan en tu nombre, limitados a los permisos que elijas.",
+	"Scopes":             "Permisos",
+	"Read":               "Lectura",
+	"Write":              "Escritura",
+	"Expires":            "Caduca",
+	"In 30 days":         "En 30 días",
+	"In 7 days":          "En 7 días",
+	"In 90 days":         "En 90 días",
+	"In a year":          "En un año",
+	"Never":              "Nunca",
+	"Create Token":       "Crear token",
+	"No API tokens yet.": "Todavía no hay tokens de API.",
+	"Created":            "Creado",
+	"Last used":          "Último uso",
+	"Revoked":            "Revocado",
+	"Expired":            "Caducado",
+	"Revoke":             "Revocar",
+	"Revoke the '{0}' token? Clients using it will stop working.": "¿Revocar el token «{0}»? Los clientes que lo usan dejarán de funcionar.",
+	"Token": "El token",
+	"created. Copy it now, it will not be shown again.": "se creó. Cópialo ahora, no se volverá a mostrar.",
+
+	// Notifications
+	"Mark all read":                                        "Marcar todo como leído",
+	"Mark read":                                            "Marcar como leída",
+	"You're all caught up.":                                "Estás al día.",
+	"{0} assigned you to “{1}”":                            "{0} te asignó «{1}»",
+	"{0} moved “{1}” to {2}":                               "{0} movió «{1}» a {2}",
+	"{0} commented on “{1}”":                               "{0} comentó en «{1}»",
+	"“{0}” is due soon":                                    "«{0}» vence pronto",
+	"“{0}” is overdue":                                     "«{0}» está vencida",
+	"Email Notifications":                                  "Notificaciones por correo electrónico",
+	"Email me right away":                                  "Enviarme un correo de inmediato",
+	"Include in my daily digest":                           "Incluir en mi resumen diario",
+	"Don't email me":                                       "No enviarme correos",
+	"When a task is assigned to me":                        "Cuando se me asigna una tarea",
+	"When someone mentions me in a comment":                "Cuando alguien me menciona en un comentario",
+	"When one of my tasks is overdue":                      "Cuando una de mis tareas está vencida",
+	"List my open tasks due this week in the daily digest": "Incluir mis tareas abiertas que vencen esta semana en el resumen diario",
+	"The digest is sent once a day in the morning, and only when there is something in it.": "El resumen se envía una vez al día por la mañana y solo cuando tiene contenido.",
+
+	// Administration
+	"Save Settings":             "Guardar configuración",
+	"Registration":              "Registro",
+	"Open: anyone can register": "Abierto: cualquiera puede registrarse",
+	"Invite only: registration needs an invitation": "Solo por invitación: el registro requiere una invitación",
+	"Closed: nobody can register":                   "Cerrado: nadie puede registrarse",
+	"open":                                          "abierto",
+	"invite-only":                                   "solo por invitación",
+	"closed":                                        "cerrado",
+	"Require two-factor authentication for administrators": "Exigir autenticación de doble factor a los administradores",
+	"Registration is currently":                            "Actualmente el registro está",
+	"Change it in settings.":                               "Cámbialo en la configuración.",
+	"Role":                                                 "Rol",
+	"User":                                                 "Usuario",
+	"Administrator":                                        "Administrador",
+	"Send Invitation":                                      "Enviar invitación",
+	"No invitations sent yet.":                             "Todavía no se han enviado invitaciones.",
+	"Invited by":                                           "Invitado por",
+	"Accepted":                                             "Aceptada",
+	"Pending until {0}":                                    "Pendiente hasta el {0}",
+	"Revoke the invitation for {0}?":                       "¿Revocar la invitación de {0}?",
+	"No accounts are locked.":                              "No hay cuentas bloqueadas.",
+	"Failed attempts":                                      "Intentos fallidos",
+	"Locked until":                                         "Bloqueada hasta",
+	"Unlock":                                               "Desbloquear",
+	"Unlock the account for {0}?":                          "¿Desbloquear la cuenta de {0}?",
+	"Recent failed sign-ins":                               "Inicios de sesión fallidos recientes",
+	"Failed Sign-ins":                                      "Inicios de sesión fallidos",
+	"No failed sign-ins recorded.":                         "No hay inicios de sesión fallidos registrados.",
+	"Time":                                                 "Hora",
+	"IP address":                                           "Dirección IP",
+	"Reason":                                               "Motivo",
+	"Webhook":                                              "Webhook",
+	"New Webhook":                                          "Nuevo webhook",
+	"Webhooks post task events as signed JSON to chat and other systems.": "Los webhooks envían los eventos de las tareas como JSON firmado a chats y otros sistemas.",
+	"No webhooks configured yet.":                                         "Todavía no hay webhooks configurados.",
+	"URL":                                                                 "URL",
+	"Events":                                                              "Eventos",
+	"Active":                                                              "Activo",
+	"Disabled":                                                            "Desactivado",
+	"Send Test Event":                                                     "Enviar evento de prueba",
+	"Delete":                                                              "Eliminar",
+	"Delete the webhook {0} and its delivery log?": "¿Eliminar el webhook {0} y su registro de envíos?",
+	"Payload URL":                "URL de destino",
+	"Enter an http or https URL": "Introduce una URL http o https",
+	"Signing secret":             "Secreto de firma",
+	"Show secret":                "Mostrar secreto",
+	"Signing secret (leave empty to generate one)":             "Secreto de firma (déjalo vacío para generar uno)",
+	"New signing secret (leave empty to keep the current one)": "Nuevo secreto de firma (déjalo vacío para mantener el actual)",
+	"Use at least 16 characters":                               "Usa al menos 16 caracteres",
+	"Send an event when":                                       "Enviar un evento cuando",
+	"A task is created":                                        "Se crea una tarea",
+	"A task is assigned":                                       "Se asigna una tarea",
+	"The status of a task changes":                             "Cambia el estado de una tarea",
+	"A comment is added":                                       "Se añade un comentario",
+	"A task is due within a day":                               "Una tarea vence en menos de un día",
+	"A task is overdue":                                        "Una tarea está vencida",
+	"Create Webhook":                                           "Crear webhook",
+	"Save Webhook":                                             "Guardar webhook",
+	"Recent Deliveries":                                        "Envíos recientes",
+	"Nothing has been sent to this webhook yet.":               "Todavía no se ha enviado nada a este webhook.",
+	"Event":           "Evento",
+	"Attempts":        "Intentos",
+	"Response":        "Respuesta",
+	"Delivered":       "Entregado",
+	"Failed":          "Fallido",
+	"Pending":         "Pendiente",
+	"Retrying at {0}": "Reintento a las {0}",
+	"No response":     "Sin respuesta",
+	"Payload":         "Contenido",
+	"Two-factor authentication is already enabled":                                "La autenticación de doble factor ya está activada",
+	"Two-factor authentication is not enabled":                                    "La autenticación de doble factor no está activada",
+	"Administrators have to keep two-factor authentication enabled":               "Los administradores deben mantener activada la autenticación de doble factor",
+	"Start the enrollment again":                                                  "Vuelve a iniciar la configuración",
+	"Registration is by invitation only. Ask an administrator for an invitation.": "El registro es solo por invitación. Pide una invitación a un administrador.",
+	"This sign-in link has expired. Please try again.":                            "Este enlace de inicio de sesión caducó. Inténtalo de nuevo.",
+	"Calendar Feed":  "Feed de calendario",
+	"Turn On":        "Activar",
+	"Regenerate URL": "Regenerar URL",
+	"Subscribe":      "Suscribirse",
+	"Subscribe to your assigned tasks from your phone or calendar app. The feed lists due dates and upcoming occurrences of recurring tasks, and is kept up to date by the app.": "Suscríbete a tus tareas asignadas desde tu teléfono o tu aplicación de calendario. El feed muestra las fechas límite y las próximas repeticiones de las tareas recurrentes, y la aplicación lo mantiene actualizado.",
+	"Get a new feed URL? Calendars subscribed with the current one will stop updating.":                                                                                          "¿Obtener una nueva URL del feed? Los calendarios suscritos con la actual dejarán de actualizarse.",
+	"Turn off the calendar feed? Calendars subscribed to it will stop updating.":                                                                                                 "¿Desactivar el feed de calendario? Los calendarios suscritos dejarán de actualizarse.",
+	"Your feed is ready. Copy the URL now, it will not be shown again. Anyone with it can see your tasks.":                                                                       "Tu feed está listo. Copia la URL ahora, no se volverá a mostrar. Cualquiera que la tenga puede ver tus tareas.",
+	"Board":                   "Tablero",
+	"Completed {0}":           "Completada el {0}",
+	"Work in progress limit":  "Límite de trabajo en curso",
+	"Work in progress limits": "Límites de trabajo en curso",
+	"The most tasks a column of the board should hold. Leave empty for no limit.": "El número máximo de tareas que debería tener una columna del tablero. Déjalo vacío para no poner límite.",
+}
+
+var spanishPlurals = map[string]map[locales.PluralRule]string{
+	"{0} attempt": {
+		locales.PluralRuleOne:   "{0} intento",
+		locales.PluralRuleOther: "{0} intentos",
+	},
+	"{0} unread notification": {
+		locales.PluralRuleOne:   "{0} notificación sin leer",
+		locales.PluralRuleOther: "{0} notificaciones sin leer",
+	},
+	"You have {0} unused recovery code.": {
+		locales.PluralRuleOne:   "Te queda {0} código de recuperación sin usar.",
+		locales.PluralRuleOther: "Te quedan {0} códigos de recuperación sin usar.",
+	},
+	"Showing the last {0} completed task": {
+		locales.PluralRuleOne:   "Se muestra {0} tarea completada, la más reciente",
+		locales.PluralRuleOther: "Se muestran las {0} tareas completadas más recientes",
+	},
+}
@@ -0,0 +1,61 @@
+package i18n
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Middleware sets the locale of the request from its Accept-Language
+// header. auth.AuthenticatedMiddleware replaces it with the locale the user
+// picked, if any.
+func Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			locale := Match(req.Header.Get("Accept-Language"))
+			c.SetRequest(req.WithContext(WithLocale(req.Context(), locale)))
+			return next(c)
+		}
+	}
+}
+
+// Match returns the supported locale the Accept-Language header prefers,
+// ignoring regions, or the default locale.
+func Match(acceptLanguage string) string {
+	type language struct {
+		tag     string
+		quality float64
+	}
+
+	var languages []language
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		quality := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+		if tag == "" || quality <= 0 {
+			continue
+		}
+		languages = append(languages, language{tag: strings.ToLower(tag), quality: quality})
+	}
+
+	sort.SliceStable(languages, func(i, j int) bool {
+		return languages[i].quality > languages[j].quality
+	})
+
+	for _, lang := range languages {
+		base, _, _ := strings.Cut(strings.ReplaceAll(lang.tag, "_", "-"), "-")
+		if IsSupported(base) {
+			return base
+		}
+	}
+	return DefaultLocale
+}
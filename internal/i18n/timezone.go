@@ -0,0 +1,119 @@
+package i18n
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+	// The server image has no zoneinfo of its own.
+	_ "time/tzdata"
+
+	"github.com/labstack/echo/v4"
+	"github.com/mjmarrazzo/maintenance-app/internal/logging"
+)
+
+// DefaultTimeZone is used until an administrator picks the time zone of the
+// organization.
+const DefaultTimeZone = "UTC"
+
+type timeZoneKey struct{}
+
+// WithTimeZone sets the time zone dates and times rendered with ctx are
+// shown in.
+func WithTimeZone(ctx context.Context, zone *time.Location) context.Context {
+	return context.WithValue(ctx, timeZoneKey{}, zone)
+}
+
+// TimeZone returns the time zone of ctx, or UTC.
+func TimeZone(ctx context.Context) *time.Location {
+	switch zone := ctx.Value(timeZoneKey{}).(type) {
+	case *time.Location:
+		return zone
+	case func() *time.Location:
+		return zone()
+	}
+	return time.UTC
+}
+
+// TimeZoneMiddleware sets the time zone of the request to the one lookup
+// returns, the default of the organization. It is only looked up when
+// something is rendered in it. auth.AuthenticatedMiddleware replaces it
+// with the time zone the user picked, if any.
+func TimeZoneMiddleware(lookup func(ctx context.Context) (*time.Location, error)) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			ctx := req.Context()
+			zone := sync.OnceValue(func() *time.Location {
+				zone, err := lookup(ctx)
+				if err != nil {
+					logging.FromContext(ctx).Error("time zone lookup failed", "error", err)
+					return time.UTC
+				}
+				return zone
+			})
+			c.SetRequest(req.WithContext(context.WithValue(ctx, timeZoneKey{}, zone)))
+			return next(c)
+		}
+	}
+}
+
+// LoadTimeZone returns the time zone named name, or nil when the name is
+// empty or unknown.
+func LoadTimeZone(name string) *time.Location {
+	if name == "" {
+		return nil
+	}
+	zone, err := time.LoadLocation(name)
+	if err != nil {
+		return nil
+	}
+	return zone
+}
+
+// TimeZones are the time zones offered in the settings, see
+// TimeZoneOptions.
+var TimeZones = []string{
+	"UTC",
+	"Pacific/Honolulu",
+	"America/Anchorage",
+	"America/Los_Angeles",
+	"America/Phoenix",
+	"America/Denver",
+	"America/Chicago",
+	"America/New_York",
+	"America/Halifax",
+	"America/Mexico_City",
+	"America/Bogota",
+	"America/Lima",
+	"America/Caracas",
+	"America/Santiago",
+	"America/Argentina/Buenos_Aires",
+	"America/Sao_Paulo",
+	"Atlantic/Canary",
+	"Europe/London",
+	"Europe/Lisbon",
+	"Europe/Madrid",
+	"Europe/Paris",
+	"Europe/Berlin",
+	"Europe/Athens",
+	"Africa/Lagos",
+	"Africa/Nairobi",
+	"Africa/Johannesburg",
+	"Asia/Dubai",
+	"Asia/Kolkata",
+	"Asia/Manila",
+	"Asia/Singapore",
+	"Asia/Tokyo",
+	"Australia/Sydney",
+	"Pacific/Auckland",
+}
+
+// TimeZoneOptions returns TimeZones with current added, so a time zone set
+// through the API stays selected.
+func TimeZoneOptions(current string) []string {
+	if current == "" || slices.Contains(TimeZones, current) {
+		return TimeZones
+	}
+	return append([]string{current}, TimeZones...)
+}
@@ -0,0 +1,195 @@
+// Package ical writes iCalendar (RFC 5545) feeds of events and to-dos, the
+// format calendar apps subscribe to.
+package ical
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ContentType is the media type of a feed.
+const ContentType = "text/calendar; charset=utf-8"
+
+// maxLineLength is the length in octets lines are folded at, without the
+// line break.
+const maxLineLength = 75
+
+const (
+	dateLayout     = "20060102"
+	dateTimeLayout = "20060102T150405Z"
+)
+
+// Calendar is a feed. Name is shown by apps that support the
+// X-WR-CALNAME extension.
+type Calendar struct {
+	ProductID  string
+	Name       string
+	Components []Component
+}
+
+// Component is an Event or a Todo.
+type Component interface {
+	write(w *writer)
+}
+
+// Status is the STATUS of a component. Events and to-dos use different
+// values, see the constants.
+type Status string
+
+const (
+	StatusConfirmed   Status = "CONFIRMED"
+	StatusNeedsAction Status = "NEEDS-ACTION"
+	StatusInProcess   Status = "IN-PROCESS"
+	StatusCompleted   Status = "COMPLETED"
+)
+
+// Event is a VEVENT. Events with AllDay set last from the date of Start
+// until the date of End, which is exclusive as RFC 5545 has it.
+type Event struct {
+	UID          string
+	Stamp        time.Time
+	Start        time.Time
+	End          time.Time
+	AllDay       bool
+	Summary      string
+	Description  string
+	URL          string
+	Status       Status
+	LastModified time.Time
+}
+
+func (e Event) write(w *writer) {
+	w.line("BEGIN:VEVENT")
+	w.property("UID", e.UID)
+	w.dateTime("DTSTAMP", e.Stamp)
+	if e.AllDay {
+		w.date("DTSTART", e.Start)
+		w.date("DTEND", e.End)
+	} else {
+		w.dateTime("DTSTART", e.Start)
+		w.dateTime("DTEND", e.End)
+	}
+	w.text("SUMMARY", e.Summary)
+	w.text("DESCRIPTION", e.Description)
+	w.property("URL", e.URL)
+	w.property("STATUS", string(e.Status))
+	w.dateTime("LAST-MODIFIED", e.LastModified)
+	w.line("END:VEVENT")
+}
+
+// Todo is a VTODO. Due is a date for to-dos with AllDay set. Priority
+// runs from 1, the highest, to 9; 0 leaves it undefined.
+type Todo struct {
+	UID          string
+	Stamp        time.Time
+	Due          time.Time
+	AllDay       bool
+	Summary      string
+	Description  string
+	URL          string
+	Status       Status
+	Priority     int
+	Completed    time.Time
+	LastModified time.Time
+}
+
+func (t Todo) write(w *writer) {
+	w.line("BEGIN:VTODO")
+	w.property("UID", t.UID)
+	w.dateTime("DTSTAMP", t.Stamp)
+	if t.AllDay {
+		w.date("DUE", t.Due)
+	} else {
+		w.dateTime("DUE", t.Due)
+	}
+	w.text("SUMMARY", t.Summary)
+	w.text("DESCRIPTION", t.Description)
+	w.property("URL", t.URL)
+	w.property("STATUS", string(t.Status))
+	if t.Priority > 0 {
+		w.property("PRIORITY", fmt.Sprint(t.Priority))
+	}
+	w.dateTime("COMPLETED", t.Completed)
+	w.dateTime("LAST-MODIFIED", t.LastModified)
+	w.line("END:VTODO")
+}
+
+// Marshal writes c as an iCalendar object.
+func (c *Calendar) Marshal() []byte {
+	w := &writer{}
+	w.line("BEGIN:VCALENDAR")
+	w.line("VERSION:2.0")
+	w.text("PRODID", c.ProductID)
+	w.line("CALSCALE:GREGORIAN")
+	w.line("METHOD:PUBLISH")
+	w.text("X-WR-CALNAME", c.Name)
+	for _, component := range c.Components {
+		component.write(w)
+	}
+	w.line("END:VCALENDAR")
+	return w.buf.Bytes()
+}
+
+// writer writes content lines, folded and ended with CRLF. Empty values and
+// zero times leave their property out.
+type writer struct {
+	buf bytes.Buffer
+}
+
+func (w *writer) property(name, value string) {
+	if value != "" {
+		w.line(name + ":" + value)
+	}
+}
+
+func (w *writer) text(name, value string) {
+	w.property(name, escapeText(value))
+}
+
+func (w *writer) date(name string, t time.Time) {
+	if !t.IsZero() {
+		w.line(name + ";VALUE=DATE:" + t.Format(dateLayout))
+	}
+}
+
+func (w *writer) dateTime(name string, t time.Time) {
+	if !t.IsZero() {
+		w.line(name + ":" + t.UTC().Format(dateTimeLayout))
+	}
+}
+
+// line folds content after 75 octets, never inside a UTF-8 sequence, with
+// continuation lines starting with a space.
+func (w *writer) line(content string) {
+	limit := maxLineLength
+	for len(content) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(content[cut]) {
+			cut--
+		}
+		w.buf.WriteString(content[:cut])
+		w.buf.WriteString("\r\n ")
+		content = content[cut:]
+		// The leading space counts towards the length of the next line.
+		limit = maxLineLength - 1
+	}
+	w.buf.WriteString(content)
+	w.buf.WriteString("\r\n")
+}
+
+var textEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	";", `\;`,
+	",", `\,`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+	"\r", `\n`,
+)
+
+// escapeText escapes a TEXT value.
+func escapeText(value string) string {
+	return textEscaper.Replace(value)
+}
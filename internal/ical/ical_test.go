@@ -0,0 +1,100 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMarshal(t *testing.T) {
+	stamp := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+	due := time.Date(2026, time.March, 10, 0, 0, 0, 0, time.UTC)
+	calendar := &Calendar{
+		ProductID: "-//Groundwork//Tasks//EN",
+		Name:      "Tasks",
+		Components: []Component{
+			Event{
+				UID:     "task-1@example.org",
+				Stamp:   stamp,
+				Start:   due,
+				End:     due.AddDate(0, 0, 1),
+				AllDay:  true,
+				Summary: "Fix the boiler; call Ann, then Bob",
+				Status:  StatusConfirmed,
+			},
+			Todo{
+				UID:      "task-1-todo@example.org",
+				Stamp:    stamp,
+				Due:      due,
+				AllDay:   true,
+				Summary:  "Fix the boiler",
+				Status:   StatusNeedsAction,
+				Priority: 1,
+			},
+		},
+	}
+
+	expected := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//Groundwork//Tasks//EN",
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+		"X-WR-CALNAME:Tasks",
+		"BEGIN:VEVENT",
+		"UID:task-1@example.org",
+		"DTSTAMP:20260301T120000Z",
+		"DTSTART;VALUE=DATE:20260310",
+		"DTEND;VALUE=DATE:20260311",
+		`SUMMARY:Fix the boiler\; call Ann\, then Bob`,
+		"STATUS:CONFIRMED",
+		"END:VEVENT",
+		"BEGIN:VTODO",
+		"UID:task-1-todo@example.org",
+		"DTSTAMP:20260301T120000Z",
+		"DUE;VALUE=DATE:20260310",
+		"SUMMARY:Fix the boiler",
+		"STATUS:NEEDS-ACTION",
+		"PRIORITY:1",
+		"END:VTODO",
+		"END:VCALENDAR",
+		"",
+	}, "\r\n")
+
+	if got := string(calendar.Marshal()); got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+}
+
+func TestLineFolding(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"short", "SUMMARY:Short"},
+		{"ascii", "DESCRIPTION:" + strings.Repeat("a", 200)},
+		{"multibyte", "DESCRIPTION:" + strings.Repeat("ñ", 100)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &writer{}
+			w.line(tt.content)
+
+			lines := strings.Split(strings.TrimSuffix(w.buf.String(), "\r\n"), "\r\n")
+			for i, line := range lines {
+				if len(line) > maxLineLength {
+					t.Errorf("Expected line %d to be at most %d octets, got %d", i, maxLineLength, len(line))
+				}
+				if i > 0 && !strings.HasPrefix(line, " ") {
+					t.Errorf("Expected continuation line %d to start with a space, got %q", i, line)
+				}
+			}
+
+			unfolded := strings.ReplaceAll(strings.TrimSuffix(w.buf.String(), "\r\n"), "\r\n ", "")
+			if unfolded != tt.content {
+				t.Errorf("Expected %q, got %q", tt.content, unfolded)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+type Format string
+
+const (
+	FormatText Format = "text"
+	FormatJSON Format = "json"
+)
+
+type ctxKey struct{}
+
+// New builds a logger writing to w in the given format. Sensitive attributes
+// are redacted before they reach the handler.
+func New(w io.Writer, format Format, level slog.Level) *slog.Logger {
+	opts := &slog.HandlerOptions{
+		Level:       level,
+		ReplaceAttr: redactAttr,
+	}
+
+	var handler slog.Handler
+	if format == FormatJSON {
+		handler = slog.NewJSONHandler(w, opts)
+	} else {
+		handler = slog.NewTextHandler(w, opts)
+	}
+
+	return slog.New(handler)
+}
+
+// NewFromEnv builds a logger configured by LOG_FORMAT (text or json) and
+// LOG_LEVEL (debug, info, warn or error).
+func NewFromEnv() *slog.Logger {
+	format := Format(strings.ToLower(os.Getenv("LOG_FORMAT")))
+	return New(os.Stdout, format, parseLevel(os.Getenv("LOG_LEVEL")))
+}
+
+func parseLevel(value string) slog.Level {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(value)); err != nil {
+		return slog.LevelInfo
+	}
+	return level
+}
+
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, ctxKey{}, logger)
+}
+
+// FromContext returns the logger carried by ctx, falling back to the default
+// logger when the context has none.
+func FromContext(ctx context.Context) *slog.Logger {
+	if ctx != nil {
+		if logger, ok := ctx.Value(ctxKey{}).(*slog.Logger); ok {
+			return logger
+		}
+	}
+	return slog.Default()
+}
@@ -0,0 +1,103 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestRedaction(t *testing.T) {
+	tests := []struct {
+		name     string
+		key      string
+		value    string
+		expected string
+	}{
+		{name: "password", key: "password", value: "hunter2", expected: redacted},
+		{name: "password hash", key: "password_hash", value: "$2a$12$abc", expected: redacted},
+		{name: "session value", key: "session", value: "abc", expected: redacted},
+		{name: "authorization header", key: "Authorization", value: "Bearer abc", expected: redacted},
+		{name: "api token", key: "api_token", value: "gw_abc", expected: redacted},
+		{name: "plain field", key: "email", value: "john@example.com", expected: "john@example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := New(&buf, FormatJSON, slog.LevelInfo)
+			logger.Info("test", tt.key, tt.value)
+
+			var entry map[string]any
+			if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+				t.Fatalf("Failed to decode log entry: %v", err)
+			}
+
+			if entry[tt.key] != tt.expected {
+				t.Errorf("Expected %s to be '%s', got '%v'", tt.key, tt.expected, entry[tt.key])
+			}
+		})
+	}
+}
+
+func TestMiddlewareRequestID(t *testing.T) {
+	tests := []struct {
+		name       string
+		incomingID string
+		expectSame bool
+	}{
+		{name: "generates request ID", incomingID: "", expectSame: false},
+		{name: "keeps incoming request ID", incomingID: "abc-123", expectSame: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := New(&buf, FormatJSON, slog.LevelInfo)
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/tasks?token=secret", nil)
+			if tt.incomingID != "" {
+				req.Header.Set(echo.HeaderXRequestID, tt.incomingID)
+			}
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			var ctxLogger *slog.Logger
+			handler := Middleware(logger)(func(c echo.Context) error {
+				ctxLogger = FromContext(c.Request().Context())
+				return c.NoContent(http.StatusOK)
+			})
+
+			if err := handler(c); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			requestID := rec.Header().Get(echo.HeaderXRequestID)
+			if requestID == "" {
+				t.Fatal("Expected response to carry a request ID")
+			}
+			if tt.expectSame && requestID != tt.incomingID {
+				t.Errorf("Expected request ID '%s', got '%s'", tt.incomingID, requestID)
+			}
+			if ctxLogger == nil || ctxLogger == slog.Default() {
+				t.Error("Expected a request scoped logger in the context")
+			}
+
+			var entry map[string]any
+			if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+				t.Fatalf("Failed to decode log entry: %v", err)
+			}
+			if entry["request_id"] != requestID {
+				t.Errorf("Expected log entry request_id '%s', got '%v'", requestID, entry["request_id"])
+			}
+			if entry["path"] != "/tasks" {
+				t.Errorf("Expected path without query string, got '%v'", entry["path"])
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package mail
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	msg, err := Render("ada@example.com", "Reset your password", "password_reset", map[string]string{
+		"FirstName": "Ada",
+		"URL":       "https://example.com/reset-password?token=a&b",
+		"ExpiresIn": "1 hour",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !strings.Contains(msg.Text, "token=a&b") {
+		t.Errorf("Expected raw URL in text body, got '%s'", msg.Text)
+	}
+
+	if !strings.Contains(msg.HTML, "token=a&amp;b") {
+		t.Errorf("Expected escaped URL in HTML body, got '%s'", msg.HTML)
+	}
+}
+
+func TestFileSender(t *testing.T) {
+	dir := t.TempDir()
+	sender, err := NewFileSender(dir, "Groundwork <no-reply@example.com>")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	err = sender.Send(context.Background(), Message{
+		To:      "ada@example.com",
+		Subject: "Hello",
+		Text:    "Plain body",
+		HTML:    "<p>HTML body</p>",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.eml"))
+	if len(files) != 1 {
+		t.Fatalf("Expected 1 message file, got %d", len(files))
+	}
+
+	data, _ := os.ReadFile(files[0])
+	for _, expected := range []string{"To: ada@example.com", "Subject: Hello", "multipart/alternative", "Plain body", "<p>HTML body</p>"} {
+		if !strings.Contains(string(data), expected) {
+			t.Errorf("Expected message to contain '%s'", expected)
+		}
+	}
+}
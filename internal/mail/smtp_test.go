@@ -0,0 +1,182 @@
+package mail
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"testing"
+)
+
+// smtpServer is a minimal SMTP server that accepts every message and keeps
+// the envelope and data of each one.
+type smtpServer struct {
+	listener net.Listener
+	messages chan smtpMessage
+}
+
+type smtpMessage struct {
+	from string
+	to   []string
+	data string
+}
+
+func newSMTPServer(t *testing.T) *smtpServer {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	s := &smtpServer{listener: listener, messages: make(chan smtpMessage, 1)}
+	go s.serve()
+	return s
+}
+
+func (s *smtpServer) port() string {
+	_, port, _ := net.SplitHostPort(s.listener.Addr().String())
+	return port
+}
+
+func (s *smtpServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *smtpServer) handle(conn net.Conn) {
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+
+	var msg smtpMessage
+	reply("220 localhost ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		command := strings.ToUpper(line)
+
+		switch {
+		case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+			reply("250 localhost")
+		case strings.HasPrefix(command, "MAIL FROM:"):
+			msg.from = strings.Trim(line[len("MAIL FROM:"):], "<>")
+			reply("250 OK")
+		case strings.HasPrefix(command, "RCPT TO:"):
+			msg.to = append(msg.to, strings.Trim(line[len("RCPT TO:"):], "<>"))
+			reply("250 OK")
+		case command == "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				dataLine, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if dataLine == ".\r\n" {
+					break
+				}
+				data.WriteString(dataLine)
+			}
+			msg.data = data.String()
+			s.messages <- msg
+			msg = smtpMessage{}
+			reply("250 OK")
+		case command == "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+func TestSMTPSender(t *testing.T) {
+	server := newSMTPServer(t)
+
+	sender, err := NewSMTPSender(SMTPConfig{
+		Host: "127.0.0.1",
+		Port: server.port(),
+		From: "Groundwork <no-reply@example.com>",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	msg, err := Render("Ada Lovelace <ada@example.com>", "You were assigned to Fix the boiler", "task_assigned", map[string]string{
+		"FirstName":      "Ada",
+		"Actor":          "Grace Hopper",
+		"TaskTitle":      "Fix the boiler",
+		"URL":            "https://example.com/tasks",
+		"PreferencesURL": "https://example.com/settings/notifications",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if err := sender.Send(context.Background(), msg); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	received := <-server.messages
+	if received.from != "no-reply@example.com" {
+		t.Errorf("Expected envelope sender 'no-reply@example.com', got '%s'", received.from)
+	}
+	if len(received.to) != 1 || received.to[0] != "ada@example.com" {
+		t.Errorf("Expected envelope recipient 'ada@example.com', got %v", received.to)
+	}
+	for _, expected := range []string{"Subject: You were assigned to Fix the boiler", "multipart/alternative", "Grace Hopper"} {
+		if !strings.Contains(received.data, expected) {
+			t.Errorf("Expected message to contain '%s'", expected)
+		}
+	}
+}
+
+type digestTask struct {
+	Title   string
+	Due     string
+	Overdue bool
+}
+
+func TestRenderDigest(t *testing.T) {
+	data := struct {
+		FirstName      string
+		Items          []string
+		Tasks          []digestTask
+		URL            string
+		PreferencesURL string
+	}{
+		FirstName: "Ada",
+		Items:     []string{"Grace Hopper assigned you to “Fix <the> boiler”"},
+		Tasks: []digestTask{
+			{Title: "Clean gutters", Due: "Mon, Jan 5", Overdue: true},
+			{Title: "Mow lawn", Due: "Fri, Jan 9"},
+		},
+		URL:            "https://example.com/tasks",
+		PreferencesURL: "https://example.com/settings/notifications",
+	}
+
+	msg, err := Render("ada@example.com", "Your digest", "digest", data)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for _, expected := range []string{"Fix <the> boiler", "Clean gutters (overdue since Mon, Jan 5)", "Mow lawn (due Fri, Jan 9)"} {
+		if !strings.Contains(msg.Text, expected) {
+			t.Errorf("Expected text body to contain '%s', got '%s'", expected, msg.Text)
+		}
+	}
+	if !strings.Contains(msg.HTML, "Fix &lt;the&gt; boiler") {
+		t.Errorf("Expected escaped item in HTML body, got '%s'", msg.HTML)
+	}
+}
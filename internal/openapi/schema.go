@@ -0,0 +1,348 @@
+package openapi
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// Nullable is implemented by wrappers like patch.Field that are encoded as
+// the wrapped type or null.
+type Nullable interface {
+	NullableType() reflect.Type
+}
+
+var nullableType = reflect.TypeOf((*Nullable)(nil)).Elem()
+
+// Generator derives JSON schemas from Go types using their json and validate
+// struct tags. Named structs are registered once as components and referenced
+// from everywhere else, so the document mirrors the Go types one to one.
+type Generator struct {
+	schemas map[string]*Schema
+}
+
+func NewGenerator() *Generator {
+	return &Generator{schemas: make(map[string]*Schema)}
+}
+
+// Schemas returns every component schema registered so far.
+func (g *Generator) Schemas() map[string]*Schema {
+	return g.schemas
+}
+
+// Schema returns the schema for v. Values that already are a *Schema are
+// returned unchanged.
+func (g *Generator) Schema(v any) *Schema {
+	if s, ok := v.(*Schema); ok {
+		return s
+	}
+	return g.schemaForType(reflect.TypeOf(v))
+}
+
+// Parameters describes the fields of v tagged with the given binding tag
+// ("query" or "param") as operation parameters.
+func (g *Generator) Parameters(v any, tag string) []*Parameter {
+	in := tag
+	if tag == "param" {
+		in = "path"
+	}
+
+	t := derefType(reflect.TypeOf(v))
+	var params []*Parameter
+	for _, field := range reflect.VisibleFields(t) {
+		if !field.IsExported() || field.Anonymous {
+			continue
+		}
+		name := tagName(field.Tag.Get(tag))
+		if name == "" || name == "-" {
+			continue
+		}
+
+		rules := parseRules(field.Tag.Get("validate"))
+		schema := g.schemaForType(field.Type)
+		applyRules(schema, field.Type, rules)
+
+		params = append(params, &Parameter{
+			Name:     name,
+			In:       in,
+			Required: in == "path" || rules.has("required"),
+			Schema:   schema,
+		})
+	}
+	return params
+}
+
+func (g *Generator) schemaForType(t reflect.Type) *Schema {
+	if t == nil {
+		return &Schema{}
+	}
+
+	if t.Kind() == reflect.Pointer {
+		return g.nullableSchema(t.Elem())
+	}
+
+	if t.Implements(nullableType) {
+		return g.nullableSchema(reflect.Zero(t).Interface().(Nullable).NullableType())
+	}
+
+	if t == timeType {
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: g.schemaForType(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object"}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		return g.register(t)
+	default:
+		return &Schema{}
+	}
+}
+
+func (g *Generator) nullableSchema(t reflect.Type) *Schema {
+	schema := g.schemaForType(t)
+	if schema.Ref != "" {
+		return schema
+	}
+	if typ, ok := schema.Type.(string); ok {
+		schema.Type = []string{typ, "null"}
+	}
+	return schema
+}
+
+func (g *Generator) register(t reflect.Type) *Schema {
+	name := t.Name()
+	if _, ok := g.schemas[name]; !ok {
+		// Reserve the name first so self referencing types terminate.
+		g.schemas[name] = &Schema{}
+		*g.schemas[name] = *g.structSchema(t)
+	}
+	return Ref(name)
+}
+
+func (g *Generator) structSchema(t reflect.Type) *Schema {
+	schema := &Schema{
+		Type:       "object",
+		Properties: make(map[string]*Schema),
+	}
+
+	for _, field := range reflect.VisibleFields(t) {
+		if !field.IsExported() || field.Anonymous {
+			continue
+		}
+		name := tagName(field.Tag.Get("json"))
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		rules := parseRules(field.Tag.Get("validate"))
+		property := g.schemaForType(field.Type)
+		applyRules(property, field.Type, rules)
+
+		schema.Properties[name] = property
+		if rules.has("required") {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+
+	return schema
+}
+
+func tagName(tag string) string {
+	name, _, _ := strings.Cut(tag, ",")
+	return name
+}
+
+func derefType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
+
+type rule struct {
+	name  string
+	param string
+}
+
+type rules []rule
+
+func (rs rules) has(name string) bool {
+	for _, r := range rs {
+		if r.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// parseRules splits a validate tag into its rules. Alternatives ("a|b") and
+// rules after dive are ignored since they cannot be expressed on the field
+// itself.
+func parseRules(tag string) rules {
+	var rs rules
+	for _, part := range strings.Split(tag, ",") {
+		if part == "" || strings.Contains(part, "|") {
+			continue
+		}
+		if part == "dive" {
+			break
+		}
+		name, param, _ := strings.Cut(part, "=")
+		rs = append(rs, rule{name: name, param: param})
+	}
+	return rs
+}
+
+var boolValues = []any{"1", "t", "T", "TRUE", "true", "True", "0", "f", "F", "FALSE", "false", "False"}
+
+// applyRules narrows schema with the constraints of the validate rules that
+// have a JSON schema equivalent. Rules without one are left out; the API
+// still answers a violation with a ValidationError.
+func applyRules(schema *Schema, t reflect.Type, rs rules) {
+	if schema.Ref != "" {
+		return
+	}
+
+	kind := derefType(t).Kind()
+	isString := kind == reflect.String
+	isNumber := kind >= reflect.Int && kind <= reflect.Float64
+
+	for _, r := range rs {
+		switch r.name {
+		case "email":
+			schema.Format = "email"
+		case "url", "uri":
+			schema.Format = "uri"
+		case "uuid":
+			schema.Format = "uuid"
+		case "numeric", "numericstring", "number":
+			if isString {
+				schema.Pattern = "^[0-9]+$"
+			}
+		case "boolean":
+			if isString {
+				schema.Enum = boolValues
+			}
+		case "datetime":
+			switch r.param {
+			case "2006-01-02":
+				schema.Format = "date"
+			case time.RFC3339:
+				schema.Format = "date-time"
+			default:
+				schema.Description = "Layout " + r.param
+			}
+		case "oneof":
+			for _, value := range splitOneOf(r.param) {
+				if isNumber {
+					if n, err := strconv.ParseFloat(value, 64); err == nil {
+						schema.Enum = append(schema.Enum, n)
+					}
+					continue
+				}
+				schema.Enum = append(schema.Enum, value)
+			}
+		case "min", "max", "len", "gt", "gte", "lt", "lte":
+			applyBound(schema, r, isString, isNumber)
+		}
+	}
+}
+
+func applyBound(schema *Schema, r rule, isString, isNumber bool) {
+	n, err := strconv.ParseFloat(r.param, 64)
+	if err != nil {
+		return
+	}
+
+	if isString {
+		length := int(n)
+		switch r.name {
+		case "min", "gte":
+			schema.MinLength = &length
+		case "max", "lte":
+			schema.MaxLength = &length
+		case "len":
+			schema.MinLength = &length
+			schema.MaxLength = &length
+		}
+		return
+	}
+
+	if !isNumber {
+		return
+	}
+
+	switch r.name {
+	case "min", "gte":
+		schema.Minimum = &n
+	case "max", "lte":
+		schema.Maximum = &n
+	case "gt":
+		schema.ExclusiveMinimum = &n
+	case "lt":
+		schema.ExclusiveMaximum = &n
+	case "len":
+		schema.Minimum = &n
+		schema.Maximum = &n
+	}
+}
+
+// splitOneOf splits a oneof parameter the way the validator does: on spaces,
+// with single quotes grouping values that contain spaces.
+func splitOneOf(param string) []string {
+	var values []string
+	var current strings.Builder
+	quoted := false
+
+	flush := func() {
+		if current.Len() > 0 {
+			values = append(values, current.String())
+			current.Reset()
+		}
+	}
+
+	for _, r := range param {
+		switch {
+		case r == '\'':
+			if quoted {
+				values = append(values, current.String())
+				current.Reset()
+			}
+			quoted = !quoted
+		case r == ' ' && !quoted:
+			flush()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	flush()
+
+	return values
+}
@@ -0,0 +1,77 @@
+package openapi
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type sample struct {
+	Name     string     `json:"name" validate:"required,max=100"`
+	Email    string     `json:"email" validate:"omitempty,email"`
+	Status   string     `json:"status" validate:"oneof=New 'In Progress'"`
+	Count    int        `json:"count" validate:"gte=1,lte=200"`
+	Digits   string     `json:"digits" validate:"numericstring"`
+	Day      string     `json:"day" validate:"datetime=2006-01-02"`
+	Due      *time.Time `json:"due"`
+	Limit    optional   `json:"limit"`
+	Internal string     `json:"-"`
+}
+
+type optional struct{}
+
+func (optional) NullableType() reflect.Type { return reflect.TypeOf(int64(0)) }
+
+func TestGeneratorSchema(t *testing.T) {
+	g := NewGenerator()
+
+	ref := g.Schema(sample{})
+	if ref.Ref != "#/components/schemas/sample" {
+		t.Fatalf("Expected a component reference, got '%s'", ref.Ref)
+	}
+
+	schema := g.Schemas()["sample"]
+	if !reflect.DeepEqual(schema.Required, []string{"name"}) {
+		t.Errorf("Expected required [name], got %v", schema.Required)
+	}
+
+	if _, ok := schema.Properties["Internal"]; ok {
+		t.Error("Expected fields tagged json:\"-\" to be skipped")
+	}
+
+	tests := []struct {
+		property string
+		check    func(*Schema) bool
+	}{
+		{"name", func(s *Schema) bool { return s.MaxLength != nil && *s.MaxLength == 100 }},
+		{"email", func(s *Schema) bool { return s.Format == "email" }},
+		{"status", func(s *Schema) bool { return reflect.DeepEqual(s.Enum, []any{"New", "In Progress"}) }},
+		{"count", func(s *Schema) bool { return *s.Minimum == 1 && *s.Maximum == 200 && s.Type == "integer" }},
+		{"digits", func(s *Schema) bool { return s.Pattern == "^[0-9]+$" }},
+		{"day", func(s *Schema) bool { return s.Format == "date" }},
+		{"due", func(s *Schema) bool {
+			return reflect.DeepEqual(s.Type, []string{"string", "null"}) && s.Format == "date-time"
+		}},
+		{"limit", func(s *Schema) bool {
+			return reflect.DeepEqual(s.Type, []string{"integer", "null"}) && s.Format == "int64"
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.property, func(t *testing.T) {
+			property, ok := schema.Properties[tt.property]
+			if !ok {
+				t.Fatalf("Expected property '%s'", tt.property)
+			}
+			if !tt.check(property) {
+				t.Errorf("Unexpected schema for '%s': %+v", tt.property, property)
+			}
+		})
+	}
+}
+
+func TestPathFromRoute(t *testing.T) {
+	if got := PathFromRoute("/api/v1/tasks/:id/comments"); got != "/api/v1/tasks/{id}/comments" {
+		t.Errorf("Expected '/api/v1/tasks/{id}/comments', got '%s'", got)
+	}
+}
@@ -0,0 +1,111 @@
+// Package patch implements the members of JSON Merge Patch (RFC 7396)
+// documents. A member missing from the document leaves the target as it is,
+// an explicit null clears it and any other value replaces it.
+package patch
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+)
+
+// MIMEMergePatchJSON is the media type of merge patch documents.
+const MIMEMergePatchJSON = "application/merge-patch+json"
+
+// Unmarshal decodes the merge patch document data into v, a pointer to a
+// struct. Unlike json.Unmarshal it reports which member failed to decode as
+// a *json.UnmarshalTypeError, also for members that are not plain types.
+func Unmarshal(data []byte, v any) error {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return err
+	}
+
+	target := reflect.ValueOf(v).Elem()
+	t := target.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		raw, ok := members[name]
+		if !ok || name == "" || name == "-" {
+			continue
+		}
+
+		if err := json.Unmarshal(raw, target.Field(i).Addr().Interface()); err != nil {
+			typeErr := &json.UnmarshalTypeError{Value: valueKind(raw), Type: field.Type}
+			if !errors.As(err, &typeErr) {
+				if nullable, ok := target.Field(i).Interface().(interface{ NullableType() reflect.Type }); ok {
+					typeErr.Type = nullable.NullableType()
+				}
+			}
+			typeErr.Struct = t.Name()
+			typeErr.Field = name
+			return typeErr
+		}
+	}
+	return nil
+}
+
+// valueKind names the JSON type of raw like json.UnmarshalTypeError does.
+func valueKind(raw json.RawMessage) string {
+	switch raw[0] {
+	case '"':
+		return "string"
+	case '{':
+		return "object"
+	case '[':
+		return "array"
+	case 't', 'f':
+		return "bool"
+	case 'n':
+		return "null"
+	default:
+		return "number"
+	}
+}
+
+// Field is a member of a merge patch. Set reports whether the member was
+// present and Valid whether it was not null.
+type Field[T any] struct {
+	Value T
+	Set   bool
+	Valid bool
+}
+
+// Of returns a present member, null unless valid.
+func Of[T any](value T, valid bool) Field[T] {
+	if !valid {
+		var zero T
+		value = zero
+	}
+	return Field[T]{Value: value, Set: true, Valid: valid}
+}
+
+func (f *Field[T]) UnmarshalJSON(data []byte) error {
+	var value T
+	if string(data) != "null" {
+		if err := json.Unmarshal(data, &value); err != nil {
+			return err
+		}
+		f.Valid = true
+	}
+	f.Value = value
+	f.Set = true
+	return nil
+}
+
+// Any returns the value, or nil when the member is null, for use as a query
+// argument.
+func (f Field[T]) Any() any {
+	if !f.Valid {
+		return nil
+	}
+	return f.Value
+}
+
+// NullableType is the type of the value; openapi.Generator documents the
+// field as that type or null.
+func (Field[T]) NullableType() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
@@ -0,0 +1,96 @@
+package patch
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+type document struct {
+	Title Field[string]  `json:"title"`
+	Cost  Field[float64] `json:"cost"`
+}
+
+func TestFieldUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		wantTitle Field[string]
+		wantCost  Field[float64]
+	}{
+		{
+			name: "missing members are not set",
+			body: `{}`,
+		},
+		{
+			name:      "null members are set but not valid",
+			body:      `{"title": null, "cost": null}`,
+			wantTitle: Field[string]{Set: true},
+			wantCost:  Field[float64]{Set: true},
+		},
+		{
+			name:      "values are set and valid",
+			body:      `{"title": "Fix the sink", "cost": 12.5}`,
+			wantTitle: Field[string]{Value: "Fix the sink", Set: true, Valid: true},
+			wantCost:  Field[float64]{Value: 12.5, Set: true, Valid: true},
+		},
+		{
+			name:     "zero values are valid",
+			body:     `{"cost": 0}`,
+			wantCost: Field[float64]{Set: true, Valid: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var doc document
+			if err := json.Unmarshal([]byte(tt.body), &doc); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if doc.Title != tt.wantTitle {
+				t.Errorf("Expected title %+v, got %+v", tt.wantTitle, doc.Title)
+			}
+			if doc.Cost != tt.wantCost {
+				t.Errorf("Expected cost %+v, got %+v", tt.wantCost, doc.Cost)
+			}
+		})
+	}
+}
+
+func TestUnmarshal(t *testing.T) {
+	var doc document
+	if err := Unmarshal([]byte(`{"title": null, "cost": 3}`), &doc); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if want := (Field[string]{Set: true}); doc.Title != want {
+		t.Errorf("Expected title %+v, got %+v", want, doc.Title)
+	}
+	if want := (Field[float64]{Value: 3, Set: true, Valid: true}); doc.Cost != want {
+		t.Errorf("Expected cost %+v, got %+v", want, doc.Cost)
+	}
+}
+
+func TestUnmarshalTypeError(t *testing.T) {
+	var doc document
+	err := Unmarshal([]byte(`{"cost": "twelve"}`), &doc)
+
+	var typeErr *json.UnmarshalTypeError
+	if !errors.As(err, &typeErr) {
+		t.Fatalf("Expected an UnmarshalTypeError, got %v", err)
+	}
+	if typeErr.Field != "cost" {
+		t.Errorf("Expected field cost, got %q", typeErr.Field)
+	}
+	if typeErr.Value != "string" {
+		t.Errorf("Expected value string, got %q", typeErr.Value)
+	}
+}
+
+func TestFieldAny(t *testing.T) {
+	if got := Of("", false).Any(); got != nil {
+		t.Errorf("Expected nil for a null field, got %v", got)
+	}
+	if got := Of(int64(3), true).Any(); got != int64(3) {
+		t.Errorf("Expected 3, got %v", got)
+	}
+}
@@ -0,0 +1,133 @@
+// Package realtime fans task changes out to the browsers that have the task
+// list open. Changes are announced by a trigger on the tasks table through
+// Postgres NOTIFY, so every app instance sees the changes made by all
+// others, and are streamed to browsers as Server-Sent Events.
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mjmarrazzo/maintenance-app/internal/logging"
+)
+
+// TaskChannel is the channel the notify_task_change trigger notifies.
+const TaskChannel = "task_changes"
+
+type Op string
+
+const (
+	OpInsert Op = "insert"
+	OpUpdate Op = "update"
+	OpDelete Op = "delete"
+)
+
+// TaskChange is the payload of a TaskChannel notification.
+type TaskChange struct {
+	Op     Op    `json:"op"`
+	TaskID int64 `json:"id"`
+}
+
+// subscriberBuffer is how many changes a slow subscriber may fall behind
+// before further changes are dropped for it.
+const subscriberBuffer = 64
+
+// Hub hands every published change to all current subscribers.
+type Hub struct {
+	mu          sync.Mutex
+	subscribers map[chan TaskChange]struct{}
+}
+
+func NewHub() *Hub {
+	return &Hub{subscribers: make(map[chan TaskChange]struct{})}
+}
+
+// Subscribe returns a channel receiving every change from now on and a
+// function to stop the subscription, which closes the channel.
+func (h *Hub) Subscribe() (<-chan TaskChange, func()) {
+	ch := make(chan TaskChange, subscriberBuffer)
+
+	h.mu.Lock()
+	h.subscribers[ch] = struct{}{}
+	h.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			h.mu.Lock()
+			delete(h.subscribers, ch)
+			h.mu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+// Publish never blocks; a subscriber whose buffer is full misses change.
+func (h *Hub) Publish(change TaskChange) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for ch := range h.subscribers {
+		select {
+		case ch <- change:
+		default:
+		}
+	}
+}
+
+// listenRetryDelay is how long Listen waits before reconnecting after the
+// listening connection failed.
+const listenRetryDelay = 5 * time.Second
+
+// Listen publishes the notifications on TaskChannel to hub until ctx is
+// done. It holds one connection taken out of pool and reconnects when it
+// fails; changes made while reconnecting are missed.
+func Listen(ctx context.Context, pool *pgxpool.Pool, hub *Hub) {
+	logger := logging.FromContext(ctx)
+
+	for {
+		err := listen(ctx, pool, hub)
+		if ctx.Err() != nil {
+			return
+		}
+		logger.Error("listening for task changes failed", "error", err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(listenRetryDelay):
+		}
+	}
+}
+
+func listen(ctx context.Context, pool *pgxpool.Pool, hub *Hub) error {
+	pooled, err := pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	// LISTEN belongs to the session, so the connection is not given back.
+	conn := pooled.Hijack()
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+TaskChannel); err != nil {
+		return err
+	}
+
+	logger := logging.FromContext(ctx)
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+
+		var change TaskChange
+		if err := json.Unmarshal([]byte(notification.Payload), &change); err != nil {
+			logger.Warn("ignoring malformed task change", "payload", notification.Payload, "error", err)
+			continue
+		}
+		hub.Publish(change)
+	}
+}
@@ -0,0 +1,84 @@
+package realtime
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHub(t *testing.T) {
+	hub := NewHub()
+
+	first, unsubscribeFirst := hub.Subscribe()
+	second, unsubscribeSecond := hub.Subscribe()
+	defer unsubscribeSecond()
+
+	change := TaskChange{Op: OpUpdate, TaskID: 42}
+	hub.Publish(change)
+
+	for _, ch := range []<-chan TaskChange{first, second} {
+		if got := <-ch; got != change {
+			t.Errorf("Expected %+v, got %+v", change, got)
+		}
+	}
+
+	unsubscribeFirst()
+	unsubscribeFirst()
+	if _, ok := <-first; ok {
+		t.Error("Expected the channel to be closed after unsubscribing")
+	}
+
+	hub.Publish(TaskChange{Op: OpDelete, TaskID: 42})
+	if got := <-second; got.Op != OpDelete {
+		t.Errorf("Expected the remaining subscriber to get the delete, got %+v", got)
+	}
+}
+
+func TestHubDropsChangesForSlowSubscribers(t *testing.T) {
+	hub := NewHub()
+	ch, unsubscribe := hub.Subscribe()
+	defer unsubscribe()
+
+	for i := range subscriberBuffer + 10 {
+		hub.Publish(TaskChange{Op: OpInsert, TaskID: int64(i)})
+	}
+
+	if len(ch) != subscriberBuffer {
+		t.Errorf("Expected %d buffered changes, got %d", subscriberBuffer, len(ch))
+	}
+}
+
+func TestEventWriteTo(t *testing.T) {
+	tests := []struct {
+		name     string
+		event    Event
+		expected string
+	}{
+		{
+			name:     "single line",
+			event:    Event{ID: "task-1", Name: "task-created", Data: "<li>Fix boiler</li>"},
+			expected: "id: task-1\nevent: task-created\ndata: <li>Fix boiler</li>\n\n",
+		},
+		{
+			name:     "multiple lines",
+			event:    Event{Name: "task-1", Data: "<li>\r\n  Fix boiler\n</li>"},
+			expected: "event: task-1\ndata: <li>\ndata:   Fix boiler\ndata: </li>\n\n",
+		},
+		{
+			name:     "empty data",
+			event:    Event{ID: "task-1", Name: "task-1"},
+			expected: "id: task-1\nevent: task-1\ndata: \n\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b strings.Builder
+			if _, err := tt.event.WriteTo(&b); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if b.String() != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, b.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package realtime
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Event is one Server-Sent Event. Data may span lines.
+type Event struct {
+	ID   string
+	Name string
+	Data string
+}
+
+// WriteTo writes e in the text/event-stream format.
+func (e Event) WriteTo(w io.Writer) (int64, error) {
+	var b strings.Builder
+	if e.ID != "" {
+		fmt.Fprintf(&b, "id: %s\n", e.ID)
+	}
+	if e.Name != "" {
+		fmt.Fprintf(&b, "event: %s\n", e.Name)
+	}
+	for _, line := range strings.Split(e.Data, "\n") {
+		fmt.Fprintf(&b, "data: %s\n", strings.TrimSuffix(line, "\r"))
+	}
+	b.WriteString("\n")
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// Comment returns a comment line, which clients ignore. It keeps idle
+// connections from being closed by proxies.
+func Comment(text string) string {
+	return ": " + text + "\n\n"
+}
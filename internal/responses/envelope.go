@@ -0,0 +1,31 @@
+package responses
+
+// Envelope wraps every successful JSON API response.
+type Envelope struct {
+	Data any   `json:"data"`
+	Meta *Meta `json:"meta,omitempty"`
+}
+
+type Meta struct {
+	Count  int `json:"count"`
+	Limit  int `json:"limit,omitempty"`
+	Offset int `json:"offset,omitempty"`
+}
+
+func NewEnvelope(data any) *Envelope {
+	return &Envelope{Data: data}
+}
+
+func NewListEnvelope[T any](items []T, limit, offset int) *Envelope {
+	if items == nil {
+		items = []T{}
+	}
+	return &Envelope{
+		Data: items,
+		Meta: &Meta{
+			Count:  len(items),
+			Limit:  limit,
+			Offset: offset,
+		},
+	}
+}
@@ -1,5 +1,67 @@
 package responses
 
+import (
+	"errors"
+	"net/http"
+)
+
+type ErrorKind string
+
+const (
+	KindValidation   ErrorKind = "validation"
+	KindNotFound     ErrorKind = "not_found"
+	KindConflict     ErrorKind = "conflict"
+	KindForbidden    ErrorKind = "forbidden"
+	KindUnauthorized ErrorKind = "unauthorized"
+	KindRateLimited  ErrorKind = "rate_limited"
+	KindInternal     ErrorKind = "internal"
+)
+
+// StatusCode maps an error kind to the HTTP status it is rendered with.
+func (k ErrorKind) StatusCode() int {
+	switch k {
+	case KindValidation:
+		return http.StatusBadRequest
+	case KindNotFound:
+		return http.StatusNotFound
+	case KindConflict:
+		return http.StatusConflict
+	case KindForbidden:
+		return http.StatusForbidden
+	case KindUnauthorized:
+		return http.StatusUnauthorized
+	case KindRateLimited:
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// AppError is the error type handlers, services and repositories return for
+// anything that is not a field validation failure. Err keeps the underlying
+// cause for logging and is never rendered.
+type AppError struct {
+	Kind    ErrorKind `json:"-"`
+	Code    string    `json:"code"`
+	Message string    `json:"message"`
+	Err     error     `json:"-"`
+}
+
+func (e *AppError) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *AppError) Unwrap() error {
+	return e.Err
+}
+
+func (e *AppError) StatusCode() int {
+	return e.Kind.StatusCode()
+}
+
 func NewValidationError(message string, parameters []string, violations []*ViolationsDetail) *ValidationError {
 	return &ValidationError{
 		Code:       "INVALID_FORMAT",
@@ -9,23 +71,66 @@ func NewValidationError(message string, parameters []string, violations []*Viola
 	}
 }
 
-func NewNotFoundError(message string) *ValidationError {
-	return &ValidationError{
+func NewNotFoundError(message string) *AppError {
+	return &AppError{
+		Kind:    KindNotFound,
 		Code:    "NOT_FOUND",
 		Message: message,
 	}
 }
 
-func NewConflictError(message string) *ValidationError {
-	return &ValidationError{
+func NewConflictError(message string) *AppError {
+	return &AppError{
+		Kind:    KindConflict,
 		Code:    "CONFLICT",
 		Message: message,
 	}
 }
 
-func NewInternalServerError(message string) *ValidationError {
-	return &ValidationError{
+func NewForbiddenError(message string) *AppError {
+	return &AppError{
+		Kind:    KindForbidden,
+		Code:    "FORBIDDEN",
+		Message: message,
+	}
+}
+
+func NewUnauthorizedError(message string) *AppError {
+	return &AppError{
+		Kind:    KindUnauthorized,
+		Code:    "UNAUTHORIZED",
+		Message: message,
+	}
+}
+
+func NewTooManyRequestsError(message string) *AppError {
+	return &AppError{
+		Kind:    KindRateLimited,
+		Code:    "TOO_MANY_REQUESTS",
+		Message: message,
+	}
+}
+
+func NewInternalServerError(message string) *AppError {
+	return &AppError{
+		Kind:    KindInternal,
 		Code:    "INTERNAL_SERVER_ERROR",
 		Message: message,
 	}
 }
+
+// WrapInternalError hides err behind a generic internal error while keeping
+// it available to errors.Is/As and the logs.
+func WrapInternalError(err error) *AppError {
+	appErr := NewInternalServerError("Internal server error")
+	appErr.Err = err
+	return appErr
+}
+
+func IsAppError(err error) (*AppError, bool) {
+	var appErr *AppError
+	if errors.As(err, &appErr) {
+		return appErr, true
+	}
+	return nil, false
+}
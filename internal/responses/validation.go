@@ -1,5 +1,10 @@
 package responses
 
+import (
+	"errors"
+	"net/http"
+)
+
 type ValidationError struct {
 	Code       string              `json:"code"`
 	Message    string              `json:"message"`
@@ -11,6 +16,10 @@ func (e ValidationError) Error() string {
 	return e.Message
 }
 
+func (e ValidationError) StatusCode() int {
+	return http.StatusBadRequest
+}
+
 type ValidationErrors struct {
 	Parameters []string
 	Violations []*ViolationsDetail
@@ -22,12 +31,14 @@ type ViolationsDetail struct {
 }
 
 func IsValidationError(err error) (*ValidationError, bool) {
-	if ve, ok := err.(ValidationError); ok {
-		return &ve, true
+	var vp *ValidationError
+	if errors.As(err, &vp) {
+		return vp, true
 	}
 
-	if ve, ok := err.(*ValidationError); ok {
-		return ve, true
+	var ve ValidationError
+	if errors.As(err, &ve) {
+		return &ve, true
 	}
 
 	return nil, false
@@ -0,0 +1,59 @@
+package security
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/mjmarrazzo/maintenance-app/components/common"
+	"github.com/mjmarrazzo/maintenance-app/internal/api"
+	"github.com/mjmarrazzo/maintenance-app/internal/logging"
+	"github.com/mjmarrazzo/maintenance-app/internal/responses"
+)
+
+const (
+	csrfCookie     = "_csrf"
+	csrfContextKey = "csrf"
+	// csrfFormField is accepted for forms submitted without htmx.
+	csrfFormField = "_csrf"
+)
+
+// CSRF rejects POST, PUT, PATCH and DELETE requests that do not carry the
+// token from the csrf cookie in the X-CSRF-Token header, which
+// common.BaseHtml adds to every htmx request. The token is made available to
+// templates through common.CSRFToken.
+//
+// Requests with an Authorization header are API calls authenticated by the
+// header rather than a cookie, and cross-site pages cannot send it, so they
+// are not checked.
+func CSRF() echo.MiddlewareFunc {
+	csrf := middleware.CSRFWithConfig(middleware.CSRFConfig{
+		Skipper: func(c echo.Context) bool {
+			return c.Request().Header.Get(echo.HeaderAuthorization) != ""
+		},
+		TokenLookup:    "header:" + echo.HeaderXCSRFToken + ",form:" + csrfFormField,
+		ContextKey:     csrfContextKey,
+		CookieName:     csrfCookie,
+		CookiePath:     "/",
+		CookieHTTPOnly: true,
+		CookieSecure:   api.SecureCookies(),
+		// Lax keeps the token stable when a page is opened from another site,
+		// e.g. at the end of single sign-on. The token itself is not a
+		// credential, the check is that the request carries it.
+		CookieSameSite: http.SameSiteLaxMode,
+		ErrorHandler: func(err error, c echo.Context) error {
+			logging.FromContext(c.Request().Context()).Warn("csrf check failed", "path", c.Request().URL.Path, "error", err)
+			return responses.NewForbiddenError("This page has expired. Please reload it and try again.")
+		},
+	})
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return csrf(func(c echo.Context) error {
+			if token, ok := c.Get(csrfContextKey).(string); ok {
+				req := c.Request()
+				c.SetRequest(req.WithContext(common.WithCSRFToken(req.Context(), token)))
+			}
+			return next(c)
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package security
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/labstack/echo/v4"
+	"github.com/mjmarrazzo/maintenance-app/components/common"
+	"github.com/mjmarrazzo/maintenance-app/internal/responses"
+)
+
+func TestHeaders(t *testing.T) {
+	tests := []struct {
+		name         string
+		forwardProto string
+		expectHSTS   bool
+	}{
+		{"http", "", false},
+		{"https behind proxy", "https", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.forwardProto != "" {
+				req.Header.Set(echo.HeaderXForwardedProto, tt.forwardProto)
+			}
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			var nonce string
+			err := Headers()(func(c echo.Context) error {
+				nonce = templ.GetNonce(c.Request().Context())
+				return nil
+			})(c)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if nonce == "" {
+				t.Fatal("Expected a nonce in the request context")
+			}
+			csp := rec.Header().Get(echo.HeaderContentSecurityPolicy)
+			if !strings.Contains(csp, "script-src 'self' 'nonce-"+nonce+"'") {
+				t.Errorf("Expected the CSP to allow the nonce, got '%s'", csp)
+			}
+			if !strings.Contains(csp, "frame-ancestors 'none'") {
+				t.Errorf("Expected the CSP to forbid framing, got '%s'", csp)
+			}
+			if hsts := rec.Header().Get(echo.HeaderStrictTransportSecurity); (hsts != "") != tt.expectHSTS {
+				t.Errorf("Expected HSTS %v, got '%s'", tt.expectHSTS, hsts)
+			}
+		})
+	}
+}
+
+func TestHeadersUseNewNonceEachRequest(t *testing.T) {
+	e := echo.New()
+	seen := make(map[string]bool)
+	for range 3 {
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+		err := Headers()(func(c echo.Context) error {
+			seen[templ.GetNonce(c.Request().Context())] = true
+			return nil
+		})(c)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	if len(seen) != 3 {
+		t.Errorf("Expected 3 distinct nonces, got %d", len(seen))
+	}
+}
+
+// csrfToken runs a GET through the middleware and returns the cookie it set
+// and the token handed to templates.
+func csrfToken(t *testing.T, e *echo.Echo) (*http.Cookie, string) {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+
+	var token string
+	err := CSRF()(func(c echo.Context) error {
+		token = common.CSRFToken(c.Request().Context())
+		return nil
+	})(c)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	cookies := rec.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != csrfCookie {
+		t.Fatalf("Expected the %s cookie, got %v", csrfCookie, cookies)
+	}
+	if token == "" || token != cookies[0].Value {
+		t.Fatalf("Expected the cookie token in the context, got '%s'", token)
+	}
+	return cookies[0], token
+}
+
+func TestCSRF(t *testing.T) {
+	e := echo.New()
+	cookie, token := csrfToken(t, e)
+
+	tests := []struct {
+		name          string
+		cookie        bool
+		header        string
+		authorization string
+		expectAllowed bool
+	}{
+		{"token in header", true, token, "", true},
+		{"missing token", true, "", "", false},
+		{"wrong token", true, "wrong", "", false},
+		{"missing cookie", false, token, "", false},
+		{"api token", false, "", "Bearer token", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/tasks", nil)
+			if tt.cookie {
+				req.AddCookie(cookie)
+			}
+			if tt.header != "" {
+				req.Header.Set(echo.HeaderXCSRFToken, tt.header)
+			}
+			if tt.authorization != "" {
+				req.Header.Set(echo.HeaderAuthorization, tt.authorization)
+			}
+			c := e.NewContext(req, httptest.NewRecorder())
+
+			called := false
+			err := CSRF()(func(c echo.Context) error {
+				called = true
+				return nil
+			})(c)
+
+			if called != tt.expectAllowed {
+				t.Errorf("Expected allowed %v, got %v", tt.expectAllowed, called)
+			}
+			if !tt.expectAllowed {
+				appErr, ok := responses.IsAppError(err)
+				if !ok || appErr.Kind != responses.KindForbidden {
+					t.Errorf("Expected a forbidden error, got %v", err)
+				}
+			}
+		})
+	}
+}
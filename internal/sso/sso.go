@@ -0,0 +1,261 @@
+// Package sso signs users in through OpenID Connect providers such as
+// Google Workspace or Microsoft Entra ID using the authorization code flow
+// with PKCE.
+package sso
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/mjmarrazzo/maintenance-app/internal/api"
+	"golang.org/x/oauth2"
+)
+
+// Config describes one provider. Providers are configured through the
+// environment, see LoadFromEnv.
+type Config struct {
+	// Name identifies the provider in URLs and the user_identities table.
+	Name         string
+	DisplayName  string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+	// AllowedDomains lists the email domains that may sign up without an
+	// invitation, e.g. the Google Workspace domain of the organization.
+	AllowedDomains []string
+	// TrustEmail treats the email claim as verified when the provider does
+	// not send email_verified, as Microsoft Entra ID does for work accounts.
+	TrustEmail bool
+}
+
+// Identity is what a provider asserted about the user in its ID token.
+type Identity struct {
+	Provider      string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	FirstName     string
+	LastName      string
+}
+
+var (
+	ErrUnknownProvider = errors.New("sso: unknown provider")
+	ErrNonceMismatch   = errors.New("sso: nonce does not match")
+)
+
+// Provider is a configured OpenID Connect provider. Discovery happens on
+// first use so an unreachable provider does not keep the app from starting.
+type Provider struct {
+	Config
+	redirectURL string
+
+	mu       sync.Mutex
+	oauth    *oauth2.Config
+	verifier *oidc.IDTokenVerifier
+}
+
+func NewProvider(cfg Config, redirectURL string) *Provider {
+	if len(cfg.Scopes) == 0 {
+		cfg.Scopes = []string{oidc.ScopeOpenID, "email", "profile"}
+	}
+	if cfg.DisplayName == "" {
+		cfg.DisplayName = cfg.Name
+	}
+	return &Provider{Config: cfg, redirectURL: redirectURL}
+}
+
+func (p *Provider) discover(ctx context.Context) (*oauth2.Config, *oidc.IDTokenVerifier, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.oauth != nil {
+		return p.oauth, p.verifier, nil
+	}
+
+	provider, err := oidc.NewProvider(ctx, p.Issuer)
+	if err != nil {
+		return nil, nil, fmt.Errorf("sso: discovering %s: %w", p.Name, err)
+	}
+
+	p.oauth = &oauth2.Config{
+		ClientID:     p.ClientID,
+		ClientSecret: p.ClientSecret,
+		Endpoint:     provider.Endpoint(),
+		RedirectURL:  p.redirectURL,
+		Scopes:       p.Scopes,
+	}
+	p.verifier = provider.Verifier(&oidc.Config{ClientID: p.ClientID})
+	return p.oauth, p.verifier, nil
+}
+
+// AuthCodeURL returns the URL to send the browser to. state and nonce must
+// be random and remembered until the callback, as must verifier, the PKCE
+// code verifier from oauth2.GenerateVerifier.
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce, verifier string) (string, error) {
+	config, _, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+	return config.AuthCodeURL(state, oidc.Nonce(nonce), oauth2.S256ChallengeOption(verifier)), nil
+}
+
+// Exchange redeems the authorization code and verifies the returned ID
+// token, including that it carries the nonce of this login.
+func (p *Provider) Exchange(ctx context.Context, code, verifier, nonce string) (*Identity, error) {
+	config, idTokenVerifier, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := config.Exchange(ctx, code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return nil, fmt.Errorf("sso: exchanging code: %w", err)
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return nil, errors.New("sso: token response has no id_token")
+	}
+
+	idToken, err := idTokenVerifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, fmt.Errorf("sso: verifying id_token: %w", err)
+	}
+	if idToken.Nonce != nonce {
+		return nil, ErrNonceMismatch
+	}
+
+	var claims struct {
+		Email         string `json:"email"`
+		EmailVerified *bool  `json:"email_verified"`
+		GivenName     string `json:"given_name"`
+		FamilyName    string `json:"family_name"`
+		Name          string `json:"name"`
+	}
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, fmt.Errorf("sso: decoding claims: %w", err)
+	}
+
+	identity := &Identity{
+		Provider:  p.Name,
+		Subject:   idToken.Subject,
+		Email:     strings.TrimSpace(claims.Email),
+		FirstName: claims.GivenName,
+		LastName:  claims.FamilyName,
+	}
+	if claims.EmailVerified != nil {
+		identity.EmailVerified = *claims.EmailVerified
+	} else {
+		identity.EmailVerified = p.TrustEmail
+	}
+	if identity.FirstName == "" && identity.LastName == "" {
+		identity.FirstName, identity.LastName, _ = strings.Cut(claims.Name, " ")
+	}
+
+	return identity, nil
+}
+
+// AllowsDomain reports whether email belongs to one of AllowedDomains.
+func (p *Provider) AllowsDomain(email string) bool {
+	_, domain, ok := strings.Cut(email, "@")
+	if !ok {
+		return false
+	}
+	return slices.ContainsFunc(p.AllowedDomains, func(allowed string) bool {
+		return strings.EqualFold(allowed, domain)
+	})
+}
+
+// Registry holds the configured providers in configuration order.
+type Registry struct {
+	providers []*Provider
+}
+
+func (r *Registry) Providers() []*Provider {
+	if r == nil {
+		return nil
+	}
+	return r.providers
+}
+
+func (r *Registry) Lookup(name string) (*Provider, error) {
+	for _, p := range r.Providers() {
+		if p.Name == name {
+			return p, nil
+		}
+	}
+	return nil, ErrUnknownProvider
+}
+
+var defaultRegistry = sync.OnceValues(func() (*Registry, error) {
+	return LoadFromEnv(api.BaseURL())
+})
+
+// Default returns the providers configured in the environment. The
+// configuration is read once.
+func Default() (*Registry, error) {
+	return defaultRegistry()
+}
+
+// LoadFromEnv configures the providers listed in OIDC_PROVIDERS, a comma
+// separated list of names. Each name reads its settings from variables
+// prefixed with OIDC_<NAME>_:
+//
+//	OIDC_PROVIDERS=google
+//	OIDC_GOOGLE_ISSUER=https://accounts.google.com
+//	OIDC_GOOGLE_CLIENT_ID=...
+//	OIDC_GOOGLE_CLIENT_SECRET=...
+//	OIDC_GOOGLE_DISPLAY_NAME=Google
+//	OIDC_GOOGLE_ALLOWED_DOMAINS=example.org
+//	OIDC_GOOGLE_SCOPES=openid email profile
+//	OIDC_GOOGLE_TRUST_EMAIL=false
+//
+// Callbacks are expected at <baseURL>/auth/oidc/<name>/callback.
+func LoadFromEnv(baseURL string) (*Registry, error) {
+	registry := &Registry{}
+	for _, name := range strings.Split(os.Getenv("OIDC_PROVIDERS"), ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+
+		prefix := "OIDC_" + strings.ToUpper(name) + "_"
+		cfg := Config{
+			Name:           name,
+			DisplayName:    os.Getenv(prefix + "DISPLAY_NAME"),
+			Issuer:         os.Getenv(prefix + "ISSUER"),
+			ClientID:       os.Getenv(prefix + "CLIENT_ID"),
+			ClientSecret:   os.Getenv(prefix + "CLIENT_SECRET"),
+			Scopes:         strings.Fields(os.Getenv(prefix + "SCOPES")),
+			AllowedDomains: splitList(os.Getenv(prefix + "ALLOWED_DOMAINS")),
+			TrustEmail:     os.Getenv(prefix+"TRUST_EMAIL") == "true",
+		}
+		if cfg.Issuer == "" || cfg.ClientID == "" {
+			return nil, fmt.Errorf("sso: %sISSUER and %sCLIENT_ID are required", prefix, prefix)
+		}
+		if _, err := registry.Lookup(name); err == nil {
+			return nil, fmt.Errorf("sso: provider %s configured twice", name)
+		}
+
+		redirectURL := baseURL + "/auth/oidc/" + name + "/callback"
+		registry.providers = append(registry.providers, NewProvider(cfg, redirectURL))
+	}
+	return registry, nil
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,324 @@
+package sso
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc/oidctest"
+	"golang.org/x/oauth2"
+)
+
+const (
+	testClientID     = "maintenance-app"
+	testClientSecret = "secret"
+	testRedirectURL  = "http://localhost:1323/auth/oidc/mock/callback"
+)
+
+// mockProvider is a minimal OpenID Connect provider. The authorization
+// endpoint immediately issues a code for the configured claims, and the
+// token endpoint enforces PKCE the way real providers do.
+type mockProvider struct {
+	*httptest.Server
+	key    *rsa.PrivateKey
+	claims map[string]any
+
+	mu    sync.Mutex
+	codes map[string]authorization
+}
+
+type authorization struct {
+	challenge string
+	nonce     string
+}
+
+func newMockProvider(t *testing.T, claims map[string]any) *mockProvider {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	m := &mockProvider{key: key, claims: claims, codes: make(map[string]authorization)}
+	discovery := &oidctest.Server{
+		PublicKeys: []oidctest.PublicKey{{PublicKey: key.Public(), KeyID: "test-key", Algorithm: "RS256"}},
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/.well-known/openid-configuration", discovery)
+	mux.Handle("/keys", discovery)
+	mux.HandleFunc("/auth", m.authorize)
+	mux.HandleFunc("/token", m.token)
+
+	m.Server = httptest.NewServer(mux)
+	discovery.SetIssuer(m.URL)
+	t.Cleanup(m.Close)
+
+	return m
+}
+
+func (m *mockProvider) authorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if query.Get("code_challenge_method") != "S256" || query.Get("code_challenge") == "" {
+		http.Error(w, "PKCE required", http.StatusBadRequest)
+		return
+	}
+
+	m.mu.Lock()
+	code := fmt.Sprintf("code-%d", len(m.codes))
+	m.codes[code] = authorization{challenge: query.Get("code_challenge"), nonce: query.Get("nonce")}
+	m.mu.Unlock()
+
+	redirect, _ := url.Parse(query.Get("redirect_uri"))
+	redirect.RawQuery = url.Values{"code": {code}, "state": {query.Get("state")}}.Encode()
+	http.Redirect(w, r, redirect.String(), http.StatusFound)
+}
+
+func (m *mockProvider) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	m.mu.Lock()
+	auth, ok := m.codes[r.PostForm.Get("code")]
+	delete(m.codes, r.PostForm.Get("code"))
+	m.mu.Unlock()
+
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if !ok || base64.RawURLEncoding.EncodeToString(sum[:]) != auth.challenge {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":"invalid_grant"}`))
+		return
+	}
+
+	claims := map[string]any{
+		"iss":   m.URL,
+		"aud":   testClientID,
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"iat":   time.Now().Unix(),
+		"nonce": auth.nonce,
+	}
+	for k, v := range m.claims {
+		claims[k] = v
+	}
+	payload, _ := json.Marshal(claims)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"access_token": "access",
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     oidctest.SignIDToken(m.key, "test-key", "RS256", string(payload)),
+	})
+}
+
+// login runs the browser side of the flow: it follows the authorization
+// URL and returns the code and state handed to the callback.
+func login(t *testing.T, p *Provider, state, nonce, verifier string) (string, string) {
+	t.Helper()
+
+	authURL, err := p.AuthCodeURL(context.Background(), state, nonce, verifier)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	resp, err := client.Get(authURL)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusFound {
+		t.Fatalf("Expected redirect to the callback, got %d", resp.StatusCode)
+	}
+	callback, _ := url.Parse(resp.Header.Get("Location"))
+	return callback.Query().Get("code"), callback.Query().Get("state")
+}
+
+func newTestProvider(issuer string, trustEmail bool) *Provider {
+	return NewProvider(Config{
+		Name:         "mock",
+		Issuer:       issuer,
+		ClientID:     testClientID,
+		ClientSecret: testClientSecret,
+		TrustEmail:   trustEmail,
+	}, testRedirectURL)
+}
+
+func TestLogin(t *testing.T) {
+	mock := newMockProvider(t, map[string]any{
+		"sub":            "user-1",
+		"email":          "Ada@Example.org",
+		"email_verified": true,
+		"given_name":     "Ada",
+		"family_name":    "Lovelace",
+	})
+	p := newTestProvider(mock.URL, false)
+
+	verifier := oauth2.GenerateVerifier()
+	code, state := login(t, p, "state-1", "nonce-1", verifier)
+	if state != "state-1" {
+		t.Errorf("Expected state 'state-1', got '%s'", state)
+	}
+
+	identity, err := p.Exchange(context.Background(), code, verifier, "nonce-1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := Identity{
+		Provider:      "mock",
+		Subject:       "user-1",
+		Email:         "Ada@Example.org",
+		EmailVerified: true,
+		FirstName:     "Ada",
+		LastName:      "Lovelace",
+	}
+	if *identity != expected {
+		t.Errorf("Expected %+v, got %+v", expected, *identity)
+	}
+}
+
+func TestLoginRejectsWrongVerifier(t *testing.T) {
+	mock := newMockProvider(t, map[string]any{"sub": "user-1"})
+	p := newTestProvider(mock.URL, false)
+
+	code, _ := login(t, p, "state", "nonce", oauth2.GenerateVerifier())
+	if _, err := p.Exchange(context.Background(), code, oauth2.GenerateVerifier(), "nonce"); err == nil {
+		t.Error("Expected an error for a mismatched code verifier")
+	}
+}
+
+func TestLoginRejectsWrongNonce(t *testing.T) {
+	mock := newMockProvider(t, map[string]any{"sub": "user-1"})
+	p := newTestProvider(mock.URL, false)
+
+	verifier := oauth2.GenerateVerifier()
+	code, _ := login(t, p, "state", "nonce", verifier)
+	if _, err := p.Exchange(context.Background(), code, verifier, "other-nonce"); err != ErrNonceMismatch {
+		t.Errorf("Expected ErrNonceMismatch, got %v", err)
+	}
+}
+
+func TestEmailVerification(t *testing.T) {
+	tests := []struct {
+		name       string
+		claims     map[string]any
+		trustEmail bool
+		expected   bool
+	}{
+		{"claim false", map[string]any{"email_verified": false}, true, false},
+		{"claim missing", map[string]any{}, false, false},
+		{"claim missing but trusted", map[string]any{}, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.claims["sub"] = "user-1"
+			tt.claims["email"] = "ada@example.org"
+			tt.claims["name"] = "Ada Lovelace"
+			mock := newMockProvider(t, tt.claims)
+			p := newTestProvider(mock.URL, tt.trustEmail)
+
+			verifier := oauth2.GenerateVerifier()
+			code, _ := login(t, p, "state", "nonce", verifier)
+			identity, err := p.Exchange(context.Background(), code, verifier, "nonce")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if identity.EmailVerified != tt.expected {
+				t.Errorf("Expected email verified %v, got %v", tt.expected, identity.EmailVerified)
+			}
+			if identity.FirstName != "Ada" || identity.LastName != "Lovelace" {
+				t.Errorf("Expected name split from the name claim, got '%s' '%s'", identity.FirstName, identity.LastName)
+			}
+		})
+	}
+}
+
+func TestAllowsDomain(t *testing.T) {
+	p := NewProvider(Config{Name: "mock", AllowedDomains: []string{"example.org"}}, testRedirectURL)
+
+	tests := []struct {
+		email    string
+		expected bool
+	}{
+		{"ada@example.org", true},
+		{"ada@EXAMPLE.org", true},
+		{"ada@example.org.evil.com", false},
+		{"ada@sub.example.org", false},
+		{"not-an-email", false},
+	}
+
+	for _, tt := range tests {
+		if got := p.AllowsDomain(tt.email); got != tt.expected {
+			t.Errorf("For %s expected %v, got %v", tt.email, tt.expected, got)
+		}
+	}
+}
+
+func TestLoadFromEnv(t *testing.T) {
+	t.Setenv("OIDC_PROVIDERS", "google, Microsoft")
+	t.Setenv("OIDC_GOOGLE_ISSUER", "https://accounts.google.com")
+	t.Setenv("OIDC_GOOGLE_CLIENT_ID", "google-client")
+	t.Setenv("OIDC_GOOGLE_ALLOWED_DOMAINS", "example.org, example.com")
+	t.Setenv("OIDC_MICROSOFT_ISSUER", "https://login.microsoftonline.com/tenant/v2.0")
+	t.Setenv("OIDC_MICROSOFT_CLIENT_ID", "microsoft-client")
+	t.Setenv("OIDC_MICROSOFT_DISPLAY_NAME", "Microsoft")
+	t.Setenv("OIDC_MICROSOFT_TRUST_EMAIL", "true")
+
+	registry, err := LoadFromEnv("https://maintenance.example.org")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(registry.Providers()) != 2 {
+		t.Fatalf("Expected 2 providers, got %d", len(registry.Providers()))
+	}
+
+	google, err := registry.Lookup("google")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if google.DisplayName != "google" {
+		t.Errorf("Expected display name to default to the name, got '%s'", google.DisplayName)
+	}
+	if strings.Join(google.AllowedDomains, ",") != "example.org,example.com" {
+		t.Errorf("Expected allowed domains, got %v", google.AllowedDomains)
+	}
+	if google.redirectURL != "https://maintenance.example.org/auth/oidc/google/callback" {
+		t.Errorf("Expected callback URL, got '%s'", google.redirectURL)
+	}
+
+	microsoft, _ := registry.Lookup("microsoft")
+	if !microsoft.TrustEmail {
+		t.Error("Expected TrustEmail for microsoft")
+	}
+
+	if _, err := registry.Lookup("github"); err != ErrUnknownProvider {
+		t.Errorf("Expected ErrUnknownProvider, got %v", err)
+	}
+
+	t.Setenv("OIDC_GOOGLE_CLIENT_ID", "")
+	if _, err := LoadFromEnv("https://maintenance.example.org"); err == nil {
+		t.Error("Expected an error for a provider without a client id")
+	}
+}
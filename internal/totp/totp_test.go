@@ -0,0 +1,102 @@
+package totp
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfcSecret is the SHA1 seed used by the test vectors in RFC 6238 appendix B.
+var rfcSecret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+func TestRFC6238Vectors(t *testing.T) {
+	tests := []struct {
+		unix     int64
+		expected string
+	}{
+		{59, "94287082"},
+		{1111111109, "07081804"},
+		{1111111111, "14050471"},
+		{1234567890, "89005924"},
+		{2000000000, "69279037"},
+		{20000000000, "65353130"},
+	}
+
+	key, err := decodeSecret(rfcSecret)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for _, tt := range tests {
+		got := hotp(key, uint64(Counter(time.Unix(tt.unix, 0))), 8)
+		if got != tt.expected {
+			t.Errorf("At %d expected %s, got %s", tt.unix, tt.expected, got)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	code, err := Code(rfcSecret, now)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if code != "050471" {
+		t.Fatalf("Expected code 050471, got %s", code)
+	}
+
+	tests := []struct {
+		name     string
+		code     string
+		at       time.Time
+		expected bool
+	}{
+		{"current step", code, now, true},
+		{"with spaces", "050 471", now, true},
+		{"previous step", code, now.Add(Period), true},
+		{"next step", code, now.Add(-Period), true},
+		{"outside skew", code, now.Add(2 * Period), false},
+		{"wrong code", "000000", now, false},
+		{"wrong length", "05047", now, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counter, ok := Validate(rfcSecret, tt.code, tt.at)
+			if ok != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, ok)
+			}
+			if ok && counter != Counter(now) {
+				t.Errorf("Expected counter %d, got %d", Counter(now), counter)
+			}
+		})
+	}
+}
+
+func TestProvisioningURI(t *testing.T) {
+	uri := ProvisioningURI("Groundwork", "ada@example.com", "JBSWY3DPEHPK3PXP")
+
+	if !strings.HasPrefix(uri, "otpauth://totp/Groundwork:ada@example.com?") {
+		t.Errorf("Expected otpauth label, got '%s'", uri)
+	}
+	for _, part := range []string{"secret=JBSWY3DPEHPK3PXP", "issuer=Groundwork", "digits=6", "period=30"} {
+		if !strings.Contains(uri, part) {
+			t.Errorf("Expected '%s' in '%s'", part, uri)
+		}
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(secret) != 32 {
+		t.Errorf("Expected 32 characters, got %d", len(secret))
+	}
+	if _, err := Code(secret, time.Now()); err != nil {
+		t.Errorf("Expected generated secret to be usable, got %v", err)
+	}
+}
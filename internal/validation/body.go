@@ -3,33 +3,89 @@ package validation
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"io"
-	"log"
 	"reflect"
 	"strings"
 
+	ut "github.com/go-playground/universal-translator"
 	"github.com/labstack/echo/v4"
+	"github.com/mjmarrazzo/maintenance-app/internal/i18n"
+	"github.com/mjmarrazzo/maintenance-app/internal/logging"
+	"github.com/mjmarrazzo/maintenance-app/internal/patch"
 	"github.com/mjmarrazzo/maintenance-app/internal/responses"
 )
 
 func BindBody(e echo.Context, i interface{}) error {
-	// if err := validateNoExtraFields(e, i); err != nil {
-	// 	e.Logger().Print("failed to validate extra fields", err)
-	// 	return err
-	// }
+	if isJSONRequest(e) {
+		if err := validateNoExtraFields(e, i); err != nil {
+			return err
+		}
+	}
 
 	if err := e.Bind(i); err != nil {
 		return HandleBindError(e, err, reflect.TypeOf(i))
 	}
 
 	if err := ValidateStruct(i); err != nil {
-		return HandleValidationErrors(err)
+		return TranslateValidationErrors(err, translator(e))
+	}
+
+	return nil
+}
+
+// BindMergePatch binds a JSON Merge Patch document, sent as
+// application/merge-patch+json or application/json, see patch.Unmarshal.
+func BindMergePatch(e echo.Context, i interface{}) error {
+	contentType := e.Request().Header.Get(echo.HeaderContentType)
+	if !strings.HasPrefix(contentType, patch.MIMEMergePatchJSON) && !isJSONRequest(e) {
+		return echo.ErrUnsupportedMediaType
+	}
+
+	if err := validateNoExtraFields(e, i); err != nil {
+		return err
+	}
+
+	body, err := io.ReadAll(e.Request().Body)
+	if err != nil {
+		return err
+	}
+	if err := patch.Unmarshal(body, i); err != nil {
+		return HandleBindError(e, err, reflect.TypeOf(i))
+	}
+
+	if err := ValidateStruct(i); err != nil {
+		return TranslateValidationErrors(err, translator(e))
 	}
 
 	return nil
 }
 
+// BindQueryParams binds and validates query parameters only, ignoring any
+// request body.
+func BindQueryParams(e echo.Context, i interface{}) error {
+	if err := (&echo.DefaultBinder{}).BindQueryParams(e, i); err != nil {
+		return HandleBindError(e, err, reflect.TypeOf(i))
+	}
+
+	if err := ValidateStruct(i); err != nil {
+		return TranslateValidationErrors(err, translator(e))
+	}
+
+	return nil
+}
+
+// translator translates violations into the locale of the request.
+func translator(e echo.Context) ut.Translator {
+	return i18n.Translator(i18n.Locale(e.Request().Context()))
+}
+
+func isJSONRequest(e echo.Context) bool {
+	return strings.HasPrefix(e.Request().Header.Get(echo.HeaderContentType), echo.MIMEApplicationJSON)
+}
+
 func HandleBindError(e echo.Context, err error, targetType reflect.Type) error {
+	logger := logging.FromContext(e.Request().Context())
 	validationResult := &responses.ValidationErrors{
 		Parameters: []string{},
 		Violations: []*responses.ViolationsDetail{},
@@ -44,14 +100,14 @@ func HandleBindError(e echo.Context, err error, targetType reflect.Type) error {
 		validationResult.Parameters = append(validationResult.Parameters, fieldName)
 		validationResult.Violations = append(validationResult.Violations, &responses.ViolationsDetail{
 			Name:    fieldName,
-			Message: GetExpectedTypeErrorMessage(expectedType, value),
+			Message: TranslateExpectedTypeErrorMessage(expectedType, value, translator(e)),
 		})
 	case *json.SyntaxError:
 		return NewValidationError("Invalid JSON syntax", validationResult)
 	case *echo.HTTPError:
 		ie := typedErr.Internal
 		if ie != nil {
-			log.Printf("Recursively handling internal type: %T\n", ie)
+			logger.Debug("handling internal bind error", "type", fmt.Sprintf("%T", ie))
 			return HandleBindError(e, ie, targetType)
 		}
 	case *responses.ValidationError:
@@ -59,7 +115,7 @@ func HandleBindError(e echo.Context, err error, targetType reflect.Type) error {
 		validationResult.Violations = append(validationResult.Violations, typedErr.Violations...)
 
 	default:
-		log.Printf("Unknown error type: %T, message: %v\n", err, err)
+		logger.Warn("unknown bind error", "type", fmt.Sprintf("%T", err), "error", err)
 	}
 
 	return NewValidationError("Validation failed", validationResult)
@@ -72,6 +128,9 @@ func validateNoExtraFields(e echo.Context, obj interface{}) error {
 	}
 
 	e.Request().Body = io.NopCloser(bytes.NewReader(body))
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
 
 	var requestMap map[string]interface{}
 	if err := json.Unmarshal(body, &requestMap); err != nil {
@@ -5,7 +5,9 @@ import (
 	"regexp"
 	"strings"
 
+	ut "github.com/go-playground/universal-translator"
 	"github.com/go-playground/validator/v10"
+	"github.com/mjmarrazzo/maintenance-app/internal/i18n"
 	"github.com/mjmarrazzo/maintenance-app/internal/responses"
 )
 
@@ -27,7 +29,15 @@ func GetValidationError(err error) (*responses.ValidationError, bool) {
 	return &valErr, ok
 }
 
+// HandleValidationErrors turns the errors of ValidateStruct into violations
+// described in English.
 func HandleValidationErrors(err error) error {
+	return TranslateValidationErrors(err, nil)
+}
+
+// TranslateValidationErrors turns the errors of ValidateStruct into
+// violations described in the language of trans.
+func TranslateValidationErrors(err error, trans ut.Translator) error {
 	if err == nil {
 		return nil
 	}
@@ -56,7 +66,7 @@ func HandleValidationErrors(err error) error {
 
 		validationResult.Violations = append(validationResult.Violations, &responses.ViolationsDetail{
 			Name:    field,
-			Message: GetErrorMessage(err),
+			Message: TranslateErrorMessage(err, trans),
 		})
 	}
 
@@ -84,48 +94,105 @@ func findParentField(namespace string) string {
 	return parts[1]
 }
 
+// GetErrorMessage describes err in English.
 func GetErrorMessage(err validator.FieldError) string {
+	key, params := errorMessage(err)
+	return i18n.Format(key, params...)
+}
+
+// TranslateErrorMessage describes err in the language of trans. Tags without
+// a message of their own get the go-playground translation, if there is one.
+func TranslateErrorMessage(err validator.FieldError, trans ut.Translator) string {
+	key, params := errorMessage(err)
+	if key == unknownTagMessage && trans != nil {
+		if message := err.Translate(trans); message != "" && message != err.Error() {
+			return message
+		}
+	}
+	return i18n.Translate(trans, key, params...)
+}
+
+const unknownTagMessage = "Failed validation: {0}"
+
+// errorMessage returns the catalog key describing err and its parameters.
+func errorMessage(err validator.FieldError) (string, []string) {
 	switch err.Tag() {
 	case "required":
-		return "This field is required"
+		return "This field is required", nil
 	case "email":
-		return "Invalid email format"
+		return "Invalid email format", nil
 	case "min":
-		return fmt.Sprintf("Should be at least %s", err.Param())
+		return "Should be at least {0}", []string{err.Param()}
 	case "max":
-		return fmt.Sprintf("Should be at most %s", err.Param())
+		return "Should be at most {0}", []string{err.Param()}
 	case "ascii":
-		return "Should contain only ASCII characters"
+		return "Should contain only ASCII characters", nil
 	case "numericstring":
-		return "Should be a numeric string"
+		if rule, param, ok := strings.Cut(err.Param(), "="); ok && numberRules[rule] != "" {
+			return numberRules[rule], []string{param}
+		}
+		return "Should be a numeric string", nil
+	case "oneof":
+		return "Should be one of {0}", []string{strings.Join(oneOfValues(err.Param()), ", ")}
+	case "date":
+		return "Should be a date like {0}", []string{dateLayouts[0]}
+	case "datetime":
+		return "Should be formatted like {0}", []string{err.Param()}
+	case "timezone":
+		return "Should be a time zone like {0}", []string{"America/New_York"}
+	case "http_url":
+		return "Should be an http or https URL", nil
 	case "gt":
 		if err.Param() == "0" {
-			return "Should be a positive number"
+			return "Should be a positive number", nil
 		}
-		return fmt.Sprintf("Should be greater than %s", err.Param())
+		return "Should be greater than {0}", []string{err.Param()}
 	case "gte":
 		if err.Param() == "1" {
-			return "Should be a positive number or zero"
+			return "Should be a positive number or zero", nil
 		}
-		return fmt.Sprintf("Should be greater than or equal to %s", err.Param())
+		return "Should be greater than or equal to {0}", []string{err.Param()}
 	case "lt":
 		if err.Param() == "0" {
-			return "Should be a negative number"
+			return "Should be a negative number", nil
 		}
-		return fmt.Sprintf("Should be less than %s", err.Param())
+		return "Should be less than {0}", []string{err.Param()}
 	case "lte":
 		if err.Param() == "-1" {
-			return "Should be a negative number or zero"
+			return "Should be a negative number or zero", nil
 		}
-		return fmt.Sprintf("Should be less than or equal to %s", err.Param())
+		return "Should be less than or equal to {0}", []string{err.Param()}
 	default:
-		return fmt.Sprintf("Failed validation: %s", err.Tag())
+		return unknownTagMessage, []string{err.Tag()}
 	}
 }
 
+var numberRules = map[string]string{
+	"gt":  "Should be a number greater than {0}",
+	"gte": "Should be a number greater than or equal to {0}",
+	"lt":  "Should be a number less than {0}",
+	"lte": "Should be a number less than or equal to {0}",
+}
+
+var oneOfRegex = regexp.MustCompile(`'[^']*'|\S+`)
+
+// oneOfValues splits the parameter of oneof, where values with spaces are
+// quoted.
+func oneOfValues(param string) []string {
+	values := oneOfRegex.FindAllString(param, -1)
+	for i, value := range values {
+		values[i] = strings.Trim(value, "'")
+	}
+	return values
+}
+
 func GetExpectedTypeErrorMessage(expectedType string, value interface{}) string {
+	return TranslateExpectedTypeErrorMessage(expectedType, value, nil)
+}
+
+func TranslateExpectedTypeErrorMessage(expectedType string, value interface{}, trans ut.Translator) string {
 	if value == nil {
-		return fmt.Sprintf("Expected type <%s>", expectedType)
+		return i18n.Translate(trans, "Expected type <{0}>", expectedType)
 	}
-	return fmt.Sprintf("Expected type <%s>, but got <%s>", expectedType, value)
+	return i18n.Translate(trans, "Expected type <{0}>, but got <{1}>", expectedType, fmt.Sprint(value))
 }
@@ -125,6 +125,26 @@ func TestGetErrorMessage(t *testing.T) {
 			fieldError:      mockFieldError{tag: "ascii"},
 			expectedMessage: "Should contain only ASCII characters",
 		},
+		{
+			name:            "numericstring validation with a rule",
+			fieldError:      mockFieldError{tag: "numericstring", param: "gte=0"},
+			expectedMessage: "Should be a number greater than or equal to 0",
+		},
+		{
+			name:            "oneof validation with quoted values",
+			fieldError:      mockFieldError{tag: "oneof", param: "New 'In Progress' Completed"},
+			expectedMessage: "Should be one of New, In Progress, Completed",
+		},
+		{
+			name:            "date validation",
+			fieldError:      mockFieldError{tag: "date"},
+			expectedMessage: "Should be a date like 2006-01-02",
+		},
+		{
+			name:            "http_url validation",
+			fieldError:      mockFieldError{tag: "http_url"},
+			expectedMessage: "Should be an http or https URL",
+		},
 		{
 			name:            "gt validation with 0",
 			fieldError:      mockFieldError{tag: "gt", param: "0"},
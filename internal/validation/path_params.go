@@ -6,6 +6,7 @@ import (
 	"strconv"
 	"strings"
 
+	ut "github.com/go-playground/universal-translator"
 	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
 	"github.com/mjmarrazzo/maintenance-app/internal/responses"
@@ -13,11 +14,11 @@ import (
 
 func BindPathParams(c echo.Context, i interface{}) error {
 	if err := bindPathParamsOnly(c, i); err != nil {
-		return handlePathParamError(i, err)
+		return handlePathParamError(i, err, translator(c))
 	}
 
 	if err := ValidateStruct(i); err != nil {
-		return handlePathParamError(i, err)
+		return handlePathParamError(i, err, translator(c))
 	}
 
 	return nil
@@ -106,7 +107,7 @@ func (e *PathParamError) Error() string {
 		e.ParamName, e.ParamValue, e.ExpectedType)
 }
 
-func handlePathParamError(i interface{}, err error) error {
+func handlePathParamError(i interface{}, err error, trans ut.Translator) error {
 	validationResult := &responses.ValidationErrors{
 		Parameters: []string{},
 		Violations: []*responses.ViolationsDetail{},
@@ -117,7 +118,7 @@ func handlePathParamError(i interface{}, err error) error {
 		validationResult.Parameters = append(validationResult.Parameters, typedErr.ParamName)
 		validationResult.Violations = append(validationResult.Violations, &responses.ViolationsDetail{
 			Name:    typedErr.ParamName,
-			Message: GetExpectedTypeErrorMessage(typedErr.ExpectedType, nil),
+			Message: TranslateExpectedTypeErrorMessage(typedErr.ExpectedType, nil, trans),
 		})
 
 	case validator.ValidationErrors:
@@ -128,7 +129,7 @@ func handlePathParamError(i interface{}, err error) error {
 			validationResult.Parameters = append(validationResult.Parameters, paramName)
 			validationResult.Violations = append(validationResult.Violations, &responses.ViolationsDetail{
 				Name:    paramName,
-				Message: GetErrorMessage(e),
+				Message: TranslateErrorMessage(e, trans),
 			})
 		}
 
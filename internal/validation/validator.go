@@ -6,11 +6,18 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	ut "github.com/go-playground/universal-translator"
 	"github.com/go-playground/validator/v10"
+	en_translations "github.com/go-playground/validator/v10/translations/en"
+	es_translations "github.com/go-playground/validator/v10/translations/es"
+	"github.com/mjmarrazzo/maintenance-app/internal/i18n"
+	"github.com/mjmarrazzo/maintenance-app/internal/patch"
 )
 
+// ValidatorInstance holds the validator and the translator of the default
+// locale. Translators of the other locales come from i18n.Translator.
 type ValidatorInstance struct {
 	Validator  *validator.Validate
 	Translator ut.Translator
@@ -31,10 +38,13 @@ func GetValidator() *ValidatorInstance {
 			return name
 		})
 		RegisterNumericStringValidator(v)
+		RegisterDateValidator(v)
+		RegisterPatchTypes(v)
+		RegisterTranslations(v)
 
 		instance = &ValidatorInstance{
 			Validator:  v,
-			Translator: nil,
+			Translator: i18n.Translator(i18n.DefaultLocale),
 		}
 	})
 
@@ -58,15 +68,8 @@ func RegisterNumericStringValidator(v *validator.Validate) {
 			return false
 		}
 
-		tag := fl.GetTag()
-
-		additionalTags := tag
-		if strings.Contains(additionalTags, "numericstring") {
-			additionalTags = strings.ReplaceAll(additionalTags, "numericstring,", "")
-			additionalTags = strings.ReplaceAll(additionalTags, ",numericstring", "")
-			additionalTags = strings.ReplaceAll(additionalTags, "numericstring", "")
-		}
-
+		// numericstring=gte=0 applies gte=0 to the number.
+		additionalTags := fl.Param()
 		if additionalTags == "" {
 			return true
 		}
@@ -88,3 +91,58 @@ func RegisterNumericStringValidator(v *validator.Validate) {
 		panic(err)
 	}
 }
+
+// RegisterTranslations registers the go-playground messages of every
+// supported locale. TranslateErrorMessage falls back to them for tags it
+// has no message of its own for.
+func RegisterTranslations(v *validator.Validate) {
+	for _, trans := range i18n.Translators() {
+		var err error
+		switch trans.Locale() {
+		case i18n.Spanish:
+			err = es_translations.RegisterDefaultTranslations(v, trans)
+		default:
+			err = en_translations.RegisterDefaultTranslations(v, trans)
+		}
+		if err != nil {
+			panic(err)
+		}
+	}
+}
+
+// dateLayouts are what the date tag accepts: the value of date inputs or
+// an RFC 3339 timestamp.
+var dateLayouts = []string{"2006-01-02", time.RFC3339Nano}
+
+func RegisterDateValidator(v *validator.Validate) {
+	err := v.RegisterValidation("date", func(fl validator.FieldLevel) bool {
+		if fl.Field().Kind() != reflect.String || fl.Field().String() == "" {
+			return true
+		}
+		for _, layout := range dateLayouts {
+			if _, err := time.Parse(layout, fl.Field().String()); err == nil {
+				return true
+			}
+		}
+		return false
+	})
+
+	if err != nil {
+		panic(err)
+	}
+}
+
+// RegisterPatchTypes validates the value of patch.Field members. Members
+// that are missing or null are empty, so tags should start with omitempty.
+func RegisterPatchTypes(v *validator.Validate) {
+	v.RegisterCustomTypeFunc(func(field reflect.Value) interface{} {
+		return field.Interface().(interface{ Any() any }).Any()
+	},
+		patch.Field[string]{},
+		patch.Field[int]{},
+		patch.Field[int64]{},
+		patch.Field[float64]{},
+		patch.Field[bool]{},
+		patch.Field[time.Time]{},
+	)
+}
@@ -0,0 +1,86 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/mjmarrazzo/maintenance-app/internal/i18n"
+	"github.com/mjmarrazzo/maintenance-app/internal/patch"
+	"github.com/mjmarrazzo/maintenance-app/internal/responses"
+)
+
+type costRequest struct {
+	Cost string `json:"cost" validate:"omitempty,numericstring=gte=0"`
+	Due  string `json:"due" validate:"omitempty,date"`
+}
+
+type costPatch struct {
+	Title patch.Field[string]  `json:"title" validate:"omitempty,max=5"`
+	Cost  patch.Field[float64] `json:"cost" validate:"omitempty,gte=0"`
+}
+
+func TestValidateStruct(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   any
+		isValid bool
+	}{
+		{"empty request", costRequest{}, true},
+		{"decimal cost", costRequest{Cost: "12.50"}, true},
+		{"negative cost", costRequest{Cost: "-1"}, false},
+		{"cost that is no number", costRequest{Cost: "twelve"}, false},
+		{"date input", costRequest{Due: "2026-03-01"}, true},
+		{"timestamp", costRequest{Due: "2026-03-01T17:00:00Z"}, true},
+		{"malformed date", costRequest{Due: "03/01/2026"}, false},
+		{"empty patch", costPatch{}, true},
+		{"null members", costPatch{Title: patch.Of("", false), Cost: patch.Of(0.0, false)}, true},
+		{"valid members", costPatch{Title: patch.Of("Sink", true), Cost: patch.Of(3.0, true)}, true},
+		{"too long title", costPatch{Title: patch.Of("Kitchen sink", true)}, false},
+		{"negative patch cost", costPatch{Cost: patch.Of(-3.0, true)}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateStruct(tt.value)
+			if tt.isValid && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if !tt.isValid && err == nil {
+				t.Error("Expected a validation error, got nil")
+			}
+		})
+	}
+}
+
+type codeRequest struct {
+	Code string `json:"code" validate:"len=6"`
+}
+
+func TestTranslateValidationErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    any
+		locale   string
+		expected string
+	}{
+		{"english", costRequest{Cost: "-1"}, i18n.English, "Should be a number greater than or equal to 0"},
+		{"spanish", costRequest{Cost: "-1"}, i18n.Spanish, "Debe ser un número mayor o igual que 0"},
+		{"spanish date", costRequest{Due: "03/01/2026"}, i18n.Spanish, "Debe ser una fecha como 2006-01-02"},
+		{"go-playground fallback", codeRequest{Code: "123"}, i18n.Spanish, "code debe tener 6 caracteres de longitud"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := TranslateValidationErrors(ValidateStruct(tt.value), i18n.Translator(tt.locale))
+			ve, ok := responses.IsValidationError(err)
+			if !ok {
+				t.Fatalf("Expected a validation error, got %v", err)
+			}
+			if len(ve.Violations) != 1 {
+				t.Fatalf("Expected 1 violation, got %d", len(ve.Violations))
+			}
+			if ve.Violations[0].Message != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, ve.Violations[0].Message)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package webhook
+
+import (
+	"time"
+
+	"github.com/mjmarrazzo/maintenance-app/domain"
+)
+
+// Payload is the JSON body of every delivery. Task and comment fields use
+// the names of the REST API.
+type Payload struct {
+	Event      string    `json:"event"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Actor      *Actor    `json:"actor,omitempty"`
+	Task       *Task     `json:"task,omitempty"`
+	// Previous is the task before the change for task.assigned and
+	// task.status_changed.
+	Previous *Task    `json:"previous,omitempty"`
+	Comment  *Comment `json:"comment,omitempty"`
+	URL      string   `json:"url,omitempty"`
+}
+
+type Actor struct {
+	ID        int64  `json:"id"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+}
+
+type Task struct {
+	ID                      int64      `json:"id"`
+	Title                   string     `json:"title"`
+	Description             string     `json:"description"`
+	CategoryName            *string    `json:"category_name"`
+	LocationName            *string    `json:"location_name"`
+	Priority                *string    `json:"priority"`
+	Status                  *string    `json:"status"`
+	CreatedBy               int64      `json:"created_by"`
+	AssignedTo              *int64     `json:"assigned_to"`
+	EstimatedCompletionDate *time.Time `json:"estimated_completion_date"`
+	UpdatedAt               time.Time  `json:"updated_at"`
+}
+
+type Comment struct {
+	ID        int64     `json:"id"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NewPayload describes event. actor may be nil for events raised by the
+// due date checks.
+func NewPayload(event domain.TaskEvent, actor *domain.User, url string) *Payload {
+	payload := &Payload{
+		Event:      string(event.Kind),
+		OccurredAt: event.OccurredAt.UTC(),
+		Task:       newTask(event.Task),
+		URL:        url,
+	}
+	if actor != nil {
+		payload.Actor = &Actor{ID: actor.ID, FirstName: actor.FirstName, LastName: actor.LastName}
+	}
+	if event.Previous != nil && event.Previous.ID != 0 {
+		payload.Previous = newTask(event.Previous)
+	}
+	if event.Comment != nil {
+		payload.Comment = &Comment{
+			ID:        event.Comment.ID,
+			Content:   event.Comment.Content,
+			CreatedAt: event.Comment.CreatedAt,
+		}
+	}
+	return payload
+}
+
+func newTask(t *domain.Task) *Task {
+	if t == nil {
+		return nil
+	}
+
+	task := &Task{
+		ID:          t.ID,
+		Title:       t.Title,
+		Description: t.Description,
+		CreatedBy:   t.CreatedBy,
+		UpdatedAt:   t.UpdatedAt,
+	}
+	if t.CategoryName.Valid {
+		task.CategoryName = &t.CategoryName.String
+	}
+	if t.LocationName.Valid {
+		task.LocationName = &t.LocationName.String
+	}
+	if t.Priority.Valid {
+		task.Priority = &t.Priority.String
+	}
+	if t.Status.Valid {
+		task.Status = &t.Status.String
+	}
+	if t.AssignedTo.Valid {
+		task.AssignedTo = &t.AssignedTo.Int64
+	}
+	if t.EstimatedCompletionDate.Valid {
+		task.EstimatedCompletionDate = &t.EstimatedCompletionDate.Time
+	}
+	return task
+}
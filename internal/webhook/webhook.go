@@ -0,0 +1,124 @@
+// Package webhook signs and sends task events to the URLs admins subscribed
+// to them.
+//
+// Every request is a JSON POST carrying these headers:
+//
+//	X-Webhook-Event: task.created
+//	X-Webhook-Delivery: 42
+//	X-Webhook-Timestamp: 1767225600
+//	X-Webhook-Signature: sha256=<hex>
+//
+// The signature is the HMAC-SHA256 of "<timestamp>.<body>" keyed with the
+// webhook secret. Receivers should recompute it, compare in constant time
+// and reject old timestamps, see Verify.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+
+	signaturePrefix = "sha256="
+	userAgent       = "Groundwork-Webhooks/1.0"
+	// maxResponseBody is how much of a response is kept for the delivery log.
+	maxResponseBody = 1024
+)
+
+// Sign returns the X-Webhook-Signature value for body sent at timestamp.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether signature is valid for body and timestamp is no
+// more than tolerance away from now. It is what a receiver has to do.
+func Verify(secret string, timestamp int64, body []byte, signature string, now time.Time, tolerance time.Duration) bool {
+	sent := time.Unix(timestamp, 0)
+	if now.Sub(sent) > tolerance || sent.Sub(now) > tolerance {
+		return false
+	}
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
+
+// Request is one signed delivery.
+type Request struct {
+	URL        string
+	Secret     string
+	Event      string
+	DeliveryID int64
+	Body       []byte
+}
+
+// Response is what the receiver answered. Body is truncated.
+type Response struct {
+	StatusCode int
+	Body       string
+}
+
+type Client struct {
+	http *http.Client
+	now  func() time.Time
+}
+
+// NewClient returns a client that gives up on receivers after timeout.
+// Redirects are not followed, a moved endpoint has to be updated instead.
+func NewClient(timeout time.Duration) *Client {
+	return &Client{
+		http: &http.Client{
+			Timeout: timeout,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		now: time.Now,
+	}
+}
+
+// Send posts req. Any response other than 2xx is an error; the response is
+// returned with it whenever the receiver answered.
+func (c *Client) Send(ctx context.Context, req Request) (*Response, error) {
+	timestamp := c.now().Unix()
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, req.URL, bytes.NewReader(req.Body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("User-Agent", userAgent)
+	httpReq.Header.Set(HeaderEvent, req.Event)
+	httpReq.Header.Set(HeaderDelivery, strconv.FormatInt(req.DeliveryID, 10))
+	httpReq.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	httpReq.Header.Set(HeaderSignature, Sign(req.Secret, timestamp, req.Body))
+
+	httpResp, err := c.http.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(httpResp.Body, maxResponseBody))
+	resp := &Response{StatusCode: httpResp.StatusCode, Body: strings.ToValidUTF8(string(body), "")}
+
+	if httpResp.StatusCode < 200 || httpResp.StatusCode > 299 {
+		return resp, fmt.Errorf("webhook: receiver answered %s", httpResp.Status)
+	}
+	return resp, nil
+}
@@ -0,0 +1,126 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestSign(t *testing.T) {
+	// printf '1767225600.{"event":"webhook.test"}' | openssl dgst -sha256 -hmac secret
+	expected := "sha256=e032a0a2bac111421b55fd8473e886934a0181037111313cd8729e0ddf940ac4"
+	if got := Sign("secret", 1767225600, []byte(`{"event":"webhook.test"}`)); got != expected {
+		t.Errorf("Expected signature '%s', got '%s'", expected, got)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	now := time.Unix(1767225600, 0)
+	body := []byte(`{"event":"task.created"}`)
+	signature := Sign("secret", now.Unix(), body)
+
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp int64
+		body      string
+		expected  bool
+	}{
+		{"valid", "secret", now.Unix(), string(body), true},
+		{"wrong secret", "other", now.Unix(), string(body), false},
+		{"tampered body", "secret", now.Unix(), `{"event":"task.deleted"}`, false},
+		{"old timestamp", "secret", now.Add(-10 * time.Minute).Unix(), string(body), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sig := signature
+			if tt.timestamp != now.Unix() {
+				sig = Sign(tt.secret, tt.timestamp, []byte(tt.body))
+			}
+			if got := Verify(tt.secret, tt.timestamp, []byte(tt.body), sig, now, 5*time.Minute); got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestClientSend(t *testing.T) {
+	var received *http.Request
+	var receivedBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		receivedBody, _ = io.ReadAll(r.Body)
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	client := NewClient(time.Second)
+	body := []byte(`{"event":"task.created"}`)
+	resp, err := client.Send(context.Background(), Request{
+		URL:        server.URL,
+		Secret:     "secret",
+		Event:      "task.created",
+		DeliveryID: 42,
+		Body:       body,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK || resp.Body != "ok" {
+		t.Errorf("Expected 200 'ok', got %d '%s'", resp.StatusCode, resp.Body)
+	}
+	if received.Header.Get(HeaderEvent) != "task.created" {
+		t.Errorf("Expected event header 'task.created', got '%s'", received.Header.Get(HeaderEvent))
+	}
+	if received.Header.Get(HeaderDelivery) != "42" {
+		t.Errorf("Expected delivery header '42', got '%s'", received.Header.Get(HeaderDelivery))
+	}
+	if received.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("Expected JSON content type, got '%s'", received.Header.Get("Content-Type"))
+	}
+
+	timestamp, err := strconv.ParseInt(received.Header.Get(HeaderTimestamp), 10, 64)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !Verify("secret", timestamp, receivedBody, received.Header.Get(HeaderSignature), time.Now(), time.Minute) {
+		t.Error("Expected the signature to verify")
+	}
+}
+
+func TestClientSendFailures(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+	}{
+		{"server error", http.StatusInternalServerError},
+		{"redirect", http.StatusFound},
+		{"gone", http.StatusGone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.status == http.StatusFound {
+					w.Header().Set("Location", "/elsewhere")
+				}
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			resp, err := NewClient(time.Second).Send(context.Background(), Request{URL: server.URL, Secret: "secret", Body: []byte("{}")})
+			if err == nil {
+				t.Fatal("Expected an error")
+			}
+			if resp == nil || resp.StatusCode != tt.status {
+				t.Errorf("Expected the response with status %d, got %+v", tt.status, resp)
+			}
+		})
+	}
+}
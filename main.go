@@ -1,46 +1,122 @@
 package main
 
 import (
-	"log"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
 	"os"
+	"time"
 
 	"github.com/gorilla/sessions"
 	"github.com/joho/godotenv"
 	"github.com/labstack/echo-contrib/session"
 	"github.com/labstack/echo/v4"
-	"github.com/labstack/echo/v4/middleware"
+	"github.com/mjmarrazzo/maintenance-app/auth"
 	"github.com/mjmarrazzo/maintenance-app/handlers"
+	"github.com/mjmarrazzo/maintenance-app/handlers/apiv1"
 	"github.com/mjmarrazzo/maintenance-app/internal/api"
 	"github.com/mjmarrazzo/maintenance-app/internal/database"
+	"github.com/mjmarrazzo/maintenance-app/internal/events"
+	"github.com/mjmarrazzo/maintenance-app/internal/i18n"
+	"github.com/mjmarrazzo/maintenance-app/internal/logging"
+	"github.com/mjmarrazzo/maintenance-app/internal/mail"
+	"github.com/mjmarrazzo/maintenance-app/internal/realtime"
+	"github.com/mjmarrazzo/maintenance-app/internal/security"
+	"github.com/mjmarrazzo/maintenance-app/internal/sso"
+	"github.com/mjmarrazzo/maintenance-app/internal/storage"
+	"github.com/mjmarrazzo/maintenance-app/service"
 )
 
+// dueDateCheckInterval is how often tasks are checked for due soon and
+// overdue reminders.
+const dueDateCheckInterval = 15 * time.Minute
+
+// emailDigestInterval is how often users are checked for a digest that is
+// due, emailOutboxInterval how often queued emails are delivered.
+const (
+	emailDigestInterval = 15 * time.Minute
+	emailOutboxInterval = 30 * time.Second
+)
+
+// webhookDeliveryInterval is how often queued webhook deliveries are sent.
+const webhookDeliveryInterval = 10 * time.Second
+
 var store *sessions.CookieStore
 
 func init() {
 	err := godotenv.Load(".env.local")
 	if err != nil {
-		log.Println("Warning: No .env file found")
+		slog.Warn("no .env file found")
 	}
 
 	store = sessions.NewCookieStore([]byte(os.Getenv("SESSION_KEY")))
 }
 
 func main() {
+	logger := logging.NewFromEnv()
+	slog.SetDefault(logger)
+
 	db, err := database.InitPool()
 	if err != nil {
 		panic(err)
 	}
 	defer db.Close()
 
+	attachmentStore, err := storage.NewFromEnv()
+	if err != nil {
+		panic(err)
+	}
+
+	mailer, err := mail.NewSenderFromEnv()
+	if err != nil {
+		panic(err)
+	}
+
+	ssoProviders, err := sso.Default()
+	if err != nil {
+		panic(err)
+	}
+
+	emailNotifications := service.NewEmailNotificationService(db.Pool(), api.BaseURL())
+	events.Default().Subscribe(service.NewNotificationService(db.Pool()).HandleTaskEvent)
+	events.Default().Subscribe(emailNotifications.HandleTaskEvent)
+	webhookService := service.NewWebhookService(db.Pool(), api.BaseURL())
+	events.Default().Subscribe(webhookService.HandleTaskEvent)
+
+	jobs := logging.WithLogger(context.Background(), logger)
+	taskChanges := realtime.NewHub()
+	go realtime.Listen(jobs, db.Pool(), taskChanges)
+	taskService := service.NewTaskService(db.Pool())
+	go runPeriodically(jobs, "due date check", dueDateCheckInterval, func(ctx context.Context) error {
+		return taskService.CheckDueDates(ctx, time.Now())
+	})
+	go runPeriodically(jobs, "email digest", emailDigestInterval, func(ctx context.Context) error {
+		return emailNotifications.SendDigests(ctx, time.Now())
+	})
+	outboxService := service.NewOutboxService(db.Pool(), mailer)
+	go runPeriodically(jobs, "email outbox", emailOutboxInterval, func(ctx context.Context) error {
+		return outboxService.ProcessOutbox(ctx, time.Now())
+	})
+	go runPeriodically(jobs, "webhook deliveries", webhookDeliveryInterval, func(ctx context.Context) error {
+		return webhookService.ProcessDeliveries(ctx, time.Now())
+	})
+
 	e := echo.New()
-	e.Use(middleware.Logger())
+	e.HideBanner = true
+	e.Use(logging.Middleware(logger))
+	e.Use(security.Headers())
+	e.Use(i18n.Middleware())
+	e.Use(i18n.TimeZoneMiddleware(service.NewSettingService(db.Pool()).GetTimeZone))
 	e.Use(api.ErrorMiddleware())
 	e.Use(session.Middleware(store))
+	e.Use(auth.SessionMiddleware(service.NewSessionService(db.Pool())))
+	e.Use(security.CSRF())
 
 	homeHandler := handlers.NewHomeHandler()
 	homeHandler.RegisterRoutes(e)
 
-	authHandler := handlers.NewAuthHandler(db)
+	authHandler := handlers.NewAuthHandler(db, mailer)
 	authHandler.RegisterRoutes(e)
 
 	categoryHandler := handlers.NewCategoryHandler(db)
@@ -49,11 +125,63 @@ func main() {
 	locationHandler := handlers.NewLocationHandler(db)
 	locationHandler.RegisterRoutes(e)
 
-	taskHandler := handlers.NewTaskHandler(db)
+	taskHandler := handlers.NewTaskHandler(db, taskChanges)
 	taskHandler.RegisterRoutes(e)
 
+	apiTokenHandler := handlers.NewAPITokenHandler(db)
+	apiTokenHandler.RegisterRoutes(e)
+
+	calendarFeedHandler := handlers.NewCalendarFeedHandler(db)
+	calendarFeedHandler.RegisterRoutes(e)
+
+	notificationHandler := handlers.NewNotificationHandler(db)
+	notificationHandler.RegisterRoutes(e)
+
+	sessionHandler := handlers.NewSessionHandler(db)
+	sessionHandler.RegisterRoutes(e)
+
+	twoFactorHandler := handlers.NewTwoFactorHandler(db)
+	twoFactorHandler.RegisterRoutes(e)
+
+	ssoHandler := handlers.NewSSOHandler(db, ssoProviders)
+	ssoHandler.RegisterRoutes(e)
+
+	adminHandler := handlers.NewAdminHandler(db, mailer)
+	adminHandler.RegisterRoutes(e)
+
+	preferenceHandler := handlers.NewPreferenceHandler(db)
+	preferenceHandler.RegisterRoutes(e)
+
+	webhookHandler := handlers.NewWebhookHandler(db)
+	webhookHandler.RegisterRoutes(e)
+
+	apiRouter := apiv1.NewRouter(db, attachmentStore)
+	apiRouter.RegisterRoutes(e)
+
 	e.Static("/public", "public")
 
-	e.Logger.Debug("Server starting on port 1323...")
-	e.Logger.Fatal(e.Start(":1323"))
+	logger.Info("server starting", "addr", ":1323")
+	if err := e.Start(":1323"); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.Error("server stopped", "error", err)
+		os.Exit(1)
+	}
+}
+
+// runPeriodically runs job right away and then every interval. Failures are
+// logged and the job is retried on the next tick.
+func runPeriodically(ctx context.Context, name string, interval time.Duration, job func(ctx context.Context) error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := job(ctx); err != nil {
+			logging.FromContext(ctx).Error("background job failed", "job", name, "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
 }
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mjmarrazzo/maintenance-app/domain"
+	"github.com/mjmarrazzo/maintenance-app/internal/database"
+)
+
+type APITokenRepository interface {
+	Create(ctx context.Context, token *domain.APIToken) error
+	GetByUserID(ctx context.Context, userID int64) ([]*domain.APIToken, error)
+	GetByHash(ctx context.Context, hash string) (*domain.APIToken, error)
+	Revoke(ctx context.Context, userID, id int64) error
+	TouchLastUsed(ctx context.Context, id int64, usedAt time.Time) error
+}
+
+type apiTokenRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewAPITokenRepository(db *pgxpool.Pool) APITokenRepository {
+	return &apiTokenRepository{db: db}
+}
+
+const apiTokenColumns = `id, user_id, name, token_hash, scopes, expires_at, last_used_at, created_at, revoked_at`
+
+func scanRowToAPIToken(row pgx.Row, token *domain.APIToken) error {
+	return row.Scan(
+		&token.ID,
+		&token.UserID,
+		&token.Name,
+		&token.TokenHash,
+		&token.Scopes,
+		&token.ExpiresAt,
+		&token.LastUsedAt,
+		&token.CreatedAt,
+		&token.RevokedAt,
+	)
+}
+
+func (r *apiTokenRepository) Create(ctx context.Context, token *domain.APIToken) error {
+	sql := `INSERT INTO api_tokens (user_id, name, token_hash, scopes, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at`
+	row := r.db.QueryRow(ctx, sql, token.UserID, token.Name, token.TokenHash, token.Scopes, token.ExpiresAt)
+
+	if err := row.Scan(&token.ID, &token.CreatedAt); err != nil {
+		return database.HandleError(err, "API token", nil)
+	}
+	return nil
+}
+
+func (r *apiTokenRepository) GetByUserID(ctx context.Context, userID int64) ([]*domain.APIToken, error) {
+	sql := `SELECT ` + apiTokenColumns + `
+		FROM api_tokens
+		WHERE user_id = $1
+		ORDER BY created_at DESC`
+	rows, err := r.db.Query(ctx, sql, userID)
+	if err != nil {
+		return nil, database.HandleError(err, "API token", nil)
+	}
+	defer rows.Close()
+
+	tokens := []*domain.APIToken{}
+	for rows.Next() {
+		token := &domain.APIToken{}
+		if err := scanRowToAPIToken(rows, token); err != nil {
+			return nil, database.HandleError(err, "API token", nil)
+		}
+		tokens = append(tokens, token)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, database.HandleError(err, "API token", nil)
+	}
+	return tokens, nil
+}
+
+func (r *apiTokenRepository) GetByHash(ctx context.Context, hash string) (*domain.APIToken, error) {
+	sql := `SELECT ` + apiTokenColumns + ` FROM api_tokens WHERE token_hash = $1`
+	row := r.db.QueryRow(ctx, sql, hash)
+
+	token := &domain.APIToken{}
+	if err := scanRowToAPIToken(row, token); err != nil {
+		return nil, database.HandleError(err, "API token", nil)
+	}
+	return token, nil
+}
+
+func (r *apiTokenRepository) Revoke(ctx context.Context, userID, id int64) error {
+	sql := `UPDATE api_tokens SET revoked_at = NOW()
+		WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL
+		RETURNING id`
+	row := r.db.QueryRow(ctx, sql, id, userID)
+
+	if err := row.Scan(&id); err != nil {
+		return database.HandleError(err, "API token", id)
+	}
+	return nil
+}
+
+func (r *apiTokenRepository) TouchLastUsed(ctx context.Context, id int64, usedAt time.Time) error {
+	sql := `UPDATE api_tokens SET last_used_at = $2 WHERE id = $1`
+	if _, err := r.db.Exec(ctx, sql, id, usedAt); err != nil {
+		return database.HandleError(err, "API token", id)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mjmarrazzo/maintenance-app/domain"
+	"github.com/mjmarrazzo/maintenance-app/internal/database"
+)
+
+type AttachmentRepository interface {
+	Create(ctx context.Context, attachment *domain.Attachment) error
+	GetByTaskID(ctx context.Context, taskID int64) ([]*domain.Attachment, error)
+	GetByID(ctx context.Context, id int64) (*domain.Attachment, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+type attachmentRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewAttachmentRepository(db *pgxpool.Pool) AttachmentRepository {
+	return &attachmentRepository{db: db}
+}
+
+const attachmentColumns = `
+	a.id, a.task_id, a.filename, a.object_key, a.mime_type, a.size_bytes,
+	a.uploaded_by, u.first_name, u.last_name, a.upload_date
+`
+
+func scanRowToAttachment(row pgx.Row, attachment *domain.Attachment) error {
+	return row.Scan(
+		&attachment.ID,
+		&attachment.TaskID,
+		&attachment.Filename,
+		&attachment.ObjectKey,
+		&attachment.MimeType,
+		&attachment.SizeBytes,
+		&attachment.UploadedBy,
+		&attachment.UploadedByFirstName,
+		&attachment.UploadedByLastName,
+		&attachment.UploadDate,
+	)
+}
+
+func (r *attachmentRepository) Create(ctx context.Context, attachment *domain.Attachment) error {
+	sql := `
+		INSERT INTO attachments (task_id, filename, object_key, mime_type, size_bytes, uploaded_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, upload_date`
+	row := r.db.QueryRow(ctx, sql,
+		attachment.TaskID,
+		attachment.Filename,
+		attachment.ObjectKey,
+		attachment.MimeType,
+		attachment.SizeBytes,
+		attachment.UploadedBy,
+	)
+
+	if err := row.Scan(&attachment.ID, &attachment.UploadDate); err != nil {
+		return database.HandleError(err, "attachment", nil)
+	}
+	return nil
+}
+
+func (r *attachmentRepository) GetByTaskID(ctx context.Context, taskID int64) ([]*domain.Attachment, error) {
+	sql := `SELECT ` + attachmentColumns + `
+		FROM attachments a
+		LEFT JOIN users u ON a.uploaded_by = u.id
+		WHERE a.task_id = $1
+		ORDER BY a.upload_date ASC`
+	rows, err := r.db.Query(ctx, sql, taskID)
+	if err != nil {
+		return nil, database.HandleError(err, "attachment", nil)
+	}
+	defer rows.Close()
+
+	attachments := []*domain.Attachment{}
+	for rows.Next() {
+		attachment := &domain.Attachment{}
+		if err := scanRowToAttachment(rows, attachment); err != nil {
+			return nil, database.HandleError(err, "attachment", nil)
+		}
+		attachments = append(attachments, attachment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, database.HandleError(err, "attachment", nil)
+	}
+	return attachments, nil
+}
+
+func (r *attachmentRepository) GetByID(ctx context.Context, id int64) (*domain.Attachment, error) {
+	sql := `SELECT ` + attachmentColumns + `
+		FROM attachments a
+		LEFT JOIN users u ON a.uploaded_by = u.id
+		WHERE a.id = $1`
+	row := r.db.QueryRow(ctx, sql, id)
+
+	attachment := &domain.Attachment{}
+	if err := scanRowToAttachment(row, attachment); err != nil {
+		return nil, database.HandleError(err, "attachment", id)
+	}
+	return attachment, nil
+}
+
+func (r *attachmentRepository) Delete(ctx context.Context, id int64) error {
+	sql := `DELETE FROM attachments WHERE id = $1`
+	if _, err := r.db.Exec(ctx, sql, id); err != nil {
+		return database.HandleError(err, "attachment", id)
+	}
+	return nil
+}
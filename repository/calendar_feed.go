@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mjmarrazzo/maintenance-app/domain"
+	"github.com/mjmarrazzo/maintenance-app/internal/database"
+)
+
+type CalendarFeedRepository interface {
+	// Replace stores the feed of its user, replacing the previous one.
+	Replace(ctx context.Context, feed *domain.CalendarFeed) error
+	GetByUserID(ctx context.Context, userID int64) (*domain.CalendarFeed, error)
+	GetByHash(ctx context.Context, hash string) (*domain.CalendarFeed, error)
+	Delete(ctx context.Context, userID int64) error
+	TouchLastUsed(ctx context.Context, userID int64, usedAt time.Time) error
+}
+
+type calendarFeedRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewCalendarFeedRepository(db *pgxpool.Pool) CalendarFeedRepository {
+	return &calendarFeedRepository{db: db}
+}
+
+const calendarFeedColumns = `user_id, token_hash, created_at, last_used_at`
+
+func scanRowToCalendarFeed(row pgx.Row, feed *domain.CalendarFeed) error {
+	return row.Scan(&feed.UserID, &feed.TokenHash, &feed.CreatedAt, &feed.LastUsedAt)
+}
+
+func (r *calendarFeedRepository) Replace(ctx context.Context, feed *domain.CalendarFeed) error {
+	sql := `INSERT INTO calendar_feeds (user_id, token_hash) VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE
+			SET token_hash = EXCLUDED.token_hash, created_at = NOW(), last_used_at = NULL
+		RETURNING created_at`
+	row := r.db.QueryRow(ctx, sql, feed.UserID, feed.TokenHash)
+
+	if err := row.Scan(&feed.CreatedAt); err != nil {
+		return database.HandleError(err, "calendar feed", feed.UserID)
+	}
+	feed.LastUsedAt.Valid = false
+	return nil
+}
+
+func (r *calendarFeedRepository) GetByUserID(ctx context.Context, userID int64) (*domain.CalendarFeed, error) {
+	sql := `SELECT ` + calendarFeedColumns + ` FROM calendar_feeds WHERE user_id = $1`
+	row := r.db.QueryRow(ctx, sql, userID)
+
+	feed := &domain.CalendarFeed{}
+	if err := scanRowToCalendarFeed(row, feed); err != nil {
+		return nil, database.HandleError(err, "calendar feed", userID)
+	}
+	return feed, nil
+}
+
+func (r *calendarFeedRepository) GetByHash(ctx context.Context, hash string) (*domain.CalendarFeed, error) {
+	sql := `SELECT ` + calendarFeedColumns + ` FROM calendar_feeds WHERE token_hash = $1`
+	row := r.db.QueryRow(ctx, sql, hash)
+
+	feed := &domain.CalendarFeed{}
+	if err := scanRowToCalendarFeed(row, feed); err != nil {
+		return nil, database.HandleError(err, "calendar feed", nil)
+	}
+	return feed, nil
+}
+
+func (r *calendarFeedRepository) Delete(ctx context.Context, userID int64) error {
+	sql := `DELETE FROM calendar_feeds WHERE user_id = $1`
+	if _, err := r.db.Exec(ctx, sql, userID); err != nil {
+		return database.HandleError(err, "calendar feed", userID)
+	}
+	return nil
+}
+
+func (r *calendarFeedRepository) TouchLastUsed(ctx context.Context, userID int64, usedAt time.Time) error {
+	sql := `UPDATE calendar_feeds SET last_used_at = $2 WHERE user_id = $1`
+	if _, err := r.db.Exec(ctx, sql, userID, usedAt); err != nil {
+		return database.HandleError(err, "calendar feed", userID)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mjmarrazzo/maintenance-app/domain"
+	"github.com/mjmarrazzo/maintenance-app/internal/database"
+)
+
+type CommentRepository interface {
+	Create(ctx context.Context, comment *domain.Comment) error
+	GetByTaskID(ctx context.Context, taskID int64) ([]*domain.Comment, error)
+	GetByID(ctx context.Context, id int64) (*domain.Comment, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+type commentRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewCommentRepository(db *pgxpool.Pool) CommentRepository {
+	return &commentRepository{db: db}
+}
+
+const commentColumns = `
+	c.id, c.task_id, c.user_id, u.first_name, u.last_name, c.content, c.created_at
+`
+
+func scanRowToComment(row pgx.Row, comment *domain.Comment) error {
+	return row.Scan(
+		&comment.ID,
+		&comment.TaskID,
+		&comment.UserID,
+		&comment.UserFirstName,
+		&comment.UserLastName,
+		&comment.Content,
+		&comment.CreatedAt,
+	)
+}
+
+func (r *commentRepository) Create(ctx context.Context, comment *domain.Comment) error {
+	sql := `INSERT INTO comments (task_id, user_id, content) VALUES ($1, $2, $3) RETURNING id, created_at`
+	row := r.db.QueryRow(ctx, sql, comment.TaskID, comment.UserID, comment.Content)
+
+	if err := row.Scan(&comment.ID, &comment.CreatedAt); err != nil {
+		return database.HandleError(err, "comment", nil)
+	}
+	return nil
+}
+
+func (r *commentRepository) GetByTaskID(ctx context.Context, taskID int64) ([]*domain.Comment, error) {
+	sql := `SELECT ` + commentColumns + `
+		FROM comments c
+		LEFT JOIN users u ON c.user_id = u.id
+		WHERE c.task_id = $1
+		ORDER BY c.created_at ASC`
+	rows, err := r.db.Query(ctx, sql, taskID)
+	if err != nil {
+		return nil, database.HandleError(err, "comment", nil)
+	}
+	defer rows.Close()
+
+	comments := []*domain.Comment{}
+	for rows.Next() {
+		comment := &domain.Comment{}
+		if err := scanRowToComment(rows, comment); err != nil {
+			return nil, database.HandleError(err, "comment", nil)
+		}
+		comments = append(comments, comment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, database.HandleError(err, "comment", nil)
+	}
+	return comments, nil
+}
+
+func (r *commentRepository) GetByID(ctx context.Context, id int64) (*domain.Comment, error) {
+	sql := `SELECT ` + commentColumns + `
+		FROM comments c
+		LEFT JOIN users u ON c.user_id = u.id
+		WHERE c.id = $1`
+	row := r.db.QueryRow(ctx, sql, id)
+
+	comment := &domain.Comment{}
+	if err := scanRowToComment(row, comment); err != nil {
+		return nil, database.HandleError(err, "comment", id)
+	}
+	return comment, nil
+}
+
+func (r *commentRepository) Delete(ctx context.Context, id int64) error {
+	sql := `DELETE FROM comments WHERE id = $1`
+	if _, err := r.db.Exec(ctx, sql, id); err != nil {
+		return database.HandleError(err, "comment", id)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mjmarrazzo/maintenance-app/domain"
+	"github.com/mjmarrazzo/maintenance-app/internal/database"
+)
+
+type EmailDigestItemRepository interface {
+	Create(ctx context.Context, item *domain.EmailDigestItem) error
+	GetPending(ctx context.Context, userID int64) ([]*domain.EmailDigestItem, error)
+	MarkSent(ctx context.Context, ids []int64) error
+}
+
+type emailDigestItemRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewEmailDigestItemRepository(db *pgxpool.Pool) EmailDigestItemRepository {
+	return &emailDigestItemRepository{db: db}
+}
+
+const emailDigestItemColumns = `id, user_id, task_id, event, summary, sent_at, created_at`
+
+func scanRowToEmailDigestItem(row pgx.Row, item *domain.EmailDigestItem) error {
+	return row.Scan(
+		&item.ID,
+		&item.UserID,
+		&item.TaskID,
+		&item.Event,
+		&item.Summary,
+		&item.SentAt,
+		&item.CreatedAt,
+	)
+}
+
+func (r *emailDigestItemRepository) Create(ctx context.Context, item *domain.EmailDigestItem) error {
+	sql := `INSERT INTO email_digest_items (user_id, task_id, event, summary)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at`
+	row := r.db.QueryRow(ctx, sql, item.UserID, item.TaskID, item.Event, item.Summary)
+
+	if err := row.Scan(&item.ID, &item.CreatedAt); err != nil {
+		return database.HandleError(err, "email digest item", nil)
+	}
+	return nil
+}
+
+func (r *emailDigestItemRepository) GetPending(ctx context.Context, userID int64) ([]*domain.EmailDigestItem, error) {
+	sql := `SELECT ` + emailDigestItemColumns + `
+		FROM email_digest_items
+		WHERE user_id = $1 AND sent_at IS NULL
+		ORDER BY created_at, id`
+	rows, err := r.db.Query(ctx, sql, userID)
+	if err != nil {
+		return nil, database.HandleError(err, "email digest item", nil)
+	}
+	defer rows.Close()
+
+	items := []*domain.EmailDigestItem{}
+	for rows.Next() {
+		item := &domain.EmailDigestItem{}
+		if err := scanRowToEmailDigestItem(rows, item); err != nil {
+			return nil, database.HandleError(err, "email digest item", nil)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, database.HandleError(err, "email digest item", nil)
+	}
+	return items, nil
+}
+
+func (r *emailDigestItemRepository) MarkSent(ctx context.Context, ids []int64) error {
+	sql := `UPDATE email_digest_items SET sent_at = NOW() WHERE id = ANY($1)`
+	if _, err := r.db.Exec(ctx, sql, ids); err != nil {
+		return database.HandleError(err, "email digest item", nil)
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mjmarrazzo/maintenance-app/domain"
+	"github.com/mjmarrazzo/maintenance-app/internal/database"
+)
+
+type EmailOutboxRepository interface {
+	Enqueue(ctx context.Context, email *domain.OutboxEmail) error
+	ClaimDue(ctx context.Context, now time.Time, limit int) ([]*domain.OutboxEmail, error)
+	MarkSent(ctx context.Context, id int64) error
+	MarkRetry(ctx context.Context, id int64, attempts int, nextAttemptAt time.Time, lastError string) error
+	MarkFailed(ctx context.Context, id int64, attempts int, lastError string) error
+}
+
+type emailOutboxRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewEmailOutboxRepository(db *pgxpool.Pool) EmailOutboxRepository {
+	return &emailOutboxRepository{db: db}
+}
+
+const emailOutboxColumns = `id, recipient, subject, text_body, html_body, attempts, next_attempt_at, last_error, sent_at, failed_at, created_at`
+
+func scanRowToOutboxEmail(row pgx.Row, email *domain.OutboxEmail) error {
+	return row.Scan(
+		&email.ID,
+		&email.Recipient,
+		&email.Subject,
+		&email.TextBody,
+		&email.HTMLBody,
+		&email.Attempts,
+		&email.NextAttemptAt,
+		&email.LastError,
+		&email.SentAt,
+		&email.FailedAt,
+		&email.CreatedAt,
+	)
+}
+
+func (r *emailOutboxRepository) Enqueue(ctx context.Context, email *domain.OutboxEmail) error {
+	sql := `INSERT INTO email_outbox (recipient, subject, text_body, html_body)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, next_attempt_at, created_at`
+	row := r.db.QueryRow(ctx, sql, email.Recipient, email.Subject, email.TextBody, email.HTMLBody)
+
+	if err := row.Scan(&email.ID, &email.NextAttemptAt, &email.CreatedAt); err != nil {
+		return database.HandleError(err, "outbox email", nil)
+	}
+	return nil
+}
+
+// ClaimDue returns up to limit emails that are due for delivery and hides
+// them from other senders for domain.OutboxLease, so a crashed sender only
+// delays them.
+func (r *emailOutboxRepository) ClaimDue(ctx context.Context, now time.Time, limit int) ([]*domain.OutboxEmail, error) {
+	sql := `UPDATE email_outbox SET next_attempt_at = $3
+		WHERE id IN (
+			SELECT id FROM email_outbox
+			WHERE sent_at IS NULL AND failed_at IS NULL AND next_attempt_at <= $1
+			ORDER BY next_attempt_at, id
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + emailOutboxColumns
+	rows, err := r.db.Query(ctx, sql, now, limit, now.Add(domain.OutboxLease))
+	if err != nil {
+		return nil, database.HandleError(err, "outbox email", nil)
+	}
+	defer rows.Close()
+
+	emails := []*domain.OutboxEmail{}
+	for rows.Next() {
+		email := &domain.OutboxEmail{}
+		if err := scanRowToOutboxEmail(rows, email); err != nil {
+			return nil, database.HandleError(err, "outbox email", nil)
+		}
+		emails = append(emails, email)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, database.HandleError(err, "outbox email", nil)
+	}
+	return emails, nil
+}
+
+func (r *emailOutboxRepository) MarkSent(ctx context.Context, id int64) error {
+	sql := `UPDATE email_outbox SET sent_at = NOW(), attempts = attempts + 1, last_error = NULL WHERE id = $1`
+	if _, err := r.db.Exec(ctx, sql, id); err != nil {
+		return database.HandleError(err, "outbox email", id)
+	}
+	return nil
+}
+
+func (r *emailOutboxRepository) MarkRetry(ctx context.Context, id int64, attempts int, nextAttemptAt time.Time, lastError string) error {
+	sql := `UPDATE email_outbox SET attempts = $2, next_attempt_at = $3, last_error = $4 WHERE id = $1`
+	if _, err := r.db.Exec(ctx, sql, id, attempts, nextAttemptAt, lastError); err != nil {
+		return database.HandleError(err, "outbox email", id)
+	}
+	return nil
+}
+
+func (r *emailOutboxRepository) MarkFailed(ctx context.Context, id int64, attempts int, lastError string) error {
+	sql := `UPDATE email_outbox SET attempts = $2, failed_at = NOW(), last_error = $3 WHERE id = $1`
+	if _, err := r.db.Exec(ctx, sql, id, attempts, lastError); err != nil {
+		return database.HandleError(err, "outbox email", id)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mjmarrazzo/maintenance-app/domain"
+	"github.com/mjmarrazzo/maintenance-app/internal/database"
+)
+
+type EmailPreferenceRepository interface {
+	GetByUserID(ctx context.Context, userID int64) (*domain.EmailPreferences, error)
+	Upsert(ctx context.Context, preferences *domain.EmailPreferences) error
+	GetDigestRecipients(ctx context.Context, sentBefore time.Time) ([]int64, error)
+	MarkDigestSent(ctx context.Context, userID int64, sentAt time.Time) error
+}
+
+type emailPreferenceRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewEmailPreferenceRepository(db *pgxpool.Pool) EmailPreferenceRepository {
+	return &emailPreferenceRepository{db: db}
+}
+
+const emailPreferenceColumns = `user_id, assigned, mentioned, overdue, daily_digest, digest_sent_at`
+
+func scanRowToEmailPreferences(row pgx.Row, preferences *domain.EmailPreferences) error {
+	return row.Scan(
+		&preferences.UserID,
+		&preferences.Assigned,
+		&preferences.Mentioned,
+		&preferences.Overdue,
+		&preferences.DailyDigest,
+		&preferences.DigestSentAt,
+	)
+}
+
+func (r *emailPreferenceRepository) GetByUserID(ctx context.Context, userID int64) (*domain.EmailPreferences, error) {
+	sql := `SELECT ` + emailPreferenceColumns + ` FROM email_preferences WHERE user_id = $1`
+	row := r.db.QueryRow(ctx, sql, userID)
+
+	preferences := &domain.EmailPreferences{}
+	if err := scanRowToEmailPreferences(row, preferences); err != nil {
+		return nil, database.HandleError(err, "email preferences", userID)
+	}
+	return preferences, nil
+}
+
+func (r *emailPreferenceRepository) Upsert(ctx context.Context, preferences *domain.EmailPreferences) error {
+	sql := `INSERT INTO email_preferences (user_id, assigned, mentioned, overdue, daily_digest)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id) DO UPDATE SET
+			assigned = EXCLUDED.assigned,
+			mentioned = EXCLUDED.mentioned,
+			overdue = EXCLUDED.overdue,
+			daily_digest = EXCLUDED.daily_digest,
+			updated_at = NOW()`
+	_, err := r.db.Exec(ctx, sql,
+		preferences.UserID,
+		preferences.Assigned,
+		preferences.Mentioned,
+		preferences.Overdue,
+		preferences.DailyDigest,
+	)
+	if err != nil {
+		return database.HandleError(err, "email preferences", preferences.UserID)
+	}
+	return nil
+}
+
+// GetDigestRecipients returns the users whose last digest was sent before
+// sentBefore and who either want the daily digest or have items waiting
+// for it.
+func (r *emailPreferenceRepository) GetDigestRecipients(ctx context.Context, sentBefore time.Time) ([]int64, error) {
+	sql := `SELECT u.id
+		FROM users u
+		LEFT JOIN email_preferences p ON p.user_id = u.id
+		WHERE (p.digest_sent_at IS NULL OR p.digest_sent_at < $1)
+			AND (
+				COALESCE(p.daily_digest, TRUE)
+				OR EXISTS (SELECT 1 FROM email_digest_items i WHERE i.user_id = u.id AND i.sent_at IS NULL)
+			)
+		ORDER BY u.id`
+	rows, err := r.db.Query(ctx, sql, sentBefore)
+	if err != nil {
+		return nil, database.HandleError(err, "email preferences", nil)
+	}
+	defer rows.Close()
+
+	userIDs, err := pgx.CollectRows(rows, pgx.RowTo[int64])
+	if err != nil {
+		return nil, database.HandleError(err, "email preferences", nil)
+	}
+	return userIDs, nil
+}
+
+func (r *emailPreferenceRepository) MarkDigestSent(ctx context.Context, userID int64, sentAt time.Time) error {
+	sql := `INSERT INTO email_preferences (user_id, digest_sent_at)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE SET digest_sent_at = EXCLUDED.digest_sent_at`
+	if _, err := r.db.Exec(ctx, sql, userID, sentAt); err != nil {
+		return database.HandleError(err, "email preferences", userID)
+	}
+	return nil
+}
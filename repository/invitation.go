@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mjmarrazzo/maintenance-app/domain"
+	"github.com/mjmarrazzo/maintenance-app/internal/database"
+	"github.com/mjmarrazzo/maintenance-app/internal/responses"
+)
+
+type InvitationRepository interface {
+	Create(ctx context.Context, invitation *domain.Invitation) error
+	GetAll(ctx context.Context) ([]*domain.Invitation, error)
+	GetByHash(ctx context.Context, hash string) (*domain.Invitation, error)
+	GetPendingByEmail(ctx context.Context, email string) (*domain.Invitation, error)
+	// Accept creates user and marks the pending invitation id as accepted by
+	// them in one transaction. An invitation that was accepted, revoked or
+	// expired in the meantime yields a not found error and no user.
+	Accept(ctx context.Context, id int64, user *domain.User) error
+	Revoke(ctx context.Context, id int64) error
+}
+
+type invitationRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewInvitationRepository(db *pgxpool.Pool) InvitationRepository {
+	return &invitationRepository{db: db}
+}
+
+const invitationColumns = `
+	i.id, i.email, i.role, i.token_hash, i.invited_by, u.first_name || ' ' || u.last_name,
+	i.expires_at, i.accepted_at, i.accepted_user_id, i.revoked_at, i.created_at
+`
+
+func scanRowToInvitation(row pgx.Row, invitation *domain.Invitation) error {
+	return row.Scan(
+		&invitation.ID,
+		&invitation.Email,
+		&invitation.Role,
+		&invitation.TokenHash,
+		&invitation.InvitedBy,
+		&invitation.InvitedByName,
+		&invitation.ExpiresAt,
+		&invitation.AcceptedAt,
+		&invitation.AcceptedUserID,
+		&invitation.RevokedAt,
+		&invitation.CreatedAt,
+	)
+}
+
+func (r *invitationRepository) Create(ctx context.Context, invitation *domain.Invitation) error {
+	sql := `INSERT INTO invitations (email, role, token_hash, invited_by, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at`
+	row := r.db.QueryRow(ctx, sql,
+		invitation.Email,
+		invitation.Role,
+		invitation.TokenHash,
+		invitation.InvitedBy,
+		invitation.ExpiresAt,
+	)
+
+	if err := row.Scan(&invitation.ID, &invitation.CreatedAt); err != nil {
+		return database.HandleError(err, "invitation", nil)
+	}
+	return nil
+}
+
+func (r *invitationRepository) GetAll(ctx context.Context) ([]*domain.Invitation, error) {
+	sql := `SELECT ` + invitationColumns + `
+		FROM invitations i
+		LEFT JOIN users u ON i.invited_by = u.id
+		ORDER BY i.created_at DESC`
+	rows, err := r.db.Query(ctx, sql)
+	if err != nil {
+		return nil, database.HandleError(err, "invitation", nil)
+	}
+	defer rows.Close()
+
+	invitations := []*domain.Invitation{}
+	for rows.Next() {
+		invitation := &domain.Invitation{}
+		if err := scanRowToInvitation(rows, invitation); err != nil {
+			return nil, database.HandleError(err, "invitation", nil)
+		}
+		invitations = append(invitations, invitation)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, database.HandleError(err, "invitation", nil)
+	}
+	return invitations, nil
+}
+
+func (r *invitationRepository) GetByHash(ctx context.Context, hash string) (*domain.Invitation, error) {
+	sql := `SELECT ` + invitationColumns + `
+		FROM invitations i
+		LEFT JOIN users u ON i.invited_by = u.id
+		WHERE i.token_hash = $1`
+	row := r.db.QueryRow(ctx, sql, hash)
+
+	invitation := &domain.Invitation{}
+	if err := scanRowToInvitation(row, invitation); err != nil {
+		return nil, database.HandleError(err, "invitation", nil)
+	}
+	return invitation, nil
+}
+
+// GetPendingByEmail returns the most recent usable invitation for email.
+func (r *invitationRepository) GetPendingByEmail(ctx context.Context, email string) (*domain.Invitation, error) {
+	sql := `SELECT ` + invitationColumns + `
+		FROM invitations i
+		LEFT JOIN users u ON i.invited_by = u.id
+		WHERE LOWER(i.email) = LOWER($1)
+		AND i.accepted_at IS NULL AND i.revoked_at IS NULL AND i.expires_at > NOW()
+		ORDER BY i.created_at DESC
+		LIMIT 1`
+	row := r.db.QueryRow(ctx, sql, email)
+
+	invitation := &domain.Invitation{}
+	if err := scanRowToInvitation(row, invitation); err != nil {
+		return nil, database.HandleError(err, "invitation", nil)
+	}
+	return invitation, nil
+}
+
+func (r *invitationRepository) Accept(ctx context.Context, id int64, user *domain.User) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return database.HandleError(err, "invitation", id)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := tx.QueryRow(ctx, createUserSQL, createUserArgs(user)...).Scan(&user.ID, &user.CreatedAt); err != nil {
+		return database.HandleError(err, "user", nil)
+	}
+
+	sql := `UPDATE invitations SET accepted_at = NOW(), accepted_user_id = $2
+		WHERE id = $1 AND accepted_at IS NULL AND revoked_at IS NULL AND expires_at > NOW()`
+	ct, err := tx.Exec(ctx, sql, id, user.ID)
+	if err != nil {
+		return database.HandleError(err, "invitation", id)
+	}
+	if ct.RowsAffected() == 0 {
+		return responses.NewNotFoundError(fmt.Sprintf("invitation with ID %d not found", id))
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return database.HandleError(err, "invitation", id)
+	}
+	return nil
+}
+
+func (r *invitationRepository) Revoke(ctx context.Context, id int64) error {
+	sql := `UPDATE invitations SET revoked_at = NOW()
+		WHERE id = $1 AND accepted_at IS NULL AND revoked_at IS NULL
+		RETURNING id`
+	row := r.db.QueryRow(ctx, sql, id)
+
+	if err := row.Scan(&id); err != nil {
+		return database.HandleError(err, "invitation", id)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mjmarrazzo/maintenance-app/domain"
+	"github.com/mjmarrazzo/maintenance-app/internal/database"
+)
+
+type LoginAttemptRepository interface {
+	Create(ctx context.Context, attempt *domain.LoginAttempt) error
+	// CountFailuresByIP returns the failed attempts from ipAddress since the
+	// given time and when the latest of them happened. Attempts rejected by
+	// the rate limit itself are not counted.
+	CountFailuresByIP(ctx context.Context, ipAddress string, since time.Time) (int, time.Time, error)
+	GetRecentFailures(ctx context.Context, limit int) ([]*domain.LoginAttempt, error)
+}
+
+type loginAttemptRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewLoginAttemptRepository(db *pgxpool.Pool) LoginAttemptRepository {
+	return &loginAttemptRepository{db: db}
+}
+
+func (r *loginAttemptRepository) Create(ctx context.Context, attempt *domain.LoginAttempt) error {
+	sql := `INSERT INTO login_attempts (email, user_id, ip_address, succeeded, reason)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''))
+		RETURNING id, created_at`
+	row := r.db.QueryRow(ctx, sql,
+		attempt.Email,
+		attempt.UserID,
+		attempt.IPAddress,
+		attempt.Succeeded,
+		attempt.Reason,
+	)
+
+	if err := row.Scan(&attempt.ID, &attempt.CreatedAt); err != nil {
+		return database.HandleError(err, "login attempt", nil)
+	}
+	return nil
+}
+
+func (r *loginAttemptRepository) CountFailuresByIP(ctx context.Context, ipAddress string, since time.Time) (int, time.Time, error) {
+	sql := `SELECT COUNT(*), COALESCE(MAX(created_at), $2)
+		FROM login_attempts
+		WHERE ip_address = $1 AND NOT succeeded AND created_at >= $2
+		AND reason IS DISTINCT FROM '` + domain.LoginReasonRateLimited + `'`
+	row := r.db.QueryRow(ctx, sql, ipAddress, since)
+
+	var count int
+	var latest time.Time
+	if err := row.Scan(&count, &latest); err != nil {
+		return 0, time.Time{}, database.HandleError(err, "login attempt", nil)
+	}
+	return count, latest, nil
+}
+
+func (r *loginAttemptRepository) GetRecentFailures(ctx context.Context, limit int) ([]*domain.LoginAttempt, error) {
+	sql := `SELECT id, email, user_id, ip_address, succeeded, COALESCE(reason, ''), created_at
+		FROM login_attempts
+		WHERE NOT succeeded
+		ORDER BY created_at DESC
+		LIMIT $1`
+	rows, err := r.db.Query(ctx, sql, limit)
+	if err != nil {
+		return nil, database.HandleError(err, "login attempt", nil)
+	}
+	defer rows.Close()
+
+	attempts := []*domain.LoginAttempt{}
+	for rows.Next() {
+		attempt := &domain.LoginAttempt{}
+		err := rows.Scan(
+			&attempt.ID,
+			&attempt.Email,
+			&attempt.UserID,
+			&attempt.IPAddress,
+			&attempt.Succeeded,
+			&attempt.Reason,
+			&attempt.CreatedAt,
+		)
+		if err != nil {
+			return nil, database.HandleError(err, "login attempt", nil)
+		}
+		attempts = append(attempts, attempt)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, database.HandleError(err, "login attempt", nil)
+	}
+	return attempts, nil
+}
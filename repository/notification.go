@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mjmarrazzo/maintenance-app/domain"
+	"github.com/mjmarrazzo/maintenance-app/internal/database"
+)
+
+type NotificationRepository interface {
+	Create(ctx context.Context, notification *domain.Notification) error
+	GetByUserID(ctx context.Context, userID int64, limit int) ([]*domain.Notification, error)
+	CountUnread(ctx context.Context, userID int64) (int, error)
+	MarkRead(ctx context.Context, userID, id int64) (*domain.Notification, error)
+	MarkAllRead(ctx context.Context, userID int64) error
+}
+
+type notificationRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewNotificationRepository(db *pgxpool.Pool) NotificationRepository {
+	return &notificationRepository{db: db}
+}
+
+const notificationColumns = `n.id, n.user_id, n.task_id, t.title, n.actor_id, actor.first_name, actor.last_name,
+	n.kind, n.detail, n.read_at, n.created_at`
+
+func scanRowToNotification(row pgx.Row, notification *domain.Notification) error {
+	return row.Scan(
+		&notification.ID,
+		&notification.UserID,
+		&notification.TaskID,
+		&notification.TaskTitle,
+		&notification.ActorID,
+		&notification.ActorFirstName,
+		&notification.ActorLastName,
+		&notification.Kind,
+		&notification.Detail,
+		&notification.ReadAt,
+		&notification.CreatedAt,
+	)
+}
+
+func (r *notificationRepository) Create(ctx context.Context, notification *domain.Notification) error {
+	sql := `INSERT INTO notifications (user_id, task_id, actor_id, kind, detail)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at`
+	row := r.db.QueryRow(ctx, sql,
+		notification.UserID,
+		notification.TaskID,
+		notification.ActorID,
+		notification.Kind,
+		notification.Detail,
+	)
+
+	if err := row.Scan(&notification.ID, &notification.CreatedAt); err != nil {
+		return database.HandleError(err, "notification", nil)
+	}
+	return nil
+}
+
+func (r *notificationRepository) GetByUserID(ctx context.Context, userID int64, limit int) ([]*domain.Notification, error) {
+	sql := `SELECT ` + notificationColumns + `
+		FROM notifications n
+		JOIN tasks t ON t.id = n.task_id
+		LEFT JOIN users actor ON actor.id = n.actor_id
+		WHERE n.user_id = $1
+		ORDER BY n.created_at DESC, n.id DESC
+		LIMIT $2`
+	rows, err := r.db.Query(ctx, sql, userID, limit)
+	if err != nil {
+		return nil, database.HandleError(err, "notification", nil)
+	}
+	defer rows.Close()
+
+	notifications := []*domain.Notification{}
+	for rows.Next() {
+		notification := &domain.Notification{}
+		if err := scanRowToNotification(rows, notification); err != nil {
+			return nil, database.HandleError(err, "notification", nil)
+		}
+		notifications = append(notifications, notification)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, database.HandleError(err, "notification", nil)
+	}
+	return notifications, nil
+}
+
+func (r *notificationRepository) CountUnread(ctx context.Context, userID int64) (int, error) {
+	sql := `SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND read_at IS NULL`
+
+	var count int
+	if err := r.db.QueryRow(ctx, sql, userID).Scan(&count); err != nil {
+		return 0, database.HandleError(err, "notification", nil)
+	}
+	return count, nil
+}
+
+func (r *notificationRepository) MarkRead(ctx context.Context, userID, id int64) (*domain.Notification, error) {
+	sql := `WITH n AS (
+			UPDATE notifications SET read_at = COALESCE(read_at, NOW())
+			WHERE id = $1 AND user_id = $2
+			RETURNING *
+		)
+		SELECT ` + notificationColumns + `
+		FROM n
+		JOIN tasks t ON t.id = n.task_id
+		LEFT JOIN users actor ON actor.id = n.actor_id`
+	row := r.db.QueryRow(ctx, sql, id, userID)
+
+	notification := &domain.Notification{}
+	if err := scanRowToNotification(row, notification); err != nil {
+		return nil, database.HandleError(err, "notification", id)
+	}
+	return notification, nil
+}
+
+func (r *notificationRepository) MarkAllRead(ctx context.Context, userID int64) error {
+	sql := `UPDATE notifications SET read_at = NOW() WHERE user_id = $1 AND read_at IS NULL`
+	if _, err := r.db.Exec(ctx, sql, userID); err != nil {
+		return database.HandleError(err, "notification", nil)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mjmarrazzo/maintenance-app/internal/database"
+)
+
+type RecoveryCodeRepository interface {
+	Replace(ctx context.Context, userID int64, codeHashes []string) error
+	Consume(ctx context.Context, userID int64, codeHash string) (bool, error)
+	CountUnused(ctx context.Context, userID int64) (int, error)
+	DeleteByUserID(ctx context.Context, userID int64) error
+}
+
+type recoveryCodeRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewRecoveryCodeRepository(db *pgxpool.Pool) RecoveryCodeRepository {
+	return &recoveryCodeRepository{db: db}
+}
+
+// Replace discards every code of the user and stores codeHashes instead.
+func (r *recoveryCodeRepository) Replace(ctx context.Context, userID int64, codeHashes []string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return database.HandleError(err, "recovery code", nil)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return database.HandleError(err, "recovery code", nil)
+	}
+
+	sql := `INSERT INTO recovery_codes (user_id, code_hash) VALUES ($1, $2)`
+	for _, hash := range codeHashes {
+		if _, err := tx.Exec(ctx, sql, userID, hash); err != nil {
+			return database.HandleError(err, "recovery code", nil)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return database.HandleError(err, "recovery code", nil)
+	}
+	return nil
+}
+
+// Consume marks the matching unused code as used. It returns false when no
+// such code exists.
+func (r *recoveryCodeRepository) Consume(ctx context.Context, userID int64, codeHash string) (bool, error) {
+	sql := `UPDATE recovery_codes SET used_at = NOW() WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL`
+	tag, err := r.db.Exec(ctx, sql, userID, codeHash)
+	if err != nil {
+		return false, database.HandleError(err, "recovery code", nil)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *recoveryCodeRepository) CountUnused(ctx context.Context, userID int64) (int, error) {
+	sql := `SELECT COUNT(*) FROM recovery_codes WHERE user_id = $1 AND used_at IS NULL`
+	row := r.db.QueryRow(ctx, sql, userID)
+
+	var count int
+	if err := row.Scan(&count); err != nil {
+		return 0, database.HandleError(err, "recovery code", nil)
+	}
+	return count, nil
+}
+
+func (r *recoveryCodeRepository) DeleteByUserID(ctx context.Context, userID int64) error {
+	sql := `DELETE FROM recovery_codes WHERE user_id = $1`
+	if _, err := r.db.Exec(ctx, sql, userID); err != nil {
+		return database.HandleError(err, "recovery code", nil)
+	}
+	return nil
+}
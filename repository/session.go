@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mjmarrazzo/maintenance-app/domain"
+	"github.com/mjmarrazzo/maintenance-app/internal/database"
+)
+
+type SessionRepository interface {
+	Create(ctx context.Context, session *domain.Session) error
+	GetByHash(ctx context.Context, hash string) (*domain.Session, error)
+	GetActiveByUserID(ctx context.Context, userID int64) ([]*domain.Session, error)
+	Touch(ctx context.Context, id int64, lastSeenAt, expiresAt time.Time) error
+	Delete(ctx context.Context, userID, id int64) error
+	DeleteByHash(ctx context.Context, hash string) error
+	DeleteByUserID(ctx context.Context, userID int64) error
+	DeleteExpired(ctx context.Context) (int64, error)
+}
+
+type sessionRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewSessionRepository(db *pgxpool.Pool) SessionRepository {
+	return &sessionRepository{db: db}
+}
+
+const sessionColumns = `id, user_id, token_hash, COALESCE(user_agent, ''), COALESCE(ip_address, ''), created_at, last_seen_at, expires_at`
+
+func scanRowToSession(row pgx.Row, session *domain.Session) error {
+	return row.Scan(
+		&session.ID,
+		&session.UserID,
+		&session.TokenHash,
+		&session.UserAgent,
+		&session.IPAddress,
+		&session.CreatedAt,
+		&session.LastSeenAt,
+		&session.ExpiresAt,
+	)
+}
+
+func (r *sessionRepository) Create(ctx context.Context, session *domain.Session) error {
+	sql := `INSERT INTO sessions (user_id, token_hash, user_agent, ip_address, created_at, last_seen_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $5, $6)
+		RETURNING id`
+	row := r.db.QueryRow(ctx, sql,
+		session.UserID,
+		session.TokenHash,
+		session.UserAgent,
+		session.IPAddress,
+		session.CreatedAt,
+		session.ExpiresAt,
+	)
+
+	if err := row.Scan(&session.ID); err != nil {
+		return database.HandleError(err, "session", nil)
+	}
+	return nil
+}
+
+func (r *sessionRepository) GetByHash(ctx context.Context, hash string) (*domain.Session, error) {
+	sql := `SELECT ` + sessionColumns + ` FROM sessions WHERE token_hash = $1`
+	row := r.db.QueryRow(ctx, sql, hash)
+
+	session := &domain.Session{}
+	if err := scanRowToSession(row, session); err != nil {
+		return nil, database.HandleError(err, "session", nil)
+	}
+	return session, nil
+}
+
+func (r *sessionRepository) GetActiveByUserID(ctx context.Context, userID int64) ([]*domain.Session, error) {
+	sql := `SELECT ` + sessionColumns + `
+		FROM sessions
+		WHERE user_id = $1 AND expires_at > NOW()
+		ORDER BY last_seen_at DESC`
+	rows, err := r.db.Query(ctx, sql, userID)
+	if err != nil {
+		return nil, database.HandleError(err, "session", nil)
+	}
+	defer rows.Close()
+
+	sessions := []*domain.Session{}
+	for rows.Next() {
+		session := &domain.Session{}
+		if err := scanRowToSession(rows, session); err != nil {
+			return nil, database.HandleError(err, "session", nil)
+		}
+		sessions = append(sessions, session)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, database.HandleError(err, "session", nil)
+	}
+	return sessions, nil
+}
+
+func (r *sessionRepository) Touch(ctx context.Context, id int64, lastSeenAt, expiresAt time.Time) error {
+	sql := `UPDATE sessions SET last_seen_at = $2, expires_at = $3 WHERE id = $1`
+	if _, err := r.db.Exec(ctx, sql, id, lastSeenAt, expiresAt); err != nil {
+		return database.HandleError(err, "session", id)
+	}
+	return nil
+}
+
+func (r *sessionRepository) Delete(ctx context.Context, userID, id int64) error {
+	sql := `DELETE FROM sessions WHERE id = $1 AND user_id = $2 RETURNING id`
+	row := r.db.QueryRow(ctx, sql, id, userID)
+
+	if err := row.Scan(&id); err != nil {
+		return database.HandleError(err, "session", id)
+	}
+	return nil
+}
+
+func (r *sessionRepository) DeleteByHash(ctx context.Context, hash string) error {
+	sql := `DELETE FROM sessions WHERE token_hash = $1`
+	if _, err := r.db.Exec(ctx, sql, hash); err != nil {
+		return database.HandleError(err, "session", nil)
+	}
+	return nil
+}
+
+func (r *sessionRepository) DeleteByUserID(ctx context.Context, userID int64) error {
+	sql := `DELETE FROM sessions WHERE user_id = $1`
+	if _, err := r.db.Exec(ctx, sql, userID); err != nil {
+		return database.HandleError(err, "session", nil)
+	}
+	return nil
+}
+
+func (r *sessionRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	sql := `DELETE FROM sessions WHERE expires_at <= NOW()`
+	tag, err := r.db.Exec(ctx, sql)
+	if err != nil {
+		return 0, database.HandleError(err, "session", nil)
+	}
+	return tag.RowsAffected(), nil
+}
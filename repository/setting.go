@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mjmarrazzo/maintenance-app/internal/database"
+)
+
+type SettingRepository interface {
+	Get(ctx context.Context, key string) (string, error)
+	Set(ctx context.Context, key, value string) error
+}
+
+type settingRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewSettingRepository(db *pgxpool.Pool) SettingRepository {
+	return &settingRepository{db: db}
+}
+
+func (r *settingRepository) Get(ctx context.Context, key string) (string, error) {
+	sql := `SELECT value FROM settings WHERE key = $1`
+	row := r.db.QueryRow(ctx, sql, key)
+
+	var value string
+	if err := row.Scan(&value); err != nil {
+		return "", database.HandleError(err, "setting", key)
+	}
+	return value, nil
+}
+
+func (r *settingRepository) Set(ctx context.Context, key, value string) error {
+	sql := `INSERT INTO settings (key, value) VALUES ($1, $2)
+		ON CONFLICT (key) DO UPDATE SET value = EXCLUDED.value, updated_at = NOW()`
+	if _, err := r.db.Exec(ctx, sql, key, value); err != nil {
+		return database.HandleError(err, "setting", key)
+	}
+	return nil
+}
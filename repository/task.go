@@ -2,7 +2,11 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
+	"maps"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -17,12 +21,22 @@ type TaskRepository interface {
 	Create(ctx context.Context, task *domain.Task) error
 	GetByID(ctx context.Context, id int64) (*domain.Task, error)
 	GetAll(ctx context.Context, filters TaskFilters) ([]*domain.Task, error)
-	Update(ctx context.Context, task *domain.Task) error
+	// Update saves task. When task.UpdatedAt is set the row is only
+	// updated if it still has that updated_at, otherwise a not found error
+	// is returned. History entries are recorded for actorID.
+	Update(ctx context.Context, actorID int64, task *domain.Task) error
+	// Patch sets columns, keyed by name, and leaves the others as they
+	// are. updatedAt works as task.UpdatedAt in Update.
+	Patch(ctx context.Context, actorID, id int64, columns map[string]any, updatedAt time.Time) error
 	Delete(ctx context.Context, id int64) error
-	UpdateStatus(ctx context.Context, id int64, status domain.Status) error
+	// UpdateStatus sets the status and completed_at of the task. History
+	// entries are recorded for actorID.
+	UpdateStatus(ctx context.Context, actorID, id int64, status domain.Status) error
 	AssignTask(ctx context.Context, taskID int64, userID int64) error
 	CompleteTask(ctx context.Context, id int64) error
-	CountByStatus(ctx context.Context) (map[domain.Status]int, error)
+	// CountByStatus counts the tasks matching filters by status. Paging and
+	// sorting are ignored.
+	CountByStatus(ctx context.Context, filters TaskFilters) (map[domain.Status]int, error)
 	CountByPriority(ctx context.Context) (map[domain.Priority]int, error)
 }
 
@@ -38,6 +52,8 @@ type TaskFilters struct {
 	SearchQuery string
 	DateFrom    *time.Time
 	DateTo      *time.Time
+	DueFrom     *time.Time
+	DueTo       *time.Time
 	Limit       int
 	Offset      int
 	SortField   string
@@ -131,6 +147,7 @@ func scanRowToTask(row pgx.Row, task *domain.Task) error {
 		&task.RecurrenceUnit,
 		&task.ParentTaskID,
 		&task.NextOccurrence,
+		&task.CompletedAt,
 	)
 	if err != nil {
 		return fmt.Errorf("error scanning task: %w", err)
@@ -164,7 +181,8 @@ func (r *taskRepository) GetByID(ctx context.Context, id int64) (*domain.Task, e
 			t.recurrence_interval,
 			t.recurrence_unit,
 			t.parent_task_id,
-			t.next_occurrence
+			t.next_occurrence,
+			t.completed_at
 		FROM tasks t
 		LEFT JOIN categories c ON t.category_id = c.id
 		LEFT JOIN locations l ON t.location_id = l.id
@@ -210,7 +228,8 @@ func (r *taskRepository) GetAll(ctx context.Context, filters TaskFilters) ([]*do
 			t.recurrence_interval,
 			t.recurrence_unit,
 			t.parent_task_id,
-			t.next_occurrence
+			t.next_occurrence,
+			t.completed_at
 		FROM tasks t
 		LEFT JOIN categories c ON t.category_id = c.id
 		LEFT JOIN locations l ON t.location_id = l.id
@@ -218,133 +237,156 @@ func (r *taskRepository) GetAll(ctx context.Context, filters TaskFilters) ([]*do
 		LEFT JOIN users assignee ON t.assigned_to = assignee.id
 		WHERE 1=1
 	`
+	conditions, args := filterConditions(filters)
+	query += conditions
+	argIndex := len(args) + 1
+
+	if filters.SortField != "" {
+		sortOrder := "ASC"
+		if strings.ToUpper(filters.SortOrder) == "DESC" {
+			sortOrder = "DESC"
+		}
+
+		allowedFields := map[string]bool{
+			"id": true, "title": true, "priority": true, "status": true,
+			"created_at": true, "updated_at": true, "estimated_completion_date": true,
+			"completed_at": true,
+		}
+
+		if allowedFields[filters.SortField] {
+			query += fmt.Sprintf(" ORDER BY t.%s %s", filters.SortField, sortOrder)
+		} else {
+			query += " ORDER BY t.created_at DESC"
+		}
+	} else {
+		query += " ORDER BY t.created_at DESC"
+	}
+
+	if filters.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT $%d", argIndex)
+		args = append(args, filters.Limit)
+		argIndex++
+	}
+
+	if filters.Offset > 0 {
+		query += fmt.Sprintf(" OFFSET $%d", argIndex)
+		args = append(args, filters.Offset)
+	}
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error listing tasks: %w", err)
+	}
+	defer rows.Close()
+
+	tasks := []*domain.Task{}
+	for rows.Next() {
+		task := &domain.Task{}
+		if err := scanRowToTask(rows, task); err != nil {
+			return nil, database.HandleError(err, "task", nil)
+		}
+
+		tasks = append(tasks, task)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating tasks: %w", err)
+	}
+
+	return tasks, nil
+}
+
+// filterConditions returns the conditions on the tasks aliased t for
+// filters, starting with AND, and their arguments numbered from $1.
+func filterConditions(filters TaskFilters) (string, []interface{}) {
+	var query string
 	var args []interface{}
 	argIndex := 1
 
 	if filters.Status != nil {
-		query += fmt.Sprintf(" AND status = $%d", argIndex)
+		query += fmt.Sprintf(" AND t.status = $%d", argIndex)
 		args = append(args, *filters.Status)
 		argIndex++
 	}
 
 	if filters.Priority != nil {
-		query += fmt.Sprintf(" AND priority = $%d", argIndex)
+		query += fmt.Sprintf(" AND t.priority = $%d", argIndex)
 		args = append(args, *filters.Priority)
 		argIndex++
 	}
 
 	if filters.CategoryID != nil {
-		query += fmt.Sprintf(" AND category_id = $%d", argIndex)
+		query += fmt.Sprintf(" AND t.category_id = $%d", argIndex)
 		args = append(args, *filters.CategoryID)
 		argIndex++
 	}
 
 	if filters.LocationID != nil {
-		query += fmt.Sprintf(" AND location_id = $%d", argIndex)
+		query += fmt.Sprintf(" AND t.location_id = $%d", argIndex)
 		args = append(args, *filters.LocationID)
 		argIndex++
 	}
 
 	if filters.AssignedTo != nil {
-		query += fmt.Sprintf(" AND assigned_to = $%d", argIndex)
+		query += fmt.Sprintf(" AND t.assigned_to = $%d", argIndex)
 		args = append(args, *filters.AssignedTo)
 		argIndex++
 	}
 
 	if filters.CreatedBy != nil {
-		query += fmt.Sprintf(" AND created_by = $%d", argIndex)
+		query += fmt.Sprintf(" AND t.created_by = $%d", argIndex)
 		args = append(args, *filters.CreatedBy)
 		argIndex++
 	}
 
 	if filters.IsCompleted != nil {
 		if *filters.IsCompleted {
-			query += " AND status = 'Completed'"
+			query += " AND t.status = 'Completed'"
 		} else {
-			query += " AND status != 'Completed'"
+			query += " AND t.status != 'Completed'"
 		}
 	}
 
 	if filters.IsRecurring != nil {
-		query += fmt.Sprintf(" AND is_recurring = $%d", argIndex)
+		query += fmt.Sprintf(" AND t.is_recurring = $%d", argIndex)
 		args = append(args, *filters.IsRecurring)
 		argIndex++
 	}
 
 	if filters.SearchQuery != "" {
-		query += fmt.Sprintf(" AND (title ILIKE $%d OR description ILIKE $%d)", argIndex, argIndex)
+		query += fmt.Sprintf(" AND (t.title ILIKE $%d OR t.description ILIKE $%d)", argIndex, argIndex)
 		searchPattern := "%" + filters.SearchQuery + "%"
 		args = append(args, searchPattern)
 		argIndex++
 	}
 
 	if filters.DateFrom != nil {
-		query += fmt.Sprintf(" AND created_at >= $%d", argIndex)
+		query += fmt.Sprintf(" AND t.created_at >= $%d", argIndex)
 		args = append(args, *filters.DateFrom)
 		argIndex++
 	}
 
 	if filters.DateTo != nil {
-		query += fmt.Sprintf(" AND created_at <= $%d", argIndex)
+		query += fmt.Sprintf(" AND t.created_at <= $%d", argIndex)
 		args = append(args, *filters.DateTo)
 		argIndex++
 	}
 
-	if filters.SortField != "" {
-		sortOrder := "ASC"
-		if strings.ToUpper(filters.SortOrder) == "DESC" {
-			sortOrder = "DESC"
-		}
-
-		allowedFields := map[string]bool{
-			"id": true, "title": true, "priority": true, "status": true,
-			"created_at": true, "updated_at": true, "estimated_completion_date": true,
-		}
-
-		if allowedFields[filters.SortField] {
-			query += fmt.Sprintf(" ORDER BY %s %s", filters.SortField, sortOrder)
-		} else {
-			query += " ORDER BY created_at DESC"
-		}
-	} else {
-		query += " ORDER BY created_at DESC"
-	}
-
-	if filters.Limit > 0 {
-		query += fmt.Sprintf(" LIMIT $%d", argIndex)
-		args = append(args, filters.Limit)
+	if filters.DueFrom != nil {
+		query += fmt.Sprintf(" AND t.estimated_completion_date >= $%d", argIndex)
+		args = append(args, *filters.DueFrom)
 		argIndex++
 	}
 
-	if filters.Offset > 0 {
-		query += fmt.Sprintf(" OFFSET $%d", argIndex)
-		args = append(args, filters.Offset)
-	}
-
-	rows, err := r.db.Query(ctx, query, args...)
-	if err != nil {
-		return nil, fmt.Errorf("error listing tasks: %w", err)
+	if filters.DueTo != nil {
+		query += fmt.Sprintf(" AND t.estimated_completion_date < $%d", argIndex)
+		args = append(args, *filters.DueTo)
 	}
-	defer rows.Close()
 
-	tasks := []*domain.Task{}
-	for rows.Next() {
-		task := &domain.Task{}
-		if err := scanRowToTask(rows, task); err != nil {
-			return nil, database.HandleError(err, "task", filters)
-		}
-
-		tasks = append(tasks, task)
-	}
-
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating tasks: %w", err)
-	}
-
-	return tasks, nil
+	return query, args
 }
 
-func (r *taskRepository) Update(ctx context.Context, task *domain.Task) error {
+func (r *taskRepository) Update(ctx context.Context, actorID int64, task *domain.Task) error {
 	query := `
 		UPDATE tasks SET
 			title = $1,
@@ -361,37 +403,85 @@ func (r *taskRepository) Update(ctx context.Context, task *domain.Task) error {
 			recurrence_interval = $12,
 			recurrence_unit = $13,
 			parent_task_id = $14,
+			completed_at = CASE WHEN $6 = 'Completed' THEN COALESCE(completed_at, NOW()) END,
 			updated_at = NOW()
-		WHERE id = $15
+		WHERE id = $15 AND ($16::timestamptz IS NULL OR updated_at = $16)
 		RETURNING updated_at`
 
-	err := r.db.QueryRow(
-		ctx,
-		query,
-		task.Title,
-		task.Description,
-		task.CategoryID,
-		task.LocationID,
-		task.Priority,
-		task.Status,
-		task.AssignedTo,
-		task.EstimatedCompletionDate,
-		task.Cost,
-		task.IsRecurring,
-		task.RecurrenceType,
-		task.RecurrenceInterval,
-		task.RecurrenceUnit,
-		task.ParentTaskID,
-		task.ID,
-	).Scan(&task.UpdatedAt)
+	return r.inActorTx(ctx, actorID, task.ID, func(tx pgx.Tx) error {
+		return tx.QueryRow(
+			ctx,
+			query,
+			task.Title,
+			task.Description,
+			task.CategoryID,
+			task.LocationID,
+			task.Priority,
+			task.Status,
+			task.AssignedTo,
+			task.EstimatedCompletionDate,
+			task.Cost,
+			task.IsRecurring,
+			task.RecurrenceType,
+			task.RecurrenceInterval,
+			task.RecurrenceUnit,
+			task.ParentTaskID,
+			task.ID,
+			expectedUpdatedAt(task.UpdatedAt),
+		).Scan(&task.UpdatedAt)
+	})
+}
 
+func (r *taskRepository) Patch(ctx context.Context, actorID, id int64, columns map[string]any, updatedAt time.Time) error {
+	args := []any{id, expectedUpdatedAt(updatedAt)}
+	var sets []string
+	for _, column := range slices.Sorted(maps.Keys(columns)) {
+		args = append(args, columns[column])
+		sets = append(sets, fmt.Sprintf("%s = $%d", column, len(args)))
+		if column == "status" {
+			sets = append(sets, fmt.Sprintf("completed_at = CASE WHEN $%d = 'Completed' THEN COALESCE(completed_at, NOW()) END", len(args)))
+		}
+	}
+	sets = append(sets, "updated_at = NOW()")
+
+	query := fmt.Sprintf(`
+		UPDATE tasks SET %s
+		WHERE id = $1 AND ($2::timestamptz IS NULL OR updated_at = $2)
+		RETURNING updated_at`, strings.Join(sets, ", "))
+
+	return r.inActorTx(ctx, actorID, id, func(tx pgx.Tx) error {
+		var updatedAt time.Time
+		return tx.QueryRow(ctx, query, args...).Scan(&updatedAt)
+	})
+}
+
+// inActorTx runs fn in a transaction whose task_history entries are
+// attributed to actorID, see log_task_changes in schema.sql.
+func (r *taskRepository) inActorTx(ctx context.Context, actorID, taskID int64, fn func(tx pgx.Tx) error) error {
+	tx, err := r.db.Begin(ctx)
 	if err != nil {
-		return database.HandleError(err, "task", task.ID)
+		return database.HandleError(err, "task", taskID)
 	}
+	defer tx.Rollback(ctx)
 
+	if _, err := tx.Exec(ctx, `SELECT set_config('app.user_id', $1, true)`, strconv.FormatInt(actorID, 10)); err != nil {
+		return database.HandleError(err, "task", taskID)
+	}
+	if err := fn(tx); err != nil {
+		return database.HandleError(err, "task", taskID)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return database.HandleError(err, "task", taskID)
+	}
 	return nil
 }
 
+// expectedUpdatedAt makes an update conditional on updatedAt unless it is
+// zero.
+func expectedUpdatedAt(updatedAt time.Time) sql.NullTime {
+	return sql.NullTime{Time: updatedAt, Valid: !updatedAt.IsZero()}
+}
+
 func (r *taskRepository) Delete(ctx context.Context, id int64) error {
 	query := `DELETE FROM tasks WHERE id = $1`
 	ct, err := r.db.Exec(ctx, query, id)
@@ -406,7 +496,7 @@ func (r *taskRepository) Delete(ctx context.Context, id int64) error {
 	return nil
 }
 
-func (r *taskRepository) UpdateStatus(ctx context.Context, id int64, status domain.Status) error {
+func (r *taskRepository) UpdateStatus(ctx context.Context, actorID, id int64, status domain.Status) error {
 	query := `
 		UPDATE tasks SET
 			status = $1,
@@ -414,13 +504,10 @@ func (r *taskRepository) UpdateStatus(ctx context.Context, id int64, status doma
 		WHERE id = $2
 		RETURNING updated_at`
 
-	var updatedAt time.Time
-	err := r.db.QueryRow(ctx, query, status, id).Scan(&updatedAt)
-	if err != nil {
-		return database.HandleError(err, "task", id)
-	}
-
-	return nil
+	return r.inActorTx(ctx, actorID, id, func(tx pgx.Tx) error {
+		var updatedAt time.Time
+		return tx.QueryRow(ctx, query, status, id).Scan(&updatedAt)
+	})
 }
 
 func (r *taskRepository) AssignTask(ctx context.Context, taskID int64, userID int64) error {
@@ -454,13 +541,15 @@ func (r *taskRepository) CompleteTask(ctx context.Context, id int64) error {
 	return nil
 }
 
-func (r *taskRepository) CountByStatus(ctx context.Context) (map[domain.Status]int, error) {
+func (r *taskRepository) CountByStatus(ctx context.Context, filters TaskFilters) (map[domain.Status]int, error) {
+	conditions, args := filterConditions(filters)
 	query := `
-		SELECT status, COUNT(*) as count
-		FROM tasks
-		GROUP BY status`
+		SELECT t.status, COUNT(*) as count
+		FROM tasks t
+		WHERE 1=1` + conditions + `
+		GROUP BY t.status`
 
-	rows, err := r.db.Query(ctx, query)
+	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("error counting tasks by status: %w", err)
 	}
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mjmarrazzo/maintenance-app/domain"
+	"github.com/mjmarrazzo/maintenance-app/internal/database"
+)
+
+type TaskReminderRepository interface {
+	Claim(ctx context.Context, taskID int64, kind domain.TaskEventKind, dueAt time.Time) (bool, error)
+}
+
+type taskReminderRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewTaskReminderRepository(db *pgxpool.Pool) TaskReminderRepository {
+	return &taskReminderRepository{db: db}
+}
+
+// Claim records that the kind reminder for the task due at dueAt is being
+// sent and reports whether it was not sent before. Moving the due date makes
+// the reminder due again.
+func (r *taskReminderRepository) Claim(ctx context.Context, taskID int64, kind domain.TaskEventKind, dueAt time.Time) (bool, error) {
+	sql := `INSERT INTO task_reminders (task_id, kind, due_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT DO NOTHING`
+	tag, err := r.db.Exec(ctx, sql, taskID, kind, dueAt)
+	if err != nil {
+		return false, database.HandleError(err, "task reminder", taskID)
+	}
+	return tag.RowsAffected() == 1, nil
+}
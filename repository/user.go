@@ -2,7 +2,10 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/mjmarrazzo/maintenance-app/domain"
 	"github.com/mjmarrazzo/maintenance-app/internal/database"
@@ -11,6 +14,18 @@ import (
 type UserRepository interface {
 	CreateUser(ctx context.Context, user *domain.User) error
 	GetUserByEmail(ctx context.Context, email string) (*domain.User, error)
+	GetUserByID(ctx context.Context, id int64) (*domain.User, error)
+	GetAll(ctx context.Context) ([]*domain.User, error)
+	UpdatePreferences(ctx context.Context, id int64, locale, timeZone sql.NullString) error
+	MarkEmailVerified(ctx context.Context, id int64) error
+	IncrementFailedLogins(ctx context.Context, id int64) (int, error)
+	Lock(ctx context.Context, id int64, until time.Time) error
+	ResetFailedLogins(ctx context.Context, id int64) error
+	GetLocked(ctx context.Context) ([]*domain.User, error)
+	SetTOTPSecret(ctx context.Context, id int64, secret string) error
+	EnableTOTP(ctx context.Context, id int64, counter int64) error
+	DisableTOTP(ctx context.Context, id int64) error
+	UseTOTPCounter(ctx context.Context, id int64, counter int64) (bool, error)
 }
 
 type userRepository struct {
@@ -21,15 +36,28 @@ func NewUserRepository(db *pgxpool.Pool) UserRepository {
 	return &userRepository{db: db}
 }
 
-func (r *userRepository) CreateUser(ctx context.Context, user *domain.User) error {
-	sql := `INSERT INTO users (first_name, last_name, email, password_hash, role) VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at`
-	row := r.db.QueryRow(ctx, sql,
+const userColumns = `id, first_name, last_name, email, password_hash, role, email_verified_at, failed_login_count, locked_until, totp_secret, totp_enabled_at, totp_last_counter, locale, time_zone, created_at`
+
+func scanRowToUser(row pgx.Row, user *domain.User) error {
+	return row.Scan(&user.ID, &user.FirstName, &user.LastName, &user.Email, &user.PasswordHash, &user.Role, &user.EmailVerifiedAt, &user.FailedLoginCount, &user.LockedUntil, &user.TOTPSecret, &user.TOTPEnabledAt, &user.TOTPLastCounter, &user.Locale, &user.TimeZone, &user.CreatedAt)
+}
+
+// createUserSQL inserts a user from createUserArgs.
+const createUserSQL = `INSERT INTO users (first_name, last_name, email, password_hash, role, email_verified_at) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, created_at`
+
+func createUserArgs(user *domain.User) []interface{} {
+	return []interface{}{
 		user.FirstName,
 		user.LastName,
 		user.Email,
 		user.PasswordHash,
 		user.Role,
-	)
+		user.EmailVerifiedAt,
+	}
+}
+
+func (r *userRepository) CreateUser(ctx context.Context, user *domain.User) error {
+	row := r.db.QueryRow(ctx, createUserSQL, createUserArgs(user)...)
 
 	if err := row.Scan(&user.ID, &user.CreatedAt); err != nil {
 		return database.HandleError(err, "user", nil)
@@ -39,13 +67,152 @@ func (r *userRepository) CreateUser(ctx context.Context, user *domain.User) erro
 }
 
 func (r *userRepository) GetUserByEmail(ctx context.Context, email string) (*domain.User, error) {
-	sql := `SELECT id, first_name, last_name, email, password_hash, role, created_at FROM users WHERE email = $1`
+	sql := `SELECT ` + userColumns + ` FROM users WHERE email = $1`
 	row := r.db.QueryRow(ctx, sql, email)
 
 	user := &domain.User{}
-	if err := row.Scan(&user.ID, &user.FirstName, &user.LastName, &user.Email, &user.PasswordHash, &user.Role, &user.CreatedAt); err != nil {
+	if err := scanRowToUser(row, user); err != nil {
 		return nil, database.HandleError(err, "user", nil)
 	}
 
 	return user, nil
 }
+
+func (r *userRepository) GetUserByID(ctx context.Context, id int64) (*domain.User, error) {
+	sql := `SELECT ` + userColumns + ` FROM users WHERE id = $1`
+	row := r.db.QueryRow(ctx, sql, id)
+
+	user := &domain.User{}
+	if err := scanRowToUser(row, user); err != nil {
+		return nil, database.HandleError(err, "user", id)
+	}
+
+	return user, nil
+}
+
+func (r *userRepository) GetAll(ctx context.Context) ([]*domain.User, error) {
+	sql := `SELECT ` + userColumns + ` FROM users ORDER BY last_name, first_name`
+	rows, err := r.db.Query(ctx, sql)
+	if err != nil {
+		return nil, database.HandleError(err, "user", nil)
+	}
+	defer rows.Close()
+
+	users := []*domain.User{}
+	for rows.Next() {
+		user := &domain.User{}
+		if err := scanRowToUser(rows, user); err != nil {
+			return nil, database.HandleError(err, "user", nil)
+		}
+		users = append(users, user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, database.HandleError(err, "user", nil)
+	}
+
+	return users, nil
+}
+
+func (r *userRepository) UpdatePreferences(ctx context.Context, id int64, locale, timeZone sql.NullString) error {
+	sql := `UPDATE users SET locale = $2, time_zone = $3 WHERE id = $1`
+	if _, err := r.db.Exec(ctx, sql, id, locale, timeZone); err != nil {
+		return database.HandleError(err, "user", id)
+	}
+	return nil
+}
+
+func (r *userRepository) MarkEmailVerified(ctx context.Context, id int64) error {
+	sql := `UPDATE users SET email_verified_at = COALESCE(email_verified_at, NOW()) WHERE id = $1`
+	if _, err := r.db.Exec(ctx, sql, id); err != nil {
+		return database.HandleError(err, "user", id)
+	}
+	return nil
+}
+
+func (r *userRepository) IncrementFailedLogins(ctx context.Context, id int64) (int, error) {
+	sql := `UPDATE users SET failed_login_count = failed_login_count + 1 WHERE id = $1 RETURNING failed_login_count`
+	row := r.db.QueryRow(ctx, sql, id)
+
+	var count int
+	if err := row.Scan(&count); err != nil {
+		return 0, database.HandleError(err, "user", id)
+	}
+	return count, nil
+}
+
+func (r *userRepository) Lock(ctx context.Context, id int64, until time.Time) error {
+	sql := `UPDATE users SET locked_until = $2 WHERE id = $1`
+	if _, err := r.db.Exec(ctx, sql, id, until); err != nil {
+		return database.HandleError(err, "user", id)
+	}
+	return nil
+}
+
+func (r *userRepository) ResetFailedLogins(ctx context.Context, id int64) error {
+	sql := `UPDATE users SET failed_login_count = 0, locked_until = NULL WHERE id = $1`
+	if _, err := r.db.Exec(ctx, sql, id); err != nil {
+		return database.HandleError(err, "user", id)
+	}
+	return nil
+}
+
+func (r *userRepository) GetLocked(ctx context.Context) ([]*domain.User, error) {
+	sql := `SELECT ` + userColumns + ` FROM users WHERE locked_until > NOW() ORDER BY locked_until DESC`
+	rows, err := r.db.Query(ctx, sql)
+	if err != nil {
+		return nil, database.HandleError(err, "user", nil)
+	}
+	defer rows.Close()
+
+	users := []*domain.User{}
+	for rows.Next() {
+		user := &domain.User{}
+		if err := scanRowToUser(rows, user); err != nil {
+			return nil, database.HandleError(err, "user", nil)
+		}
+		users = append(users, user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, database.HandleError(err, "user", nil)
+	}
+
+	return users, nil
+}
+
+// SetTOTPSecret stores the secret of an enrollment that has not been
+// confirmed yet. Users that already have two-factor enabled are left alone.
+func (r *userRepository) SetTOTPSecret(ctx context.Context, id int64, secret string) error {
+	sql := `UPDATE users SET totp_secret = $2 WHERE id = $1 AND totp_enabled_at IS NULL`
+	if _, err := r.db.Exec(ctx, sql, id, secret); err != nil {
+		return database.HandleError(err, "user", id)
+	}
+	return nil
+}
+
+func (r *userRepository) EnableTOTP(ctx context.Context, id int64, counter int64) error {
+	sql := `UPDATE users SET totp_enabled_at = NOW(), totp_last_counter = $2 WHERE id = $1 AND totp_secret IS NOT NULL`
+	if _, err := r.db.Exec(ctx, sql, id, counter); err != nil {
+		return database.HandleError(err, "user", id)
+	}
+	return nil
+}
+
+func (r *userRepository) DisableTOTP(ctx context.Context, id int64) error {
+	sql := `UPDATE users SET totp_secret = NULL, totp_enabled_at = NULL, totp_last_counter = 0 WHERE id = $1`
+	if _, err := r.db.Exec(ctx, sql, id); err != nil {
+		return database.HandleError(err, "user", id)
+	}
+	return nil
+}
+
+// UseTOTPCounter records counter as the latest accepted time step. It
+// returns false when that step or a later one was already used, so every
+// code is accepted at most once.
+func (r *userRepository) UseTOTPCounter(ctx context.Context, id int64, counter int64) (bool, error) {
+	sql := `UPDATE users SET totp_last_counter = $2 WHERE id = $1 AND totp_last_counter < $2`
+	tag, err := r.db.Exec(ctx, sql, id, counter)
+	if err != nil {
+		return false, database.HandleError(err, "user", id)
+	}
+	return tag.RowsAffected() == 1, nil
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mjmarrazzo/maintenance-app/domain"
+	"github.com/mjmarrazzo/maintenance-app/internal/database"
+)
+
+type UserIdentityRepository interface {
+	Create(ctx context.Context, identity *domain.UserIdentity) error
+	GetByProviderSubject(ctx context.Context, provider, subject string) (*domain.UserIdentity, error)
+	TouchLastLogin(ctx context.Context, id int64) error
+}
+
+type userIdentityRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewUserIdentityRepository(db *pgxpool.Pool) UserIdentityRepository {
+	return &userIdentityRepository{db: db}
+}
+
+const userIdentityColumns = `id, user_id, provider, subject, email, last_login_at, created_at`
+
+func scanRowToUserIdentity(row pgx.Row, identity *domain.UserIdentity) error {
+	return row.Scan(
+		&identity.ID,
+		&identity.UserID,
+		&identity.Provider,
+		&identity.Subject,
+		&identity.Email,
+		&identity.LastLoginAt,
+		&identity.CreatedAt,
+	)
+}
+
+func (r *userIdentityRepository) Create(ctx context.Context, identity *domain.UserIdentity) error {
+	sql := `INSERT INTO user_identities (user_id, provider, subject, email, last_login_at)
+		VALUES ($1, $2, $3, $4, NOW())
+		RETURNING id, last_login_at, created_at`
+	row := r.db.QueryRow(ctx, sql,
+		identity.UserID,
+		identity.Provider,
+		identity.Subject,
+		identity.Email,
+	)
+
+	if err := row.Scan(&identity.ID, &identity.LastLoginAt, &identity.CreatedAt); err != nil {
+		return database.HandleError(err, "user identity", nil)
+	}
+	return nil
+}
+
+func (r *userIdentityRepository) GetByProviderSubject(ctx context.Context, provider, subject string) (*domain.UserIdentity, error) {
+	sql := `SELECT ` + userIdentityColumns + ` FROM user_identities WHERE provider = $1 AND subject = $2`
+	row := r.db.QueryRow(ctx, sql, provider, subject)
+
+	identity := &domain.UserIdentity{}
+	if err := scanRowToUserIdentity(row, identity); err != nil {
+		return nil, database.HandleError(err, "user identity", subject)
+	}
+	return identity, nil
+}
+
+func (r *userIdentityRepository) TouchLastLogin(ctx context.Context, id int64) error {
+	sql := `UPDATE user_identities SET last_login_at = NOW() WHERE id = $1`
+	if _, err := r.db.Exec(ctx, sql, id); err != nil {
+		return database.HandleError(err, "user identity", id)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mjmarrazzo/maintenance-app/domain"
+	"github.com/mjmarrazzo/maintenance-app/internal/database"
+)
+
+type UserTokenRepository interface {
+	Create(ctx context.Context, token *domain.UserToken) error
+	// Consume marks a valid, unused token as used and returns it. Expired,
+	// used or unknown tokens yield a not found error.
+	Consume(ctx context.Context, purpose domain.TokenPurpose, hash string) (*domain.UserToken, error)
+	// ResetPassword consumes a password reset token like Consume and sets
+	// the password of its user in the same transaction, so the token stays
+	// usable if the password cannot be saved.
+	ResetPassword(ctx context.Context, hash, passwordHash string) (*domain.UserToken, error)
+	InvalidateForUser(ctx context.Context, userID int64, purpose domain.TokenPurpose) error
+}
+
+type userTokenRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewUserTokenRepository(db *pgxpool.Pool) UserTokenRepository {
+	return &userTokenRepository{db: db}
+}
+
+func (r *userTokenRepository) Create(ctx context.Context, token *domain.UserToken) error {
+	sql := `INSERT INTO user_tokens (user_id, purpose, token_hash, expires_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at`
+	row := r.db.QueryRow(ctx, sql, token.UserID, token.Purpose, token.TokenHash, token.ExpiresAt)
+
+	if err := row.Scan(&token.ID, &token.CreatedAt); err != nil {
+		return database.HandleError(err, "token", nil)
+	}
+	return nil
+}
+
+// consumeSQL marks the token $1 for purpose $2 as used if it is valid.
+const consumeSQL = `UPDATE user_tokens SET used_at = NOW()
+	WHERE token_hash = $1 AND purpose = $2 AND used_at IS NULL AND expires_at > NOW()
+	RETURNING id, user_id, purpose, token_hash, expires_at, used_at, created_at`
+
+func scanRowToUserToken(row pgx.Row, token *domain.UserToken) error {
+	return row.Scan(
+		&token.ID,
+		&token.UserID,
+		&token.Purpose,
+		&token.TokenHash,
+		&token.ExpiresAt,
+		&token.UsedAt,
+		&token.CreatedAt,
+	)
+}
+
+func (r *userTokenRepository) Consume(ctx context.Context, purpose domain.TokenPurpose, hash string) (*domain.UserToken, error) {
+	token := &domain.UserToken{}
+	if err := scanRowToUserToken(r.db.QueryRow(ctx, consumeSQL, hash, purpose), token); err != nil {
+		return nil, database.HandleError(err, "token", nil)
+	}
+	return token, nil
+}
+
+func (r *userTokenRepository) ResetPassword(ctx context.Context, hash, passwordHash string) (*domain.UserToken, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, database.HandleError(err, "token", nil)
+	}
+	defer tx.Rollback(ctx)
+
+	token := &domain.UserToken{}
+	if err := scanRowToUserToken(tx.QueryRow(ctx, consumeSQL, hash, domain.PurposePasswordReset), token); err != nil {
+		return nil, database.HandleError(err, "token", nil)
+	}
+
+	sql := `UPDATE users SET password_hash = $2 WHERE id = $1`
+	if _, err := tx.Exec(ctx, sql, token.UserID, passwordHash); err != nil {
+		return nil, database.HandleError(err, "user", token.UserID)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, database.HandleError(err, "token", nil)
+	}
+	return token, nil
+}
+
+func (r *userTokenRepository) InvalidateForUser(ctx context.Context, userID int64, purpose domain.TokenPurpose) error {
+	sql := `UPDATE user_tokens SET used_at = NOW() WHERE user_id = $1 AND purpose = $2 AND used_at IS NULL`
+	if _, err := r.db.Exec(ctx, sql, userID, purpose); err != nil {
+		return database.HandleError(err, "token", nil)
+	}
+	return nil
+}
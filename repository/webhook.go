@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mjmarrazzo/maintenance-app/domain"
+	"github.com/mjmarrazzo/maintenance-app/internal/database"
+)
+
+type WebhookRepository interface {
+	Create(ctx context.Context, webhook *domain.Webhook) error
+	GetAll(ctx context.Context) ([]*domain.Webhook, error)
+	GetByID(ctx context.Context, id int64) (*domain.Webhook, error)
+	GetSubscribed(ctx context.Context, kind domain.TaskEventKind) ([]*domain.Webhook, error)
+	Update(ctx context.Context, webhook *domain.Webhook) error
+	Delete(ctx context.Context, id int64) error
+}
+
+type webhookRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewWebhookRepository(db *pgxpool.Pool) WebhookRepository {
+	return &webhookRepository{db: db}
+}
+
+const webhookColumns = `id, name, url, secret, events, is_active, created_by, created_at, updated_at`
+
+func scanRowToWebhook(row pgx.Row, webhook *domain.Webhook) error {
+	return row.Scan(
+		&webhook.ID,
+		&webhook.Name,
+		&webhook.URL,
+		&webhook.Secret,
+		&webhook.Events,
+		&webhook.IsActive,
+		&webhook.CreatedBy,
+		&webhook.CreatedAt,
+		&webhook.UpdatedAt,
+	)
+}
+
+func (r *webhookRepository) Create(ctx context.Context, webhook *domain.Webhook) error {
+	sql := `INSERT INTO webhooks (name, url, secret, events, is_active, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at, updated_at`
+	row := r.db.QueryRow(ctx, sql,
+		webhook.Name,
+		webhook.URL,
+		webhook.Secret,
+		webhook.Events,
+		webhook.IsActive,
+		webhook.CreatedBy,
+	)
+
+	if err := row.Scan(&webhook.ID, &webhook.CreatedAt, &webhook.UpdatedAt); err != nil {
+		return database.HandleError(err, "webhook", nil)
+	}
+	return nil
+}
+
+func (r *webhookRepository) GetAll(ctx context.Context) ([]*domain.Webhook, error) {
+	sql := `SELECT ` + webhookColumns + ` FROM webhooks ORDER BY name, id`
+	return r.query(ctx, sql)
+}
+
+func (r *webhookRepository) GetByID(ctx context.Context, id int64) (*domain.Webhook, error) {
+	sql := `SELECT ` + webhookColumns + ` FROM webhooks WHERE id = $1`
+	row := r.db.QueryRow(ctx, sql, id)
+
+	webhook := &domain.Webhook{}
+	if err := scanRowToWebhook(row, webhook); err != nil {
+		return nil, database.HandleError(err, "webhook", id)
+	}
+	return webhook, nil
+}
+
+// GetSubscribed returns the active webhooks subscribed to kind.
+func (r *webhookRepository) GetSubscribed(ctx context.Context, kind domain.TaskEventKind) ([]*domain.Webhook, error) {
+	sql := `SELECT ` + webhookColumns + ` FROM webhooks WHERE is_active AND $1 = ANY(events) ORDER BY id`
+	return r.query(ctx, sql, string(kind))
+}
+
+func (r *webhookRepository) query(ctx context.Context, sql string, args ...any) ([]*domain.Webhook, error) {
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, database.HandleError(err, "webhook", nil)
+	}
+	defer rows.Close()
+
+	webhooks := []*domain.Webhook{}
+	for rows.Next() {
+		webhook := &domain.Webhook{}
+		if err := scanRowToWebhook(rows, webhook); err != nil {
+			return nil, database.HandleError(err, "webhook", nil)
+		}
+		webhooks = append(webhooks, webhook)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, database.HandleError(err, "webhook", nil)
+	}
+	return webhooks, nil
+}
+
+func (r *webhookRepository) Update(ctx context.Context, webhook *domain.Webhook) error {
+	sql := `UPDATE webhooks
+		SET name = $2, url = $3, secret = $4, events = $5, is_active = $6, updated_at = NOW()
+		WHERE id = $1
+		RETURNING updated_at`
+	row := r.db.QueryRow(ctx, sql,
+		webhook.ID,
+		webhook.Name,
+		webhook.URL,
+		webhook.Secret,
+		webhook.Events,
+		webhook.IsActive,
+	)
+
+	if err := row.Scan(&webhook.UpdatedAt); err != nil {
+		return database.HandleError(err, "webhook", webhook.ID)
+	}
+	return nil
+}
+
+func (r *webhookRepository) Delete(ctx context.Context, id int64) error {
+	sql := `DELETE FROM webhooks WHERE id = $1`
+	if _, err := r.db.Exec(ctx, sql, id); err != nil {
+		return database.HandleError(err, "webhook", id)
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mjmarrazzo/maintenance-app/domain"
+	"github.com/mjmarrazzo/maintenance-app/internal/database"
+)
+
+type WebhookDeliveryRepository interface {
+	Create(ctx context.Context, delivery *domain.WebhookDelivery) error
+	GetByWebhookID(ctx context.Context, webhookID int64, limit int) ([]*domain.WebhookDelivery, error)
+	ClaimDue(ctx context.Context, now time.Time, limit int) ([]*domain.WebhookDelivery, error)
+	RecordAttempt(ctx context.Context, delivery *domain.WebhookDelivery) error
+}
+
+type webhookDeliveryRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewWebhookDeliveryRepository(db *pgxpool.Pool) WebhookDeliveryRepository {
+	return &webhookDeliveryRepository{db: db}
+}
+
+const webhookDeliveryColumns = `id, webhook_id, event, payload, attempts, next_attempt_at, response_status, response_body, last_error, delivered_at, failed_at, created_at`
+
+func scanRowToWebhookDelivery(row pgx.Row, delivery *domain.WebhookDelivery) error {
+	return row.Scan(
+		&delivery.ID,
+		&delivery.WebhookID,
+		&delivery.Event,
+		&delivery.Payload,
+		&delivery.Attempts,
+		&delivery.NextAttemptAt,
+		&delivery.ResponseStatus,
+		&delivery.ResponseBody,
+		&delivery.LastError,
+		&delivery.DeliveredAt,
+		&delivery.FailedAt,
+		&delivery.CreatedAt,
+	)
+}
+
+func (r *webhookDeliveryRepository) Create(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	sql := `INSERT INTO webhook_deliveries (webhook_id, event, payload)
+		VALUES ($1, $2, $3)
+		RETURNING id, next_attempt_at, created_at`
+	row := r.db.QueryRow(ctx, sql, delivery.WebhookID, delivery.Event, delivery.Payload)
+
+	if err := row.Scan(&delivery.ID, &delivery.NextAttemptAt, &delivery.CreatedAt); err != nil {
+		return database.HandleError(err, "webhook delivery", nil)
+	}
+	return nil
+}
+
+func (r *webhookDeliveryRepository) GetByWebhookID(ctx context.Context, webhookID int64, limit int) ([]*domain.WebhookDelivery, error) {
+	sql := `SELECT ` + webhookDeliveryColumns + ` FROM webhook_deliveries
+		WHERE webhook_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2`
+	return r.query(ctx, sql, webhookID, limit)
+}
+
+// ClaimDue returns up to limit deliveries that are due and hides them from
+// other senders for domain.WebhookLease, so a crashed sender only delays
+// them.
+func (r *webhookDeliveryRepository) ClaimDue(ctx context.Context, now time.Time, limit int) ([]*domain.WebhookDelivery, error) {
+	sql := `UPDATE webhook_deliveries SET next_attempt_at = $3
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE delivered_at IS NULL AND failed_at IS NULL AND next_attempt_at <= $1
+			ORDER BY next_attempt_at, id
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + webhookDeliveryColumns
+	return r.query(ctx, sql, now, limit, now.Add(domain.WebhookLease))
+}
+
+func (r *webhookDeliveryRepository) query(ctx context.Context, sql string, args ...any) ([]*domain.WebhookDelivery, error) {
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, database.HandleError(err, "webhook delivery", nil)
+	}
+	defer rows.Close()
+
+	deliveries := []*domain.WebhookDelivery{}
+	for rows.Next() {
+		delivery := &domain.WebhookDelivery{}
+		if err := scanRowToWebhookDelivery(rows, delivery); err != nil {
+			return nil, database.HandleError(err, "webhook delivery", nil)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, database.HandleError(err, "webhook delivery", nil)
+	}
+	return deliveries, nil
+}
+
+// RecordAttempt saves the outcome of the latest attempt to send delivery.
+func (r *webhookDeliveryRepository) RecordAttempt(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	sql := `UPDATE webhook_deliveries
+		SET attempts = $2, next_attempt_at = $3, response_status = $4, response_body = $5,
+			last_error = $6, delivered_at = $7, failed_at = $8
+		WHERE id = $1`
+	_, err := r.db.Exec(ctx, sql,
+		delivery.ID,
+		delivery.Attempts,
+		delivery.NextAttemptAt,
+		delivery.ResponseStatus,
+		delivery.ResponseBody,
+		delivery.LastError,
+		delivery.DeliveredAt,
+		delivery.FailedAt,
+	)
+	if err != nil {
+		return database.HandleError(err, "webhook delivery", delivery.ID)
+	}
+	return nil
+}
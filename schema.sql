@@ -29,6 +29,14 @@ CREATE TABLE IF NOT EXISTS users (
     password_hash VARCHAR(255) NOT NULL,
     role user_role NOT NULL DEFAULT 'User',
     phone VARCHAR(20),
+    email_verified_at TIMESTAMP WITH TIME ZONE,
+    failed_login_count INTEGER NOT NULL DEFAULT 0,
+    locked_until TIMESTAMP WITH TIME ZONE,
+    totp_secret VARCHAR(64),
+    totp_enabled_at TIMESTAMP WITH TIME ZONE,
+    totp_last_counter BIGINT NOT NULL DEFAULT 0,
+    locale VARCHAR(10),
+    time_zone VARCHAR(64),
     created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
 );
 
@@ -98,6 +106,190 @@ CREATE TABLE IF NOT EXISTS task_history (
     timestamp TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
 );
 
+-- Create ApiTokens table for non-browser clients; only the SHA-256 of the token is stored
+CREATE TABLE IF NOT EXISTS api_tokens (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    scopes TEXT[] NOT NULL DEFAULT '{read}',
+    expires_at TIMESTAMP WITH TIME ZONE,
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    revoked_at TIMESTAMP WITH TIME ZONE
+);
+
+-- Create CalendarFeeds table; the secret in the URL of a user's iCalendar feed is stored as its SHA-256
+CREATE TABLE IF NOT EXISTS calendar_feeds (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_used_at TIMESTAMP WITH TIME ZONE
+);
+
+-- Create Sessions table; the cookie only carries a random token whose SHA-256 is stored here
+CREATE TABLE IF NOT EXISTS sessions (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    user_agent TEXT,
+    ip_address VARCHAR(45),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_seen_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+-- Create UserTokens table for single-use email verification and password reset links
+CREATE TABLE IF NOT EXISTS user_tokens (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    purpose VARCHAR(32) NOT NULL CHECK (purpose IN ('email_verification', 'password_reset')),
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Create Settings table for application wide options managed by administrators
+CREATE TABLE IF NOT EXISTS settings (
+    key VARCHAR(100) PRIMARY KEY,
+    value TEXT NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Create Invitations table; registration links are bound to an email and role
+CREATE TABLE IF NOT EXISTS invitations (
+    id SERIAL PRIMARY KEY,
+    email VARCHAR(255) NOT NULL,
+    role user_role NOT NULL DEFAULT 'User',
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    invited_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    accepted_at TIMESTAMP WITH TIME ZONE,
+    accepted_user_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Create LoginAttempts table as an audit trail and to rate limit by IP address
+CREATE TABLE IF NOT EXISTS login_attempts (
+    id SERIAL PRIMARY KEY,
+    email VARCHAR(255) NOT NULL,
+    user_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    ip_address VARCHAR(45) NOT NULL,
+    succeeded BOOLEAN NOT NULL,
+    reason VARCHAR(50),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Create RecoveryCodes table; single use fallbacks for two-factor authentication
+CREATE TABLE IF NOT EXISTS recovery_codes (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash CHAR(64) NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Create UserIdentities table linking users to single sign-on accounts
+CREATE TABLE IF NOT EXISTS user_identities (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    provider VARCHAR(50) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    last_login_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (provider, subject)
+);
+
+-- Create Notifications table for the in-app inbox
+CREATE TABLE IF NOT EXISTS notifications (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    task_id INTEGER NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    actor_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    kind VARCHAR(50) NOT NULL,
+    detail TEXT NOT NULL DEFAULT '',
+    read_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Create TaskReminders table so each due soon and overdue event is raised once per due date
+CREATE TABLE IF NOT EXISTS task_reminders (
+    task_id INTEGER NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    kind VARCHAR(50) NOT NULL,
+    due_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (task_id, kind, due_at)
+);
+
+-- Create EmailPreferences table; users without a row get the defaults
+CREATE TABLE IF NOT EXISTS email_preferences (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    assigned VARCHAR(16) NOT NULL DEFAULT 'immediate' CHECK (assigned IN ('off', 'immediate', 'digest')),
+    mentioned VARCHAR(16) NOT NULL DEFAULT 'immediate' CHECK (mentioned IN ('off', 'immediate', 'digest')),
+    overdue VARCHAR(16) NOT NULL DEFAULT 'immediate' CHECK (overdue IN ('off', 'immediate', 'digest')),
+    daily_digest BOOLEAN NOT NULL DEFAULT TRUE,
+    digest_sent_at TIMESTAMP WITH TIME ZONE,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Create EmailDigestItems table for email notifications held back for the daily digest
+CREATE TABLE IF NOT EXISTS email_digest_items (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    task_id INTEGER NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    event VARCHAR(16) NOT NULL,
+    summary TEXT NOT NULL,
+    sent_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Create EmailOutbox table; emails are delivered by a background sender that retries failures
+CREATE TABLE IF NOT EXISTS email_outbox (
+    id SERIAL PRIMARY KEY,
+    recipient VARCHAR(255) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    text_body TEXT NOT NULL,
+    html_body TEXT NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_error TEXT,
+    sent_at TIMESTAMP WITH TIME ZONE,
+    failed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Create Webhooks table; admins subscribe URLs to task events
+CREATE TABLE IF NOT EXISTS webhooks (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    url TEXT NOT NULL,
+    secret VARCHAR(200) NOT NULL,
+    events TEXT[] NOT NULL,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Create WebhookDeliveries table; the delivery log, retried in the background like the email outbox
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id SERIAL PRIMARY KEY,
+    webhook_id INTEGER NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+    event VARCHAR(50) NOT NULL,
+    payload JSONB NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    response_status INTEGER,
+    response_body TEXT,
+    last_error TEXT,
+    delivered_at TIMESTAMP WITH TIME ZONE,
+    failed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
 -- Create indexes for performance optimization
 CREATE INDEX idx_tasks_status ON tasks(status);
 CREATE INDEX idx_tasks_priority ON tasks(priority);
@@ -109,6 +301,22 @@ CREATE INDEX idx_tasks_is_recurring ON tasks(is_recurring);
 CREATE INDEX idx_comments_task_id ON comments(task_id);
 CREATE INDEX idx_attachments_task_id ON attachments(task_id);
 CREATE INDEX idx_task_history_task_id ON task_history(task_id);
+CREATE INDEX idx_api_tokens_user_id ON api_tokens(user_id);
+CREATE INDEX idx_sessions_user_id ON sessions(user_id);
+CREATE INDEX idx_sessions_expires_at ON sessions(expires_at);
+CREATE INDEX idx_user_tokens_user_id ON user_tokens(user_id, purpose);
+CREATE INDEX idx_invitations_email ON invitations(LOWER(email));
+CREATE INDEX idx_login_attempts_ip_address ON login_attempts(ip_address, created_at);
+CREATE INDEX idx_login_attempts_created_at ON login_attempts(created_at);
+CREATE INDEX idx_recovery_codes_user_id ON recovery_codes(user_id);
+CREATE INDEX idx_user_identities_user_id ON user_identities(user_id);
+CREATE INDEX idx_notifications_user_id ON notifications(user_id, created_at);
+CREATE INDEX idx_notifications_unread ON notifications(user_id) WHERE read_at IS NULL;
+CREATE INDEX idx_tasks_estimated_completion_date ON tasks(estimated_completion_date);
+CREATE INDEX idx_email_digest_items_pending ON email_digest_items(user_id) WHERE sent_at IS NULL;
+CREATE INDEX idx_email_outbox_pending ON email_outbox(next_attempt_at) WHERE sent_at IS NULL AND failed_at IS NULL;
+CREATE INDEX idx_webhook_deliveries_webhook_id ON webhook_deliveries(webhook_id, created_at);
+CREATE INDEX idx_webhook_deliveries_pending ON webhook_deliveries(next_attempt_at) WHERE delivered_at IS NULL AND failed_at IS NULL;
 
 -- Create trigger to update updated_at timestamp on tasks
 CREATE OR REPLACE FUNCTION update_modified_column()
@@ -123,69 +331,85 @@ CREATE TRIGGER update_tasks_modtime
 BEFORE UPDATE ON tasks
 FOR EACH ROW EXECUTE FUNCTION update_modified_column();
 
--- Create trigger to log task changes to task_history
+-- Create trigger to log task changes to task_history. The app sets app.user_id
+-- in the transaction of an edit to the user making it.
 CREATE OR REPLACE FUNCTION log_task_changes()
 RETURNS TRIGGER AS $$
 DECLARE
-    changed_field TEXT;
-    old_value TEXT;
-    new_value TEXT;
+    actor INTEGER;
 BEGIN
     IF TG_OP = 'UPDATE' THEN
+        actor := COALESCE(NULLIF(current_setting('app.user_id', true), '')::INTEGER, NEW.assigned_to, NEW.created_by);
+
         -- Check each field for changes and log them
         IF OLD.title IS DISTINCT FROM NEW.title THEN
             INSERT INTO task_history (task_id, changed_by, changed_field, old_value, new_value)
-            VALUES (NEW.id, COALESCE(NEW.assigned_to, NEW.created_by), 'title', OLD.title, NEW.title);
+            VALUES (NEW.id, actor, 'title', OLD.title, NEW.title);
         END IF;
 
         IF OLD.description IS DISTINCT FROM NEW.description THEN
             INSERT INTO task_history (task_id, changed_by, changed_field, old_value, new_value)
-            VALUES (NEW.id, COALESCE(NEW.assigned_to, NEW.created_by), 'description', OLD.description, NEW.description);
+            VALUES (NEW.id, actor, 'description', OLD.description, NEW.description);
         END IF;
 
         IF OLD.category_id IS DISTINCT FROM NEW.category_id THEN
             INSERT INTO task_history (task_id, changed_by, changed_field, old_value, new_value)
-            VALUES (NEW.id, COALESCE(NEW.assigned_to, NEW.created_by), 'category_id', OLD.category_id::TEXT, NEW.category_id::TEXT);
+            VALUES (NEW.id, actor, 'category_id', OLD.category_id::TEXT, NEW.category_id::TEXT);
         END IF;
 
         IF OLD.location_id IS DISTINCT FROM NEW.location_id THEN
             INSERT INTO task_history (task_id, changed_by, changed_field, old_value, new_value)
-            VALUES (NEW.id, COALESCE(NEW.assigned_to, NEW.created_by), 'location_id', OLD.location_id::TEXT, NEW.location_id::TEXT);
+            VALUES (NEW.id, actor, 'location_id', OLD.location_id::TEXT, NEW.location_id::TEXT);
         END IF;
 
         IF OLD.priority IS DISTINCT FROM NEW.priority THEN
             INSERT INTO task_history (task_id, changed_by, changed_field, old_value, new_value)
-            VALUES (NEW.id, COALESCE(NEW.assigned_to, NEW.created_by), 'priority', OLD.priority::TEXT, NEW.priority::TEXT);
+            VALUES (NEW.id, actor, 'priority', OLD.priority::TEXT, NEW.priority::TEXT);
         END IF;
 
         IF OLD.status IS DISTINCT FROM NEW.status THEN
             INSERT INTO task_history (task_id, changed_by, changed_field, old_value, new_value)
-            VALUES (NEW.id, COALESCE(NEW.assigned_to, NEW.created_by), 'status', OLD.status::TEXT, NEW.status::TEXT);
+            VALUES (NEW.id, actor, 'status', OLD.status::TEXT, NEW.status::TEXT);
         END IF;
 
         IF OLD.assigned_to IS DISTINCT FROM NEW.assigned_to THEN
             INSERT INTO task_history (task_id, changed_by, changed_field, old_value, new_value)
-            VALUES (NEW.id, COALESCE(NEW.assigned_to, NEW.created_by), 'assigned_to', OLD.assigned_to::TEXT, NEW.assigned_to::TEXT);
+            VALUES (NEW.id, actor, 'assigned_to', OLD.assigned_to::TEXT, NEW.assigned_to::TEXT);
         END IF;
 
         IF OLD.estimated_completion_date IS DISTINCT FROM NEW.estimated_completion_date THEN
             INSERT INTO task_history (task_id, changed_by, changed_field, old_value, new_value)
-            VALUES (NEW.id, COALESCE(NEW.assigned_to, NEW.created_by), 'estimated_completion_date', OLD.estimated_completion_date::TEXT, NEW.estimated_completion_date::TEXT);
+            VALUES (NEW.id, actor, 'estimated_completion_date', OLD.estimated_completion_date::TEXT, NEW.estimated_completion_date::TEXT);
         END IF;
 
         IF OLD.cost IS DISTINCT FROM NEW.cost THEN
             INSERT INTO task_history (task_id, changed_by, changed_field, old_value, new_value)
-            VALUES (NEW.id, COALESCE(NEW.assigned_to, NEW.created_by), 'cost', OLD.cost::TEXT, NEW.cost::TEXT);
+            VALUES (NEW.id, actor, 'cost', OLD.cost::TEXT, NEW.cost::TEXT);
         END IF;
 
         IF OLD.is_recurring IS DISTINCT FROM NEW.is_recurring THEN
             INSERT INTO task_history (task_id, changed_by, changed_field, old_value, new_value)
-            VALUES (NEW.id, COALESCE(NEW.assigned_to, NEW.created_by), 'is_recurring', OLD.is_recurring::TEXT, NEW.is_recurring::TEXT);
+            VALUES (NEW.id, actor, 'is_recurring', OLD.is_recurring::TEXT, NEW.is_recurring::TEXT);
+        END IF;
+
+        IF OLD.recurrence_type IS DISTINCT FROM NEW.recurrence_type THEN
+            INSERT INTO task_history (task_id, changed_by, changed_field, old_value, new_value)
+            VALUES (NEW.id, actor, 'recurrence_type', OLD.recurrence_type::TEXT, NEW.recurrence_type::TEXT);
         END IF;
 
-        IF OLD.recurrence_pattern IS DISTINCT FROM NEW.recurrence_pattern THEN
+        IF OLD.recurrence_interval IS DISTINCT FROM NEW.recurrence_interval THEN
             INSERT INTO task_history (task_id, changed_by, changed_field, old_value, new_value)
-            VALUES (NEW.id, COALESCE(NEW.assigned_to, NEW.created_by), 'recurrence_pattern', OLD.recurrence_pattern::TEXT, NEW.recurrence_pattern::TEXT);
+            VALUES (NEW.id, actor, 'recurrence_interval', OLD.recurrence_interval::TEXT, NEW.recurrence_interval::TEXT);
+        END IF;
+
+        IF OLD.recurrence_unit IS DISTINCT FROM NEW.recurrence_unit THEN
+            INSERT INTO task_history (task_id, changed_by, changed_field, old_value, new_value)
+            VALUES (NEW.id, actor, 'recurrence_unit', OLD.recurrence_unit::TEXT, NEW.recurrence_unit::TEXT);
+        END IF;
+
+        IF OLD.parent_task_id IS DISTINCT FROM NEW.parent_task_id THEN
+            INSERT INTO task_history (task_id, changed_by, changed_field, old_value, new_value)
+            VALUES (NEW.id, actor, 'parent_task_id', OLD.parent_task_id::TEXT, NEW.parent_task_id::TEXT);
         END IF;
     END IF;
 
@@ -197,6 +421,27 @@ CREATE TRIGGER task_history_trigger
 AFTER UPDATE ON tasks
 FOR EACH ROW EXECUTE FUNCTION log_task_changes();
 
+-- Create trigger to announce task changes to the app instances streaming them to browsers
+CREATE OR REPLACE FUNCTION notify_task_change()
+RETURNS TRIGGER AS $$
+DECLARE
+    task_id INTEGER;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        task_id := OLD.id;
+    ELSE
+        task_id := NEW.id;
+    END IF;
+
+    PERFORM pg_notify('task_changes', json_build_object('op', lower(TG_OP), 'id', task_id)::text);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER task_change_notify_trigger
+AFTER INSERT OR UPDATE OR DELETE ON tasks
+FOR EACH ROW EXECUTE FUNCTION notify_task_change();
+
 -- Insert default categories
 INSERT INTO categories (name, description)
 VALUES
@@ -210,15 +455,38 @@ VALUES
 ('Furniture', 'Furniture repair and maintenance')
 ON CONFLICT DO NOTHING;
 
--- Insert a default admin user with password 'admin' (this is just for initial setup, should be changed)
--- Note: In production, use a proper password hashing mechanism
-INSERT INTO users (first_name, last_name, email, password_hash, role)
-VALUES ('Admin', 'Admin', 'admin@example.org', '', 'Administrator')
+-- Registration is by invitation until an administrator opens it
+INSERT INTO settings (key, value) VALUES ('registration_mode', 'invite-only')
+ON CONFLICT DO NOTHING;
+
+-- Administrators have to enroll in two-factor authentication
+INSERT INTO settings (key, value) VALUES ('require_admin_two_factor', 'true')
+ON CONFLICT DO NOTHING;
+
+-- Dates are shown and entered in UTC until an administrator picks a time zone
+INSERT INTO settings (key, value) VALUES ('time_zone', 'UTC')
+ON CONFLICT DO NOTHING;
+
+-- Insert a default admin user without a usable password; set one through /forgot-password
+INSERT INTO users (first_name, last_name, email, password_hash, role, email_verified_at)
+VALUES ('Admin', 'Admin', 'admin@example.org', '', 'Administrator', NOW())
 ON CONFLICT DO NOTHING;
 
+-- Time zone of the organization, in which recurrences keep their time of day
+CREATE OR REPLACE FUNCTION organization_time_zone()
+RETURNS TEXT AS $$
+    SELECT COALESCE(
+        (SELECT value FROM settings WHERE key = 'time_zone' AND value IN (SELECT name FROM pg_timezone_names)),
+        'UTC'
+    );
+$$ LANGUAGE sql STABLE;
+
 -- Function to calculate next occurrence
 CREATE OR REPLACE FUNCTION calculate_next_occurrence()
 RETURNS TRIGGER AS $$
+DECLARE
+    zone TEXT := organization_time_zone();
+    step INTERVAL;
 BEGIN
     -- Only calculate for recurring tasks
     IF NEW.is_recurring = TRUE THEN
@@ -227,49 +495,46 @@ BEGIN
             -- Calculate based on recurrence type
             CASE NEW.recurrence_type
                 WHEN 'Daily' THEN
-                    NEW.next_occurrence := COALESCE(NEW.next_occurrence, NOW()) +
-                        (NEW.recurrence_interval || ' days')::INTERVAL;
+                    step := (NEW.recurrence_interval || ' days')::INTERVAL;
 
                 WHEN 'Weekly' THEN
-                    NEW.next_occurrence := COALESCE(NEW.next_occurrence, NOW()) +
-                        (NEW.recurrence_interval * 7 || ' days')::INTERVAL;
+                    step := (NEW.recurrence_interval * 7 || ' days')::INTERVAL;
 
                 WHEN 'Monthly' THEN
-                    NEW.next_occurrence := COALESCE(NEW.next_occurrence, NOW()) +
-                        (NEW.recurrence_interval || ' months')::INTERVAL;
+                    step := (NEW.recurrence_interval || ' months')::INTERVAL;
 
                 WHEN 'Yearly' THEN
-                    NEW.next_occurrence := COALESCE(NEW.next_occurrence, NOW()) +
-                        (NEW.recurrence_interval || ' years')::INTERVAL;
+                    step := (NEW.recurrence_interval || ' years')::INTERVAL;
 
                 WHEN 'Custom' THEN
                     -- Handle custom recurrence using recurrence_unit
                     CASE NEW.recurrence_unit
                         WHEN 'Days' THEN
-                            NEW.next_occurrence := COALESCE(NEW.next_occurrence, NOW()) +
-                                (NEW.recurrence_interval || ' days')::INTERVAL;
+                            step := (NEW.recurrence_interval || ' days')::INTERVAL;
 
                         WHEN 'Weeks' THEN
-                            NEW.next_occurrence := COALESCE(NEW.next_occurrence, NOW()) +
-                                (NEW.recurrence_interval * 7 || ' days')::INTERVAL;
+                            step := (NEW.recurrence_interval * 7 || ' days')::INTERVAL;
 
                         WHEN 'Months' THEN
-                            NEW.next_occurrence := COALESCE(NEW.next_occurrence, NOW()) +
-                                (NEW.recurrence_interval || ' months')::INTERVAL;
+                            step := (NEW.recurrence_interval || ' months')::INTERVAL;
 
                         WHEN 'Years' THEN
-                            NEW.next_occurrence := COALESCE(NEW.next_occurrence, NOW()) +
-                                (NEW.recurrence_interval || ' years')::INTERVAL;
+                            step := (NEW.recurrence_interval || ' years')::INTERVAL;
 
                         ELSE
                             -- Default fallback
-                            NEW.next_occurrence := COALESCE(NEW.next_occurrence, NOW()) + '1 day'::INTERVAL;
+                            step := '1 day'::INTERVAL;
                     END CASE;
 
                 ELSE
                     -- Default fallback
-                    NEW.next_occurrence := COALESCE(NEW.next_occurrence, NOW()) + '1 day'::INTERVAL;
+                    step := '1 day'::INTERVAL;
             END CASE;
+
+            -- Step in local time, so occurrences keep their time of day across
+            -- daylight saving changes
+            NEW.next_occurrence :=
+                ((COALESCE(NEW.next_occurrence, NOW()) AT TIME ZONE zone) + step) AT TIME ZONE zone;
         END IF;
     END IF;
 
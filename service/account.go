@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mjmarrazzo/maintenance-app/domain"
+	"github.com/mjmarrazzo/maintenance-app/internal/hashing"
+	"github.com/mjmarrazzo/maintenance-app/internal/logging"
+	"github.com/mjmarrazzo/maintenance-app/internal/mail"
+	"github.com/mjmarrazzo/maintenance-app/internal/responses"
+	"github.com/mjmarrazzo/maintenance-app/repository"
+)
+
+// AccountService runs the flows driven by single-use links sent by email.
+type AccountService interface {
+	SendVerification(ctx context.Context, user *domain.User) error
+	// ResendVerification sends a new link when email belongs to an
+	// unverified account and silently does nothing otherwise.
+	ResendVerification(ctx context.Context, email string) error
+	VerifyEmail(ctx context.Context, token string) (*domain.User, error)
+	// RequestPasswordReset sends a reset link when email belongs to an
+	// account and silently does nothing otherwise, so the response does not
+	// reveal which addresses are registered.
+	RequestPasswordReset(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, req *domain.ResetPasswordRequest) error
+}
+
+type accountService struct {
+	users    repository.UserRepository
+	tokens   repository.UserTokenRepository
+	sessions repository.SessionRepository
+	mailer   mail.Sender
+	baseURL  string
+}
+
+func NewAccountService(pool *pgxpool.Pool, mailer mail.Sender, baseURL string) AccountService {
+	return &accountService{
+		users:    repository.NewUserRepository(pool),
+		tokens:   repository.NewUserTokenRepository(pool),
+		sessions: repository.NewSessionRepository(pool),
+		mailer:   mailer,
+		baseURL:  baseURL,
+	}
+}
+
+func (s *accountService) SendVerification(ctx context.Context, user *domain.User) error {
+	return s.sendLink(ctx, user, domain.PurposeEmailVerification, "/verify-email", "Verify your email address", "verify_email")
+}
+
+func (s *accountService) ResendVerification(ctx context.Context, email string) error {
+	user, err := s.lookup(ctx, email)
+	if err != nil || user == nil || user.IsEmailVerified() {
+		return err
+	}
+	return s.SendVerification(ctx, user)
+}
+
+func (s *accountService) VerifyEmail(ctx context.Context, token string) (*domain.User, error) {
+	userToken, err := s.consume(ctx, domain.PurposeEmailVerification, token)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.users.MarkEmailVerified(ctx, userToken.UserID); err != nil {
+		return nil, err
+	}
+
+	logging.FromContext(ctx).Info("email verified", "user_id", userToken.UserID)
+	return s.users.GetUserByID(ctx, userToken.UserID)
+}
+
+func (s *accountService) RequestPasswordReset(ctx context.Context, email string) error {
+	user, err := s.lookup(ctx, email)
+	if err != nil || user == nil {
+		return err
+	}
+	return s.sendLink(ctx, user, domain.PurposePasswordReset, "/reset-password", "Reset your password", "password_reset")
+}
+
+func (s *accountService) ResetPassword(ctx context.Context, req *domain.ResetPasswordRequest) error {
+	passwordHash, err := hashing.HashPassword(req.Password)
+	if err != nil {
+		return err
+	}
+
+	// The token is only used up once the new password is saved.
+	userToken, err := s.tokens.ResetPassword(ctx, hashing.HashToken(req.Token), passwordHash)
+	if appErr, ok := responses.IsAppError(err); ok && appErr.Kind == responses.KindNotFound {
+		return invalidLinkError()
+	}
+	if err != nil {
+		return err
+	}
+
+	// The reset link proves control of the mailbox, so the address counts
+	// as verified from here on.
+	if err := s.users.MarkEmailVerified(ctx, userToken.UserID); err != nil {
+		return err
+	}
+
+	if err := s.tokens.InvalidateForUser(ctx, userToken.UserID, domain.PurposePasswordReset); err != nil {
+		return err
+	}
+
+	// Whoever knew the old password should not stay signed in.
+	if err := s.sessions.DeleteByUserID(ctx, userToken.UserID); err != nil {
+		return err
+	}
+
+	logging.FromContext(ctx).Info("password reset", "user_id", userToken.UserID)
+	return nil
+}
+
+// lookup returns nil without an error for unknown addresses.
+func (s *accountService) lookup(ctx context.Context, email string) (*domain.User, error) {
+	user, err := s.users.GetUserByEmail(ctx, email)
+	if appErr, ok := responses.IsAppError(err); ok && appErr.Kind == responses.KindNotFound {
+		logging.FromContext(ctx).Info("account link requested for unknown email")
+		return nil, nil
+	}
+	return user, err
+}
+
+func (s *accountService) consume(ctx context.Context, purpose domain.TokenPurpose, token string) (*domain.UserToken, error) {
+	userToken, err := s.tokens.Consume(ctx, purpose, hashing.HashToken(token))
+	if appErr, ok := responses.IsAppError(err); ok && appErr.Kind == responses.KindNotFound {
+		return nil, invalidLinkError()
+	}
+	return userToken, err
+}
+
+func invalidLinkError() error {
+	return responses.NewValidationError("This link is invalid or has expired", []string{"token"}, nil)
+}
+
+func (s *accountService) sendLink(ctx context.Context, user *domain.User, purpose domain.TokenPurpose, path, subject, template string) error {
+	token, err := hashing.GenerateSecret()
+	if err != nil {
+		return err
+	}
+
+	ttl := purpose.TTL()
+	userToken := &domain.UserToken{
+		UserID:    user.ID,
+		Purpose:   purpose,
+		TokenHash: hashing.HashToken(token),
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	if err := s.tokens.Create(ctx, userToken); err != nil {
+		return err
+	}
+
+	link := s.baseURL + path + "?" + url.Values{"token": {token}}.Encode()
+	msg, err := mail.Render(user.Email, subject, template, map[string]string{
+		"FirstName": user.FirstName,
+		"URL":       link,
+		"ExpiresIn": formatTTL(ttl),
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		return err
+	}
+
+	logging.FromContext(ctx).Info("account link sent", "user_id", user.ID, "purpose", purpose)
+	return nil
+}
+
+func formatTTL(ttl time.Duration) string {
+	hours := int(ttl.Hours())
+	if hours == 1 {
+		return "1 hour"
+	}
+	return strconv.Itoa(hours) + " hours"
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mjmarrazzo/maintenance-app/domain"
+	"github.com/mjmarrazzo/maintenance-app/internal/hashing"
+	"github.com/mjmarrazzo/maintenance-app/internal/logging"
+	"github.com/mjmarrazzo/maintenance-app/internal/responses"
+	"github.com/mjmarrazzo/maintenance-app/repository"
+)
+
+// lastUsedResolution limits how often a busy token writes its last-used
+// timestamp.
+const lastUsedResolution = time.Minute
+
+type APITokenService interface {
+	// Create stores a new token and returns it together with the plaintext
+	// value, which is not retrievable afterwards.
+	Create(ctx context.Context, userID int64, tr *domain.APITokenRequest) (*domain.APIToken, string, error)
+	GetByUserID(ctx context.Context, userID int64) ([]*domain.APIToken, error)
+	Revoke(ctx context.Context, userID, id int64) error
+	Authenticate(ctx context.Context, token string) (*domain.User, *domain.APIToken, error)
+}
+
+type apiTokenService struct {
+	repository     repository.APITokenRepository
+	userRepository repository.UserRepository
+}
+
+func NewAPITokenService(pool *pgxpool.Pool) APITokenService {
+	return &apiTokenService{
+		repository:     repository.NewAPITokenRepository(pool),
+		userRepository: repository.NewUserRepository(pool),
+	}
+}
+
+func (s *apiTokenService) Create(ctx context.Context, userID int64, tr *domain.APITokenRequest) (*domain.APIToken, string, error) {
+	plaintext, err := hashing.GenerateToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	token := tr.ToDomain(time.Now())
+	token.UserID = userID
+	token.TokenHash = hashing.HashToken(plaintext)
+
+	if err := s.repository.Create(ctx, token); err != nil {
+		return nil, "", err
+	}
+
+	logging.FromContext(ctx).Info("API token created", "token_id", token.ID, "scopes", token.Scopes)
+	return token, plaintext, nil
+}
+
+func (s *apiTokenService) GetByUserID(ctx context.Context, userID int64) ([]*domain.APIToken, error) {
+	return s.repository.GetByUserID(ctx, userID)
+}
+
+func (s *apiTokenService) Revoke(ctx context.Context, userID, id int64) error {
+	if err := s.repository.Revoke(ctx, userID, id); err != nil {
+		return err
+	}
+
+	logging.FromContext(ctx).Info("API token revoked", "token_id", id)
+	return nil
+}
+
+func (s *apiTokenService) Authenticate(ctx context.Context, plaintext string) (*domain.User, *domain.APIToken, error) {
+	invalid := responses.NewUnauthorizedError("Invalid or expired token")
+	if !strings.HasPrefix(plaintext, hashing.TokenPrefix) {
+		return nil, nil, invalid
+	}
+
+	token, err := s.repository.GetByHash(ctx, hashing.HashToken(plaintext))
+	if err != nil {
+		if appErr, ok := responses.IsAppError(err); ok && appErr.Kind == responses.KindNotFound {
+			return nil, nil, invalid
+		}
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	if !token.IsActive(now) {
+		return nil, nil, invalid
+	}
+
+	user, err := s.userRepository.GetUserByID(ctx, token.UserID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !token.LastUsedAt.Valid || now.Sub(token.LastUsedAt.Time) >= lastUsedResolution {
+		if err := s.repository.TouchLastUsed(ctx, token.ID, now); err != nil {
+			logging.FromContext(ctx).Warn("failed to record API token use", "token_id", token.ID, "error", err)
+		}
+	}
+
+	return user, token, nil
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mjmarrazzo/maintenance-app/domain"
+	"github.com/mjmarrazzo/maintenance-app/internal/logging"
+	"github.com/mjmarrazzo/maintenance-app/internal/responses"
+	"github.com/mjmarrazzo/maintenance-app/internal/storage"
+	"github.com/mjmarrazzo/maintenance-app/repository"
+)
+
+type AttachmentService interface {
+	Upload(ctx context.Context, userID, taskID int64, filename, mimeType string, content io.Reader) (*domain.Attachment, error)
+	GetByTaskID(ctx context.Context, taskID int64) ([]*domain.Attachment, error)
+	GetByID(ctx context.Context, id int64) (*domain.Attachment, error)
+	Open(ctx context.Context, id int64) (*domain.Attachment, io.ReadCloser, error)
+	Delete(ctx context.Context, actor *domain.User, id int64) error
+}
+
+type attachmentService struct {
+	repository     repository.AttachmentRepository
+	taskRepository repository.TaskRepository
+	store          storage.Store
+}
+
+func NewAttachmentService(pool *pgxpool.Pool, store storage.Store) AttachmentService {
+	return &attachmentService{
+		repository:     repository.NewAttachmentRepository(pool),
+		taskRepository: repository.NewTaskRepository(pool),
+		store:          store,
+	}
+}
+
+func (s *attachmentService) Upload(ctx context.Context, userID, taskID int64, filename, mimeType string, content io.Reader) (*domain.Attachment, error) {
+	if _, err := s.taskRepository.GetByID(ctx, taskID); err != nil {
+		return nil, err
+	}
+
+	key, err := storage.NewObjectKey()
+	if err != nil {
+		return nil, err
+	}
+
+	size, err := s.store.Put(ctx, key, content)
+	if err != nil {
+		return nil, err
+	}
+
+	attachment := &domain.Attachment{
+		TaskID:     taskID,
+		Filename:   filename,
+		ObjectKey:  key,
+		MimeType:   mimeType,
+		SizeBytes:  size,
+		UploadedBy: userID,
+	}
+
+	if err := s.repository.Create(ctx, attachment); err != nil {
+		if deleteErr := s.store.Delete(ctx, key); deleteErr != nil {
+			logging.FromContext(ctx).Error("failed to remove orphaned attachment", "object_key", key, "error", deleteErr)
+		}
+		return nil, err
+	}
+
+	logging.FromContext(ctx).Info("attachment uploaded", "attachment_id", attachment.ID, "task_id", taskID, "size_bytes", size)
+	return attachment, nil
+}
+
+func (s *attachmentService) GetByTaskID(ctx context.Context, taskID int64) ([]*domain.Attachment, error) {
+	if _, err := s.taskRepository.GetByID(ctx, taskID); err != nil {
+		return nil, err
+	}
+	return s.repository.GetByTaskID(ctx, taskID)
+}
+
+func (s *attachmentService) GetByID(ctx context.Context, id int64) (*domain.Attachment, error) {
+	return s.repository.GetByID(ctx, id)
+}
+
+func (s *attachmentService) Open(ctx context.Context, id int64) (*domain.Attachment, io.ReadCloser, error) {
+	attachment, err := s.repository.GetByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	content, err := s.store.Open(ctx, attachment.ObjectKey)
+	if errors.Is(err, storage.ErrObjectNotFound) {
+		return nil, nil, responses.NewNotFoundError(fmt.Sprintf("content for attachment with ID %d not found", id))
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return attachment, content, nil
+}
+
+func (s *attachmentService) Delete(ctx context.Context, actor *domain.User, id int64) error {
+	attachment, err := s.repository.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if attachment.UploadedBy != actor.ID && !actor.IsAdmin() {
+		return responses.NewForbiddenError("Only the uploader or an administrator can delete this attachment")
+	}
+
+	if err := s.repository.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	if err := s.store.Delete(ctx, attachment.ObjectKey); err != nil {
+		logging.FromContext(ctx).Error("failed to remove attachment content", "object_key", attachment.ObjectKey, "error", err)
+	}
+
+	logging.FromContext(ctx).Info("attachment deleted", "attachment_id", id)
+	return nil
+}
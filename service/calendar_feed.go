@@ -0,0 +1,204 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mjmarrazzo/maintenance-app/domain"
+	"github.com/mjmarrazzo/maintenance-app/internal/hashing"
+	"github.com/mjmarrazzo/maintenance-app/internal/ical"
+	"github.com/mjmarrazzo/maintenance-app/internal/logging"
+	"github.com/mjmarrazzo/maintenance-app/internal/responses"
+	"github.com/mjmarrazzo/maintenance-app/repository"
+)
+
+type CalendarFeedService interface {
+	// Get returns the feed of the user, or nil if they have none.
+	Get(ctx context.Context, userID int64) (*domain.CalendarFeed, error)
+	// Regenerate gives the user a feed with a new secret, which is returned
+	// and not retrievable afterwards. The previous URL stops working.
+	Regenerate(ctx context.Context, userID int64) (string, error)
+	Delete(ctx context.Context, userID int64) error
+	// Render returns the iCalendar feed whose URL carries token.
+	Render(ctx context.Context, token string, now time.Time) ([]byte, error)
+}
+
+type calendarFeedService struct {
+	repository repository.CalendarFeedRepository
+	users      repository.UserRepository
+	tasks      repository.TaskRepository
+	settings   repository.SettingRepository
+	baseURL    string
+}
+
+func NewCalendarFeedService(pool *pgxpool.Pool, baseURL string) CalendarFeedService {
+	return &calendarFeedService{
+		repository: repository.NewCalendarFeedRepository(pool),
+		users:      repository.NewUserRepository(pool),
+		tasks:      repository.NewTaskRepository(pool),
+		settings:   repository.NewSettingRepository(pool),
+		baseURL:    baseURL,
+	}
+}
+
+func (s *calendarFeedService) Get(ctx context.Context, userID int64) (*domain.CalendarFeed, error) {
+	feed, err := s.repository.GetByUserID(ctx, userID)
+	if appErr, ok := responses.IsAppError(err); ok && appErr.Kind == responses.KindNotFound {
+		return nil, nil
+	}
+	return feed, err
+}
+
+func (s *calendarFeedService) Regenerate(ctx context.Context, userID int64) (string, error) {
+	secret, err := hashing.GenerateSecret()
+	if err != nil {
+		return "", err
+	}
+
+	feed := &domain.CalendarFeed{UserID: userID, TokenHash: hashing.HashToken(secret)}
+	if err := s.repository.Replace(ctx, feed); err != nil {
+		return "", err
+	}
+
+	logging.FromContext(ctx).Info("calendar feed regenerated", "user_id", userID)
+	return secret, nil
+}
+
+func (s *calendarFeedService) Delete(ctx context.Context, userID int64) error {
+	if err := s.repository.Delete(ctx, userID); err != nil {
+		return err
+	}
+
+	logging.FromContext(ctx).Info("calendar feed deleted", "user_id", userID)
+	return nil
+}
+
+// Render lists the open tasks assigned to the owner of the feed. Each task
+// is a to-do and its due date an all-day event in the time zone of the
+// user, followed by the occurrences of recurring tasks until
+// domain.CalendarFeedHorizon. UIDs are derived from the task, and the date
+// for occurrences, so calendar apps update entries instead of adding them
+// again.
+func (s *calendarFeedService) Render(ctx context.Context, token string, now time.Time) ([]byte, error) {
+	feed, err := s.repository.GetByHash(ctx, hashing.HashToken(token))
+	if appErr, ok := responses.IsAppError(err); ok && appErr.Kind == responses.KindNotFound {
+		return nil, responses.NewNotFoundError("Calendar feed not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !feed.LastUsedAt.Valid || now.Sub(feed.LastUsedAt.Time) >= lastUsedResolution {
+		if err := s.repository.TouchLastUsed(ctx, feed.UserID, now); err != nil {
+			logging.FromContext(ctx).Warn("failed to record calendar feed use", "user_id", feed.UserID, "error", err)
+		}
+	}
+
+	user, err := s.users.GetUserByID(ctx, feed.UserID)
+	if err != nil {
+		return nil, err
+	}
+	zone, err := userTimeZone(ctx, s.settings, user)
+	if err != nil {
+		return nil, err
+	}
+
+	isCompleted := false
+	tasks, err := s.tasks.GetAll(ctx, repository.TaskFilters{
+		AssignedTo:  &user.ID,
+		IsCompleted: &isCompleted,
+		SortField:   "estimated_completion_date",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	calendar := &ical.Calendar{
+		ProductID: "-//Groundwork//Tasks//EN",
+		Name:      "Groundwork",
+	}
+	year, month, day := now.In(zone).Date()
+	today := time.Date(year, month, day, 0, 0, 0, 0, zone)
+	until := now.Add(domain.CalendarFeedHorizon)
+	for _, task := range tasks {
+		calendar.Components = append(calendar.Components, s.todo(task, zone, now))
+		if task.EstimatedCompletionDate.Valid {
+			calendar.Components = append(calendar.Components, s.event(task, task.EstimatedCompletionDate.Time, zone, now, false))
+		}
+		for _, occurrence := range task.Occurrences(today, until, zone) {
+			if occurrence.Projected {
+				calendar.Components = append(calendar.Components, s.event(task, occurrence.Due, zone, now, true))
+			}
+		}
+	}
+
+	logging.FromContext(ctx).Info("calendar feed rendered", "user_id", user.ID, "tasks", len(tasks))
+	return calendar.Marshal(), nil
+}
+
+func (s *calendarFeedService) todo(task *domain.Task, zone *time.Location, now time.Time) ical.Todo {
+	todo := ical.Todo{
+		UID:          s.uid(task, "todo"),
+		Stamp:        now,
+		AllDay:       true,
+		Summary:      task.Title,
+		Description:  task.Description,
+		URL:          s.baseURL + "/tasks",
+		Status:       ical.StatusNeedsAction,
+		Priority:     todoPriorities[domain.Priority(task.Priority.String)],
+		LastModified: task.UpdatedAt,
+	}
+	if task.EstimatedCompletionDate.Valid {
+		todo.Due = task.EstimatedCompletionDate.Time.In(zone)
+	}
+	if task.Status.String == string(domain.StatusInProgress) {
+		todo.Status = ical.StatusInProcess
+	}
+	return todo
+}
+
+// todoPriorities maps priorities to the 1 (highest) to 9 scale of RFC 5545.
+var todoPriorities = map[domain.Priority]int{
+	domain.PriorityUrgent: 1,
+	domain.PriorityHigh:   3,
+	domain.PriorityMedium: 5,
+	domain.PriorityLow:    9,
+}
+
+// event is the all-day event of the day task is due. Projected occurrences
+// get a UID of their own per date, the due date itself keeps the one of
+// the task when it is moved.
+func (s *calendarFeedService) event(task *domain.Task, due time.Time, zone *time.Location, now time.Time, projected bool) ical.Event {
+	day := due.In(zone)
+	uid := s.uid(task, "")
+	if projected {
+		uid = s.uid(task, day.Format("20060102"))
+	}
+	return ical.Event{
+		UID:          uid,
+		Stamp:        now,
+		Start:        day,
+		End:          day.AddDate(0, 0, 1),
+		AllDay:       true,
+		Summary:      task.Title,
+		Description:  task.Description,
+		URL:          s.baseURL + "/tasks",
+		Status:       ical.StatusConfirmed,
+		LastModified: task.UpdatedAt,
+	}
+}
+
+// uid identifies an entry of task within the host of the application.
+func (s *calendarFeedService) uid(task *domain.Task, suffix string) string {
+	host := s.baseURL
+	if u, err := url.Parse(s.baseURL); err == nil && u.Host != "" {
+		host = u.Hostname()
+	}
+	if suffix == "" {
+		return fmt.Sprintf("task-%d@%s", task.ID, host)
+	}
+	return fmt.Sprintf("task-%d-%s@%s", task.ID, suffix, host)
+}
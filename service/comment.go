@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mjmarrazzo/maintenance-app/domain"
+	"github.com/mjmarrazzo/maintenance-app/internal/events"
+	"github.com/mjmarrazzo/maintenance-app/internal/logging"
+	"github.com/mjmarrazzo/maintenance-app/internal/responses"
+	"github.com/mjmarrazzo/maintenance-app/repository"
+)
+
+type CommentService interface {
+	Create(ctx context.Context, userID, taskID int64, comment *domain.CommentRequest) (*domain.Comment, error)
+	GetByTaskID(ctx context.Context, taskID int64) ([]*domain.Comment, error)
+	Delete(ctx context.Context, actor *domain.User, id int64) error
+}
+
+type commentService struct {
+	repository     repository.CommentRepository
+	taskRepository repository.TaskRepository
+	bus            *events.Bus
+}
+
+func NewCommentService(pool *pgxpool.Pool) CommentService {
+	return &commentService{
+		repository:     repository.NewCommentRepository(pool),
+		taskRepository: repository.NewTaskRepository(pool),
+		bus:            events.Default(),
+	}
+}
+
+func (s *commentService) Create(ctx context.Context, userID, taskID int64, cr *domain.CommentRequest) (*domain.Comment, error) {
+	task, err := s.taskRepository.GetByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	comment := cr.ToDomain()
+	comment.TaskID = taskID
+	comment.UserID = userID
+
+	if err := s.repository.Create(ctx, comment); err != nil {
+		return nil, err
+	}
+
+	logging.FromContext(ctx).Info("comment created", "comment_id", comment.ID, "task_id", taskID)
+	s.bus.Publish(ctx, domain.TaskEvent{
+		Kind:       domain.TaskEventCommented,
+		Task:       task,
+		ActorID:    userID,
+		Comment:    comment,
+		OccurredAt: time.Now(),
+	})
+	return comment, nil
+}
+
+func (s *commentService) GetByTaskID(ctx context.Context, taskID int64) ([]*domain.Comment, error) {
+	if _, err := s.taskRepository.GetByID(ctx, taskID); err != nil {
+		return nil, err
+	}
+	return s.repository.GetByTaskID(ctx, taskID)
+}
+
+func (s *commentService) Delete(ctx context.Context, actor *domain.User, id int64) error {
+	comment, err := s.repository.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if comment.UserID != actor.ID && !actor.IsAdmin() {
+		return responses.NewForbiddenError("Only the author or an administrator can delete this comment")
+	}
+
+	if err := s.repository.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	logging.FromContext(ctx).Info("comment deleted", "comment_id", id)
+	return nil
+}
@@ -0,0 +1,308 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mjmarrazzo/maintenance-app/domain"
+	"github.com/mjmarrazzo/maintenance-app/internal/logging"
+	"github.com/mjmarrazzo/maintenance-app/internal/mail"
+	"github.com/mjmarrazzo/maintenance-app/internal/responses"
+	"github.com/mjmarrazzo/maintenance-app/repository"
+)
+
+type EmailNotificationService interface {
+	HandleTaskEvent(ctx context.Context, event domain.TaskEvent)
+	SendDigests(ctx context.Context, now time.Time) error
+	GetPreferences(ctx context.Context, userID int64) (*domain.EmailPreferences, error)
+	UpdatePreferences(ctx context.Context, userID int64, request *domain.EmailPreferencesRequest) error
+}
+
+type emailNotificationService struct {
+	preferences repository.EmailPreferenceRepository
+	digestItems repository.EmailDigestItemRepository
+	outbox      repository.EmailOutboxRepository
+	users       repository.UserRepository
+	tasks       repository.TaskRepository
+	settings    repository.SettingRepository
+	baseURL     string
+}
+
+func NewEmailNotificationService(pool *pgxpool.Pool, baseURL string) EmailNotificationService {
+	return &emailNotificationService{
+		preferences: repository.NewEmailPreferenceRepository(pool),
+		digestItems: repository.NewEmailDigestItemRepository(pool),
+		outbox:      repository.NewEmailOutboxRepository(pool),
+		users:       repository.NewUserRepository(pool),
+		tasks:       repository.NewTaskRepository(pool),
+		settings:    repository.NewSettingRepository(pool),
+		baseURL:     baseURL,
+	}
+}
+
+func (s *emailNotificationService) GetPreferences(ctx context.Context, userID int64) (*domain.EmailPreferences, error) {
+	preferences, err := s.preferences.GetByUserID(ctx, userID)
+	if appErr, ok := responses.IsAppError(err); ok && appErr.Kind == responses.KindNotFound {
+		return domain.DefaultEmailPreferences(userID), nil
+	}
+	return preferences, err
+}
+
+func (s *emailNotificationService) UpdatePreferences(ctx context.Context, userID int64, request *domain.EmailPreferencesRequest) error {
+	preferences := request.ToDomain()
+	preferences.UserID = userID
+
+	if err := s.preferences.Upsert(ctx, preferences); err != nil {
+		return err
+	}
+
+	logging.FromContext(ctx).Info("email preferences updated", "user_id", userID)
+	return nil
+}
+
+// HandleTaskEvent emails assignees, mentioned users and the owners of
+// overdue tasks, or keeps the email for their digest. It is subscribed to
+// events.Default at startup.
+func (s *emailNotificationService) HandleTaskEvent(ctx context.Context, event domain.TaskEvent) {
+	logger := logging.FromContext(ctx)
+
+	var err error
+	switch event.Kind {
+	case domain.TaskEventAssigned:
+		if event.Task.AssignedTo.Int64 != event.ActorID {
+			err = s.notify(ctx, event.Task.AssignedTo.Int64, domain.EmailEventAssigned, event)
+		}
+	case domain.TaskEventOverdue:
+		recipient := event.Task.CreatedBy
+		if event.Task.AssignedTo.Valid {
+			recipient = event.Task.AssignedTo.Int64
+		}
+		err = s.notify(ctx, recipient, domain.EmailEventOverdue, event)
+	case domain.TaskEventCommented:
+		for _, email := range event.Comment.Mentions() {
+			user, lookupErr := s.users.GetUserByEmail(ctx, email)
+			if appErr, ok := responses.IsAppError(lookupErr); ok && appErr.Kind == responses.KindNotFound {
+				continue
+			}
+			if lookupErr != nil {
+				err = lookupErr
+				break
+			}
+			if user.ID == event.ActorID {
+				continue
+			}
+			if err = s.notify(ctx, user.ID, domain.EmailEventMentioned, event); err != nil {
+				break
+			}
+		}
+	}
+
+	if err != nil {
+		logger.Error("failed to queue email notification", "kind", event.Kind, "task_id", event.Task.ID, "error", err)
+	}
+}
+
+func (s *emailNotificationService) notify(ctx context.Context, userID int64, emailEvent domain.EmailEvent, event domain.TaskEvent) error {
+	preferences, err := s.GetPreferences(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	delivery := preferences.DeliveryFor(emailEvent)
+	if delivery == domain.EmailDeliveryOff {
+		return nil
+	}
+
+	user, err := s.users.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	actor := "Someone"
+	if event.ActorID != 0 {
+		if actorUser, err := s.users.GetUserByID(ctx, event.ActorID); err == nil {
+			actor = actorUser.FirstName + " " + actorUser.LastName
+		}
+	}
+
+	if delivery == domain.EmailDeliveryDigest {
+		return s.digestItems.Create(ctx, &domain.EmailDigestItem{
+			UserID:  userID,
+			TaskID:  event.Task.ID,
+			Event:   emailEvent,
+			Summary: emailSummary(emailEvent, actor, event.Task),
+		})
+	}
+
+	data := map[string]string{
+		"FirstName":      user.FirstName,
+		"Actor":          actor,
+		"TaskTitle":      event.Task.Title,
+		"URL":            s.baseURL + "/tasks",
+		"PreferencesURL": s.baseURL + "/settings/notifications",
+	}
+
+	var subject, template string
+	switch emailEvent {
+	case domain.EmailEventAssigned:
+		subject, template = "You were assigned to "+event.Task.Title, "task_assigned"
+	case domain.EmailEventMentioned:
+		subject, template = actor+" mentioned you on "+event.Task.Title, "task_mentioned"
+		data["Excerpt"] = notificationDetail(event)
+	case domain.EmailEventOverdue:
+		zone, err := userTimeZone(ctx, s.settings, user)
+		if err != nil {
+			return err
+		}
+		subject, template = event.Task.Title+" is overdue", "task_overdue"
+		data["DueDate"] = event.Task.EstimatedCompletionDate.Time.In(zone).Format("Jan 2, 2006")
+	}
+
+	msg, err := mail.Render(user.Email, subject, template, data)
+	if err != nil {
+		return err
+	}
+	return s.enqueue(ctx, msg)
+}
+
+func (s *emailNotificationService) enqueue(ctx context.Context, msg mail.Message) error {
+	return s.outbox.Enqueue(ctx, &domain.OutboxEmail{
+		Recipient: msg.To,
+		Subject:   msg.Subject,
+		TextBody:  msg.Text,
+		HTMLBody:  msg.HTML,
+	})
+}
+
+func emailSummary(emailEvent domain.EmailEvent, actor string, task *domain.Task) string {
+	switch emailEvent {
+	case domain.EmailEventAssigned:
+		return fmt.Sprintf("%s assigned you to “%s”", actor, task.Title)
+	case domain.EmailEventMentioned:
+		return fmt.Sprintf("%s mentioned you on “%s”", actor, task.Title)
+	case domain.EmailEventOverdue:
+		return fmt.Sprintf("“%s” is overdue", task.Title)
+	default:
+		return task.Title
+	}
+}
+
+type digestTask struct {
+	Title   string
+	Due     string
+	Overdue bool
+}
+
+type digestData struct {
+	FirstName      string
+	Items          []string
+	Tasks          []digestTask
+	URL            string
+	PreferencesURL string
+}
+
+// SendDigests queues the daily digest for every user who has not had one
+// today, once it is past domain.DigestHour in the time zone of the
+// organization. Users with nothing to report get no email.
+func (s *emailNotificationService) SendDigests(ctx context.Context, now time.Time) error {
+	zone, err := timeZone(ctx, s.settings)
+	if err != nil {
+		return err
+	}
+	now = now.In(zone)
+	if now.Hour() < domain.DigestHour {
+		return nil
+	}
+
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	userIDs, err := s.preferences.GetDigestRecipients(ctx, startOfDay)
+	if err != nil {
+		return err
+	}
+
+	// One user's digest failing must not hold up the others. It is tried
+	// again on the next run.
+	var errs []error
+	for _, userID := range userIDs {
+		if err := s.sendDigest(ctx, userID, now); err != nil {
+			logging.FromContext(ctx).Error("failed to send email digest", "user_id", userID, "error", err)
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *emailNotificationService) sendDigest(ctx context.Context, userID int64, now time.Time) error {
+	user, err := s.users.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	preferences, err := s.GetPreferences(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	items, err := s.digestItems.GetPending(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	data := digestData{
+		FirstName:      user.FirstName,
+		URL:            s.baseURL + "/tasks",
+		PreferencesURL: s.baseURL + "/settings/notifications",
+	}
+	itemIDs := make([]int64, 0, len(items))
+	for _, item := range items {
+		data.Items = append(data.Items, item.Summary)
+		itemIDs = append(itemIDs, item.ID)
+	}
+
+	if preferences.DailyDigest {
+		isCompleted := false
+		dueTo := now.Add(domain.DigestHorizon)
+		tasks, err := s.tasks.GetAll(ctx, repository.TaskFilters{
+			AssignedTo:  &userID,
+			IsCompleted: &isCompleted,
+			DueTo:       &dueTo,
+			SortField:   "estimated_completion_date",
+		})
+		if err != nil {
+			return err
+		}
+		zone, err := userTimeZone(ctx, s.settings, user)
+		if err != nil {
+			return err
+		}
+		for _, task := range tasks {
+			due := task.EstimatedCompletionDate.Time.In(zone)
+			data.Tasks = append(data.Tasks, digestTask{
+				Title:   task.Title,
+				Due:     due.Format("Mon, Jan 2"),
+				Overdue: due.Before(now),
+			})
+		}
+	}
+
+	if len(data.Items) > 0 || len(data.Tasks) > 0 {
+		msg, err := mail.Render(user.Email, "Your Groundwork digest", "digest", data)
+		if err != nil {
+			return err
+		}
+		if err := s.enqueue(ctx, msg); err != nil {
+			return err
+		}
+		if len(itemIDs) > 0 {
+			if err := s.digestItems.MarkSent(ctx, itemIDs); err != nil {
+				return err
+			}
+		}
+		logging.FromContext(ctx).Info("email digest queued", "user_id", userID, "items", len(itemIDs), "tasks", len(data.Tasks))
+	}
+
+	return s.preferences.MarkDigestSent(ctx, userID, now)
+}
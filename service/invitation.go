@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mjmarrazzo/maintenance-app/domain"
+	"github.com/mjmarrazzo/maintenance-app/internal/hashing"
+	"github.com/mjmarrazzo/maintenance-app/internal/logging"
+	"github.com/mjmarrazzo/maintenance-app/internal/mail"
+	"github.com/mjmarrazzo/maintenance-app/internal/responses"
+	"github.com/mjmarrazzo/maintenance-app/repository"
+)
+
+type InvitationService interface {
+	Create(ctx context.Context, inviter *domain.User, ir *domain.InvitationRequest) (*domain.Invitation, error)
+	GetAll(ctx context.Context) ([]*domain.Invitation, error)
+	// GetPending returns the invitation behind token if it can still be used.
+	GetPending(ctx context.Context, token string) (*domain.Invitation, error)
+	Revoke(ctx context.Context, id int64) error
+}
+
+type invitationService struct {
+	repository     repository.InvitationRepository
+	userRepository repository.UserRepository
+	mailer         mail.Sender
+	baseURL        string
+}
+
+func NewInvitationService(pool *pgxpool.Pool, mailer mail.Sender, baseURL string) InvitationService {
+	return &invitationService{
+		repository:     repository.NewInvitationRepository(pool),
+		userRepository: repository.NewUserRepository(pool),
+		mailer:         mailer,
+		baseURL:        baseURL,
+	}
+}
+
+func (s *invitationService) Create(ctx context.Context, inviter *domain.User, ir *domain.InvitationRequest) (*domain.Invitation, error) {
+	invitation := ir.ToDomain()
+
+	_, err := s.userRepository.GetUserByEmail(ctx, invitation.Email)
+	if err == nil {
+		return nil, responses.NewConflictError("A user with this email already exists")
+	}
+	if appErr, ok := responses.IsAppError(err); !ok || appErr.Kind != responses.KindNotFound {
+		return nil, err
+	}
+
+	token, err := hashing.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	invitation.TokenHash = hashing.HashToken(token)
+	invitation.InvitedBy.Int64 = inviter.ID
+	invitation.InvitedBy.Valid = true
+	invitation.ExpiresAt = time.Now().Add(domain.InvitationTTL)
+
+	if err := s.repository.Create(ctx, invitation); err != nil {
+		return nil, err
+	}
+
+	link := s.baseURL + "/register?" + url.Values{"token": {token}}.Encode()
+	msg, err := mail.Render(invitation.Email, "You're invited to Groundwork", "invitation", map[string]string{
+		"InviterName": inviter.FirstName + " " + inviter.LastName,
+		"Role":        string(invitation.Role),
+		"URL":         link,
+		"ExpiresIn":   "7 days",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		return nil, err
+	}
+
+	logging.FromContext(ctx).Info("invitation sent", "invitation_id", invitation.ID, "role", invitation.Role)
+	return invitation, nil
+}
+
+func (s *invitationService) GetAll(ctx context.Context) ([]*domain.Invitation, error) {
+	return s.repository.GetAll(ctx)
+}
+
+func (s *invitationService) GetPending(ctx context.Context, token string) (*domain.Invitation, error) {
+	return findPendingInvitation(ctx, s.repository, token)
+}
+
+func (s *invitationService) Revoke(ctx context.Context, id int64) error {
+	if err := s.repository.Revoke(ctx, id); err != nil {
+		return err
+	}
+
+	logging.FromContext(ctx).Info("invitation revoked", "invitation_id", id)
+	return nil
+}
+
+func findPendingInvitation(ctx context.Context, invitations repository.InvitationRepository, token string) (*domain.Invitation, error) {
+	invalid := invalidInvitationError()
+
+	invitation, err := invitations.GetByHash(ctx, hashing.HashToken(token))
+	if appErr, ok := responses.IsAppError(err); ok && appErr.Kind == responses.KindNotFound {
+		return nil, invalid
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !invitation.IsPending(time.Now()) {
+		return nil, invalid
+	}
+	return invitation, nil
+}
+
+// createUser creates user, accepting invitation in the same transaction
+// when there is one, so an invitation is used by exactly one user.
+func createUser(ctx context.Context, users repository.UserRepository, invitations repository.InvitationRepository, invitation *domain.Invitation, user *domain.User) error {
+	if invitation == nil {
+		return users.CreateUser(ctx, user)
+	}
+
+	err := invitations.Accept(ctx, invitation.ID, user)
+	if appErr, ok := responses.IsAppError(err); ok && appErr.Kind == responses.KindNotFound {
+		return invalidInvitationError()
+	}
+	return err
+}
+
+func invalidInvitationError() error {
+	return responses.NewValidationError("This invitation is invalid or has expired", []string{"invitation_token"}, nil)
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"slices"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mjmarrazzo/maintenance-app/domain"
+	"github.com/mjmarrazzo/maintenance-app/internal/logging"
+	"github.com/mjmarrazzo/maintenance-app/repository"
+)
+
+const (
+	notificationInboxLimit = 50
+	commentExcerptLength   = 140
+)
+
+type NotificationService interface {
+	HandleTaskEvent(ctx context.Context, event domain.TaskEvent)
+	GetInbox(ctx context.Context, userID int64) ([]*domain.Notification, error)
+	CountUnread(ctx context.Context, userID int64) (int, error)
+	MarkRead(ctx context.Context, userID, id int64) (*domain.Notification, error)
+	MarkAllRead(ctx context.Context, userID int64) error
+}
+
+type notificationService struct {
+	repository repository.NotificationRepository
+}
+
+func NewNotificationService(pool *pgxpool.Pool) NotificationService {
+	return &notificationService{repository: repository.NewNotificationRepository(pool)}
+}
+
+// HandleTaskEvent notifies the users concerned by event. It is subscribed
+// to events.Default at startup.
+func (s *notificationService) HandleTaskEvent(ctx context.Context, event domain.TaskEvent) {
+	logger := logging.FromContext(ctx)
+
+	for _, userID := range notificationRecipients(event) {
+		notification := &domain.Notification{
+			UserID: userID,
+			TaskID: event.Task.ID,
+			Kind:   event.Kind,
+			Detail: notificationDetail(event),
+		}
+		if event.ActorID != 0 {
+			notification.ActorID.Int64 = event.ActorID
+			notification.ActorID.Valid = true
+		}
+
+		if err := s.repository.Create(ctx, notification); err != nil {
+			logger.Error("failed to create notification", "kind", event.Kind, "task_id", event.Task.ID, "user_id", userID, "error", err)
+		}
+	}
+}
+
+// notificationRecipients returns who hears about event: the assignee, and
+// for status changes and comments also the creator. Nobody is notified
+// about their own actions.
+func notificationRecipients(event domain.TaskEvent) []int64 {
+	task := event.Task
+
+	var candidates []int64
+	switch event.Kind {
+	case domain.TaskEventAssigned:
+		candidates = []int64{task.AssignedTo.Int64}
+	case domain.TaskEventStatusChanged, domain.TaskEventCommented:
+		candidates = []int64{task.AssignedTo.Int64, task.CreatedBy}
+	case domain.TaskEventDueSoon, domain.TaskEventOverdue:
+		if task.AssignedTo.Valid {
+			candidates = []int64{task.AssignedTo.Int64}
+		} else {
+			candidates = []int64{task.CreatedBy}
+		}
+	}
+
+	var recipients []int64
+	for _, userID := range candidates {
+		if userID == 0 || userID == event.ActorID || slices.Contains(recipients, userID) {
+			continue
+		}
+		recipients = append(recipients, userID)
+	}
+	return recipients
+}
+
+func notificationDetail(event domain.TaskEvent) string {
+	switch event.Kind {
+	case domain.TaskEventStatusChanged:
+		return event.Task.Status.String
+	case domain.TaskEventCommented:
+		content := []rune(event.Comment.Content)
+		if len(content) > commentExcerptLength {
+			return string(content[:commentExcerptLength]) + "…"
+		}
+		return string(content)
+	default:
+		return ""
+	}
+}
+
+func (s *notificationService) GetInbox(ctx context.Context, userID int64) ([]*domain.Notification, error) {
+	return s.repository.GetByUserID(ctx, userID, notificationInboxLimit)
+}
+
+func (s *notificationService) CountUnread(ctx context.Context, userID int64) (int, error) {
+	return s.repository.CountUnread(ctx, userID)
+}
+
+func (s *notificationService) MarkRead(ctx context.Context, userID, id int64) (*domain.Notification, error) {
+	return s.repository.MarkRead(ctx, userID, id)
+}
+
+func (s *notificationService) MarkAllRead(ctx context.Context, userID int64) error {
+	if err := s.repository.MarkAllRead(ctx, userID); err != nil {
+		return err
+	}
+
+	logging.FromContext(ctx).Info("notifications marked read", "user_id", userID)
+	return nil
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mjmarrazzo/maintenance-app/domain"
+	"github.com/mjmarrazzo/maintenance-app/internal/logging"
+	"github.com/mjmarrazzo/maintenance-app/internal/mail"
+	"github.com/mjmarrazzo/maintenance-app/repository"
+)
+
+const outboxBatchSize = 20
+
+type OutboxService interface {
+	ProcessOutbox(ctx context.Context, now time.Time) error
+}
+
+type outboxService struct {
+	outbox repository.EmailOutboxRepository
+	mailer mail.Sender
+}
+
+func NewOutboxService(pool *pgxpool.Pool, mailer mail.Sender) OutboxService {
+	return &outboxService{
+		outbox: repository.NewEmailOutboxRepository(pool),
+		mailer: mailer,
+	}
+}
+
+// ProcessOutbox delivers the emails that are due. Failed deliveries are
+// retried with exponential backoff until domain.OutboxMaxAttempts.
+func (s *outboxService) ProcessOutbox(ctx context.Context, now time.Time) error {
+	logger := logging.FromContext(ctx)
+
+	emails, err := s.outbox.ClaimDue(ctx, now, outboxBatchSize)
+	if err != nil {
+		return err
+	}
+
+	for _, email := range emails {
+		sendErr := s.mailer.Send(ctx, mail.Message{
+			To:      email.Recipient,
+			Subject: email.Subject,
+			Text:    email.TextBody,
+			HTML:    email.HTMLBody,
+		})
+		if sendErr == nil {
+			if err := s.outbox.MarkSent(ctx, email.ID); err != nil {
+				return err
+			}
+			continue
+		}
+
+		attempts := email.Attempts + 1
+		if attempts >= domain.OutboxMaxAttempts {
+			logger.Error("giving up on email", "email_id", email.ID, "attempts", attempts, "error", sendErr)
+			if err := s.outbox.MarkFailed(ctx, email.ID, attempts, sendErr.Error()); err != nil {
+				return err
+			}
+			continue
+		}
+
+		retryAt := now.Add(domain.Backoff(attempts, 1, domain.OutboxRetryBase, domain.OutboxRetryMax))
+		logger.Warn("email delivery failed", "email_id", email.ID, "attempts", attempts, "retry_at", retryAt, "error", sendErr)
+		if err := s.outbox.MarkRetry(ctx, email.ID, attempts, retryAt, sendErr.Error()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mjmarrazzo/maintenance-app/domain"
+	"github.com/mjmarrazzo/maintenance-app/internal/hashing"
+	"github.com/mjmarrazzo/maintenance-app/internal/logging"
+	"github.com/mjmarrazzo/maintenance-app/internal/responses"
+	"github.com/mjmarrazzo/maintenance-app/repository"
+)
+
+// sessionTouchInterval limits how often an active session writes its
+// last-seen timestamp and slides its expiry.
+const sessionTouchInterval = time.Minute
+
+type SessionService interface {
+	// Create starts a session for userID and returns it with the plaintext
+	// token that goes into the cookie.
+	Create(ctx context.Context, userID int64, userAgent, ipAddress string) (*domain.Session, string, error)
+	// Authenticate resolves a session token to its user, reloading the user
+	// so role changes apply immediately, and slides the session expiry.
+	Authenticate(ctx context.Context, token string) (*domain.User, *domain.Session, error)
+	GetActiveByUserID(ctx context.Context, userID int64) ([]*domain.Session, error)
+	Revoke(ctx context.Context, userID, id int64) error
+	RevokeToken(ctx context.Context, token string) error
+	RevokeAll(ctx context.Context, userID int64) error
+}
+
+type sessionService struct {
+	repository     repository.SessionRepository
+	userRepository repository.UserRepository
+}
+
+func NewSessionService(pool *pgxpool.Pool) SessionService {
+	return &sessionService{
+		repository:     repository.NewSessionRepository(pool),
+		userRepository: repository.NewUserRepository(pool),
+	}
+}
+
+func (s *sessionService) Create(ctx context.Context, userID int64, userAgent, ipAddress string) (*domain.Session, string, error) {
+	logger := logging.FromContext(ctx)
+	if removed, err := s.repository.DeleteExpired(ctx); err != nil {
+		logger.Warn("failed to delete expired sessions", "error", err)
+	} else if removed > 0 {
+		logger.Debug("deleted expired sessions", "count", removed)
+	}
+
+	token, err := hashing.GenerateSecret()
+	if err != nil {
+		return nil, "", err
+	}
+
+	now := time.Now()
+	session := &domain.Session{
+		UserID:    userID,
+		TokenHash: hashing.HashToken(token),
+		UserAgent: userAgent,
+		IPAddress: ipAddress,
+		CreatedAt: now,
+	}
+	session.LastSeenAt = now
+	session.ExpiresAt = session.NextExpiry(now)
+
+	if err := s.repository.Create(ctx, session); err != nil {
+		return nil, "", err
+	}
+
+	logger.Info("session created", "session_id", session.ID, "user_id", userID)
+	return session, token, nil
+}
+
+func (s *sessionService) Authenticate(ctx context.Context, token string) (*domain.User, *domain.Session, error) {
+	expired := responses.NewUnauthorizedError("Session expired")
+	if token == "" {
+		return nil, nil, expired
+	}
+
+	session, err := s.repository.GetByHash(ctx, hashing.HashToken(token))
+	if err != nil {
+		if appErr, ok := responses.IsAppError(err); ok && appErr.Kind == responses.KindNotFound {
+			return nil, nil, expired
+		}
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	if session.IsExpired(now) {
+		return nil, nil, expired
+	}
+
+	user, err := s.userRepository.GetUserByID(ctx, session.UserID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if now.Sub(session.LastSeenAt) >= sessionTouchInterval {
+		expiresAt := session.NextExpiry(now)
+		if err := s.repository.Touch(ctx, session.ID, now, expiresAt); err != nil {
+			logging.FromContext(ctx).Warn("failed to extend session", "session_id", session.ID, "error", err)
+		} else {
+			session.LastSeenAt = now
+			session.ExpiresAt = expiresAt
+		}
+	}
+
+	return user, session, nil
+}
+
+func (s *sessionService) GetActiveByUserID(ctx context.Context, userID int64) ([]*domain.Session, error) {
+	return s.repository.GetActiveByUserID(ctx, userID)
+}
+
+func (s *sessionService) Revoke(ctx context.Context, userID, id int64) error {
+	if err := s.repository.Delete(ctx, userID, id); err != nil {
+		return err
+	}
+
+	logging.FromContext(ctx).Info("session revoked", "session_id", id)
+	return nil
+}
+
+func (s *sessionService) RevokeToken(ctx context.Context, token string) error {
+	return s.repository.DeleteByHash(ctx, hashing.HashToken(token))
+}
+
+func (s *sessionService) RevokeAll(ctx context.Context, userID int64) error {
+	if err := s.repository.DeleteByUserID(ctx, userID); err != nil {
+		return err
+	}
+
+	logging.FromContext(ctx).Info("all sessions revoked")
+	return nil
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mjmarrazzo/maintenance-app/domain"
+	"github.com/mjmarrazzo/maintenance-app/internal/i18n"
+	"github.com/mjmarrazzo/maintenance-app/internal/logging"
+	"github.com/mjmarrazzo/maintenance-app/internal/responses"
+	"github.com/mjmarrazzo/maintenance-app/repository"
+)
+
+type SettingService interface {
+	GetRegistrationMode(ctx context.Context) (domain.RegistrationMode, error)
+	RequiresAdminTwoFactor(ctx context.Context) (bool, error)
+	GetTimeZone(ctx context.Context) (*time.Location, error)
+	// GetWIPLimits returns the limits of the statuses that have one.
+	GetWIPLimits(ctx context.Context) (map[domain.Status]int, error)
+	Update(ctx context.Context, sr *domain.SettingsRequest) error
+}
+
+type settingService struct {
+	repository repository.SettingRepository
+}
+
+func NewSettingService(pool *pgxpool.Pool) SettingService {
+	return &settingService{repository: repository.NewSettingRepository(pool)}
+}
+
+func (s *settingService) GetRegistrationMode(ctx context.Context) (domain.RegistrationMode, error) {
+	return registrationMode(ctx, s.repository)
+}
+
+func (s *settingService) RequiresAdminTwoFactor(ctx context.Context) (bool, error) {
+	return requiresAdminTwoFactor(ctx, s.repository)
+}
+
+func (s *settingService) GetTimeZone(ctx context.Context) (*time.Location, error) {
+	return timeZone(ctx, s.repository)
+}
+
+func (s *settingService) GetWIPLimits(ctx context.Context) (map[domain.Status]int, error) {
+	limits := make(map[domain.Status]int)
+	for _, status := range domain.WIPLimitStatuses {
+		value, err := s.repository.Get(ctx, domain.WIPLimitSetting(status))
+		if appErr, ok := responses.IsAppError(err); ok && appErr.Kind == responses.KindNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 0 {
+			logging.FromContext(ctx).Warn("invalid work in progress limit setting", "status", status, "value", value)
+			continue
+		}
+		if limit > 0 {
+			limits[status] = limit
+		}
+	}
+	return limits, nil
+}
+
+func (s *settingService) Update(ctx context.Context, sr *domain.SettingsRequest) error {
+	if err := s.repository.Set(ctx, domain.SettingRegistrationMode, sr.RegistrationMode); err != nil {
+		return err
+	}
+
+	if err := s.repository.Set(ctx, domain.SettingRequireAdminTwoFactor, strconv.FormatBool(sr.RequireAdminTwoFactor)); err != nil {
+		return err
+	}
+
+	if err := s.repository.Set(ctx, domain.SettingTimeZone, sr.TimeZone); err != nil {
+		return err
+	}
+
+	limits := sr.WIPLimits()
+	for _, status := range domain.WIPLimitStatuses {
+		if err := s.repository.Set(ctx, domain.WIPLimitSetting(status), strconv.Itoa(limits[status])); err != nil {
+			return err
+		}
+	}
+
+	logging.FromContext(ctx).Info("settings updated",
+		"registration_mode", sr.RegistrationMode,
+		"require_admin_two_factor", sr.RequireAdminTwoFactor,
+		"time_zone", sr.TimeZone,
+		"wip_limits", limits,
+	)
+	return nil
+}
+
+// registrationMode falls back to invite-only when the setting is missing or
+// invalid, so a broken configuration never opens registration.
+func registrationMode(ctx context.Context, settings repository.SettingRepository) (domain.RegistrationMode, error) {
+	value, err := settings.Get(ctx, domain.SettingRegistrationMode)
+	if appErr, ok := responses.IsAppError(err); ok && appErr.Kind == responses.KindNotFound {
+		return domain.RegistrationInviteOnly, nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	mode := domain.RegistrationMode(value)
+	if !mode.IsValid() {
+		logging.FromContext(ctx).Warn("invalid registration mode setting", "value", value)
+		return domain.RegistrationInviteOnly, nil
+	}
+	return mode, nil
+}
+
+// requiresAdminTwoFactor defaults to true for the same reason: only an
+// explicit setting relaxes the policy.
+func requiresAdminTwoFactor(ctx context.Context, settings repository.SettingRepository) (bool, error) {
+	value, err := settings.Get(ctx, domain.SettingRequireAdminTwoFactor)
+	if appErr, ok := responses.IsAppError(err); ok && appErr.Kind == responses.KindNotFound {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	required, err := strconv.ParseBool(value)
+	if err != nil {
+		logging.FromContext(ctx).Warn("invalid two-factor policy setting", "value", value)
+		return true, nil
+	}
+	return required, nil
+}
+
+// timeZone falls back to UTC when the setting is missing or names no known
+// time zone.
+func timeZone(ctx context.Context, settings repository.SettingRepository) (*time.Location, error) {
+	value, err := settings.Get(ctx, domain.SettingTimeZone)
+	if appErr, ok := responses.IsAppError(err); ok && appErr.Kind == responses.KindNotFound {
+		return time.UTC, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	zone := i18n.LoadTimeZone(value)
+	if zone == nil {
+		logging.FromContext(ctx).Warn("invalid time zone setting", "value", value)
+		return time.UTC, nil
+	}
+	return zone, nil
+}
+
+// userTimeZone is the time zone user picked, or the one of the
+// organization.
+func userTimeZone(ctx context.Context, settings repository.SettingRepository, user *domain.User) (*time.Location, error) {
+	if zone := i18n.LoadTimeZone(user.TimeZone.String); zone != nil {
+		return zone, nil
+	}
+	return timeZone(ctx, settings)
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mjmarrazzo/maintenance-app/domain"
+	"github.com/mjmarrazzo/maintenance-app/internal/logging"
+	"github.com/mjmarrazzo/maintenance-app/internal/responses"
+	"github.com/mjmarrazzo/maintenance-app/internal/sso"
+	"github.com/mjmarrazzo/maintenance-app/repository"
+)
+
+type SSOService interface {
+	Authenticate(ctx context.Context, provider *sso.Provider, identity *sso.Identity, ipAddress string) (*domain.User, error)
+}
+
+type ssoService struct {
+	users         repository.UserRepository
+	identities    repository.UserIdentityRepository
+	invitations   repository.InvitationRepository
+	settings      repository.SettingRepository
+	loginAttempts repository.LoginAttemptRepository
+}
+
+func NewSSOService(pool *pgxpool.Pool) SSOService {
+	return &ssoService{
+		users:         repository.NewUserRepository(pool),
+		identities:    repository.NewUserIdentityRepository(pool),
+		invitations:   repository.NewInvitationRepository(pool),
+		settings:      repository.NewSettingRepository(pool),
+		loginAttempts: repository.NewLoginAttemptRepository(pool),
+	}
+}
+
+// Authenticate resolves the user behind a provider identity. Known
+// identities sign in directly. Otherwise the identity is linked to the user
+// with the same verified email address, or a user is provisioned if the
+// registration rules allow it.
+func (s *ssoService) Authenticate(ctx context.Context, provider *sso.Provider, identity *sso.Identity, ipAddress string) (*domain.User, error) {
+	logger := logging.FromContext(ctx).With("provider", identity.Provider)
+
+	linked, err := s.identities.GetByProviderSubject(ctx, identity.Provider, identity.Subject)
+	if err == nil {
+		user, err := s.users.GetUserByID(ctx, linked.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.checkLocked(ctx, user, ipAddress); err != nil {
+			return nil, err
+		}
+		if err := s.identities.TouchLastLogin(ctx, linked.ID); err != nil {
+			return nil, err
+		}
+		recordLoginAttempt(ctx, s.loginAttempts, user.Email, user, ipAddress, "")
+		return user, nil
+	}
+	if appErr, ok := responses.IsAppError(err); !ok || appErr.Kind != responses.KindNotFound {
+		return nil, err
+	}
+
+	// Linking and provisioning both trust the email address, so it has to
+	// be one the provider verified.
+	if identity.Email == "" || !identity.EmailVerified {
+		logger.Warn("single sign-on without verified email", "subject", identity.Subject)
+		return nil, responses.NewForbiddenError(fmt.Sprintf("Your %s account has no verified email address", provider.DisplayName))
+	}
+
+	user, err := s.users.GetUserByEmail(ctx, identity.Email)
+	if appErr, ok := responses.IsAppError(err); ok && appErr.Kind == responses.KindNotFound {
+		user, err = s.provision(ctx, provider, identity)
+	} else if err == nil {
+		err = s.checkLocked(ctx, user, ipAddress)
+		if err == nil && !user.IsEmailVerified() {
+			err = s.users.MarkEmailVerified(ctx, user.ID)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.identities.Create(ctx, &domain.UserIdentity{
+		UserID:   user.ID,
+		Provider: identity.Provider,
+		Subject:  identity.Subject,
+		Email:    identity.Email,
+	}); err != nil {
+		return nil, err
+	}
+
+	logger.Info("single sign-on identity linked", "user", user)
+	recordLoginAttempt(ctx, s.loginAttempts, user.Email, user, ipAddress, "")
+	return user, nil
+}
+
+// checkLocked rejects users locked after too many failed logins, who must
+// not get around the lock by signing in with a provider instead.
+func (s *ssoService) checkLocked(ctx context.Context, user *domain.User, ipAddress string) error {
+	if !user.IsLocked(time.Now()) {
+		return nil
+	}
+
+	logging.FromContext(ctx).Info("authentication failed", "reason", domain.LoginReasonLocked, "user", user)
+	recordLoginAttempt(ctx, s.loginAttempts, user.Email, user, ipAddress, domain.LoginReasonLocked)
+	return responses.NewUnauthorizedError("Invalid credentials")
+}
+
+// provision creates a user for an identity with no account yet. That needs
+// a pending invitation, an email domain the provider allows, or open
+// registration; closed registration rejects everyone.
+func (s *ssoService) provision(ctx context.Context, provider *sso.Provider, identity *sso.Identity) (*domain.User, error) {
+	mode, err := registrationMode(ctx, s.settings)
+	if err != nil {
+		return nil, err
+	}
+	if mode == domain.RegistrationClosed {
+		return nil, responses.NewForbiddenError("Registration is closed")
+	}
+
+	invitation, err := s.invitations.GetPendingByEmail(ctx, identity.Email)
+	if appErr, ok := responses.IsAppError(err); ok && appErr.Kind == responses.KindNotFound {
+		invitation, err = nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	role := domain.RoleUser
+	switch {
+	case invitation != nil:
+		role = invitation.Role
+	case provider.AllowsDomain(identity.Email), mode == domain.RegistrationOpen:
+	default:
+		return nil, responses.NewForbiddenError(fmt.Sprintf("There is no account for %s. Ask an administrator for an invitation.", identity.Email))
+	}
+
+	firstName, lastName := identity.FirstName, identity.LastName
+	if firstName == "" {
+		firstName, _, _ = strings.Cut(identity.Email, "@")
+	}
+
+	// Provisioned users have no password. They can set one through
+	// /forgot-password if they ever want to sign in without the provider.
+	user := &domain.User{
+		FirstName: firstName,
+		LastName:  lastName,
+		Email:     identity.Email,
+		Role:      role,
+	}
+	user.EmailVerifiedAt.Time = time.Now()
+	user.EmailVerifiedAt.Valid = true
+
+	if err := createUser(ctx, s.users, s.invitations, invitation, user); err != nil {
+		return nil, err
+	}
+
+	logging.FromContext(ctx).Info("user provisioned through single sign-on", "user", user, "provider", identity.Provider)
+	return user, nil
+}
@@ -2,35 +2,93 @@ package service
 
 import (
 	"context"
+	"database/sql"
+	"maps"
+	"slices"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/mjmarrazzo/maintenance-app/domain"
+	"github.com/mjmarrazzo/maintenance-app/internal/events"
+	"github.com/mjmarrazzo/maintenance-app/internal/i18n"
+	"github.com/mjmarrazzo/maintenance-app/internal/logging"
+	"github.com/mjmarrazzo/maintenance-app/internal/responses"
 	"github.com/mjmarrazzo/maintenance-app/repository"
 )
 
 type TaskService interface {
 	Create(ctx context.Context, userId int64, task *domain.TaskRequest) (*domain.Task, error)
 	GetAll(ctx context.Context) ([]*domain.Task, error)
+	List(ctx context.Context, filters repository.TaskFilters) ([]*domain.Task, error)
+	// Calendar returns the occurrences of the tasks matching filters due
+	// from from until to, sorted by due date.
+	Calendar(ctx context.Context, filters repository.TaskFilters, from, to time.Time) ([]domain.Occurrence, error)
+	// CountByStatus counts the tasks matching filters by status.
+	CountByStatus(ctx context.Context, filters repository.TaskFilters) (map[domain.Status]int, error)
 	GetByID(ctx context.Context, id int64) (*domain.Task, error)
-	Update(ctx context.Context, id int64, task *domain.TaskRequest) (*domain.Task, error)
+	Update(ctx context.Context, userId, id int64, task *domain.TaskRequest) (*domain.Task, error)
+	// Patch changes only the fields in the patch.
+	Patch(ctx context.Context, userId, id int64, patch *domain.TaskPatch) (*domain.Task, error)
 	Delete(ctx context.Context, id int64) error
+	UpdateStatus(ctx context.Context, userId, id int64, status domain.Status) (*domain.Task, error)
+	CheckDueDates(ctx context.Context, now time.Time) error
+}
+
+// TaskConflictError is returned by Update when the task was saved by
+// someone else after the version the request is based on. It unwraps to a
+// conflict AppError.
+type TaskConflictError struct {
+	// Current is the task as it is saved now.
+	Current *domain.Task
+	// Mine is the task as the request would have saved it.
+	Mine *domain.Task
+}
+
+func (e *TaskConflictError) Error() string {
+	return e.Unwrap().Error()
+}
+
+func (e *TaskConflictError) Unwrap() error {
+	return responses.NewConflictError("The task was changed by someone else since you loaded it")
 }
 
 type taskService struct {
 	repository repository.TaskRepository
+	categories repository.CategoryRepository
+	locations  repository.LocationRepository
+	users      repository.UserRepository
+	reminders  repository.TaskReminderRepository
+	bus        *events.Bus
 }
 
 func NewTaskService(pool *pgxpool.Pool) TaskService {
-	return &taskService{repository: repository.NewTaskRepository(pool)}
+	return &taskService{
+		repository: repository.NewTaskRepository(pool),
+		categories: repository.NewCategoryRepository(pool),
+		locations:  repository.NewLocationRepository(pool),
+		users:      repository.NewUserRepository(pool),
+		reminders:  repository.NewTaskReminderRepository(pool),
+		bus:        events.Default(),
+	}
 }
 
 func (s *taskService) Create(ctx context.Context, userId int64, tr *domain.TaskRequest) (*domain.Task, error) {
-	task := tr.ToDomain()
+	task, err := tr.ToDomain(i18n.TimeZone(ctx))
+	if err != nil {
+		return nil, err
+	}
 	task.CreatedBy = userId
 
+	if err := s.validate(ctx, task, nil); err != nil {
+		return nil, err
+	}
+
 	if err := s.repository.Create(ctx, task); err != nil {
 		return nil, err
 	}
+
+	logging.FromContext(ctx).Info("task created", "task_id", task.ID, "user_id", userId)
+	s.publishChanges(ctx, userId, &domain.Task{}, task)
 	return task, nil
 }
 
@@ -38,23 +96,300 @@ func (s *taskService) GetAll(ctx context.Context) ([]*domain.Task, error) {
 	return s.repository.GetAll(ctx, repository.TaskFilters{})
 }
 
+func (s *taskService) List(ctx context.Context, filters repository.TaskFilters) ([]*domain.Task, error) {
+	return s.repository.GetAll(ctx, filters)
+}
+
+// Calendar projects the occurrences of recurring tasks in the time zone of
+// ctx. Recurring tasks are loaded whatever their due date, since an earlier
+// one can still recur in the range.
+func (s *taskService) Calendar(ctx context.Context, filters repository.TaskFilters, from, to time.Time) ([]domain.Occurrence, error) {
+	single, recurring := filters, filters
+	isRecurring, isSingle := true, false
+	single.IsRecurring, single.DueFrom, single.DueTo = &isSingle, &from, &to
+	recurring.IsRecurring = &isRecurring
+
+	var occurrences []domain.Occurrence
+	for _, filters := range []repository.TaskFilters{single, recurring} {
+		tasks, err := s.repository.GetAll(ctx, filters)
+		if err != nil {
+			return nil, err
+		}
+		for _, task := range tasks {
+			occurrences = append(occurrences, task.Occurrences(from, to, i18n.TimeZone(ctx))...)
+		}
+	}
+
+	slices.SortStableFunc(occurrences, func(a, b domain.Occurrence) int {
+		return a.Due.Compare(b.Due)
+	})
+	return occurrences, nil
+}
+
+func (s *taskService) CountByStatus(ctx context.Context, filters repository.TaskFilters) (map[domain.Status]int, error) {
+	return s.repository.CountByStatus(ctx, filters)
+}
+
 func (s *taskService) GetByID(ctx context.Context, id int64) (*domain.Task, error) {
 	return s.repository.GetByID(ctx, id)
 }
 
-func (s *taskService) Update(ctx context.Context, id int64, tr *domain.TaskRequest) (*domain.Task, error) {
-	task := tr.ToDomain()
+func (s *taskService) Update(ctx context.Context, userId, id int64, tr *domain.TaskRequest) (*domain.Task, error) {
+	previous, err := s.repository.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := tr.ToDomain(i18n.TimeZone(ctx))
+	if err != nil {
+		return nil, err
+	}
 	task.ID = id
 
-	if err := s.repository.Update(ctx, task); err != nil {
+	if err := s.validate(ctx, task, nil); err != nil {
 		return nil, err
 	}
-	return task, nil
+
+	err = s.save(ctx, previous, task, func() error {
+		return s.repository.Update(ctx, userId, task)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logging.FromContext(ctx).Info("task updated", "task_id", id)
+	return s.reloadAndPublish(ctx, userId, previous)
+}
+
+func (s *taskService) Patch(ctx context.Context, userId, id int64, tp *domain.TaskPatch) (*domain.Task, error) {
+	previous, err := s.repository.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	columns := tp.Columns()
+	if len(columns) == 0 {
+		return previous, nil
+	}
+
+	mine := *previous
+	mine.UpdatedAt = time.Time{}
+	tp.Apply(&mine)
+
+	if err := s.validate(ctx, &mine, slices.Collect(maps.Keys(columns))); err != nil {
+		return nil, err
+	}
+
+	err = s.save(ctx, previous, &mine, func() error {
+		return s.repository.Patch(ctx, userId, id, columns, mine.UpdatedAt)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logging.FromContext(ctx).Info("task patched", "task_id", id, "fields", slices.Sorted(maps.Keys(columns)))
+	return s.reloadAndPublish(ctx, userId, previous)
+}
+
+// validate checks the cross-field rules of task and that the records the
+// fields in fields refer to exist. nil fields checks all references.
+func (s *taskService) validate(ctx context.Context, task *domain.Task, fields []string) error {
+	if err := task.Validate(); err != nil {
+		return err
+	}
+
+	references := []struct {
+		field  string
+		id     sql.NullInt64
+		exists func(ctx context.Context, id int64) error
+	}{
+		{"category_id", task.CategoryID, func(ctx context.Context, id int64) error {
+			_, err := s.categories.GetByID(ctx, id)
+			return err
+		}},
+		{"location_id", task.LocationID, func(ctx context.Context, id int64) error {
+			_, err := s.locations.GetByID(ctx, id)
+			return err
+		}},
+		{"assigned_to", task.AssignedTo, func(ctx context.Context, id int64) error {
+			_, err := s.users.GetUserByID(ctx, id)
+			return err
+		}},
+		{"parent_task_id", task.ParentTaskID, func(ctx context.Context, id int64) error {
+			_, err := s.repository.GetByID(ctx, id)
+			return err
+		}},
+	}
+
+	var parameters []string
+	var violations []*responses.ViolationsDetail
+	for _, reference := range references {
+		if !reference.id.Valid || (fields != nil && !slices.Contains(fields, reference.field)) {
+			continue
+		}
+		err := reference.exists(ctx, reference.id.Int64)
+		if appErr, ok := responses.IsAppError(err); ok && appErr.Kind == responses.KindNotFound {
+			parameters = append(parameters, reference.field)
+			violations = append(violations, &responses.ViolationsDetail{Name: reference.field, Message: "Does not exist"})
+		} else if err != nil {
+			return err
+		}
+	}
+
+	if len(violations) > 0 {
+		return responses.NewValidationError("Validation failed", parameters, violations)
+	}
+	return nil
+}
+
+// save runs update, which is conditional on mine.UpdatedAt when it is set,
+// and reports a stale update as a TaskConflictError.
+func (s *taskService) save(ctx context.Context, previous, mine *domain.Task, update func() error) error {
+	if mine.UpdatedAt.IsZero() {
+		return update()
+	}
+	if !mine.UpdatedAt.Equal(previous.UpdatedAt) {
+		return s.conflict(ctx, previous, mine)
+	}
+
+	err := update()
+	if appErr, ok := responses.IsAppError(err); ok && appErr.Kind == responses.KindNotFound {
+		// The task was saved by someone else between loading and updating it.
+		current, err := s.repository.GetByID(ctx, previous.ID)
+		if err != nil {
+			return err
+		}
+		return s.conflict(ctx, current, mine)
+	}
+	return err
+}
+
+// conflict loads the names Task.Changes compares for mine.
+func (s *taskService) conflict(ctx context.Context, current, mine *domain.Task) error {
+	logging.FromContext(ctx).Info("task update conflict", "task_id", current.ID, "expected_updated_at", mine.UpdatedAt, "updated_at", current.UpdatedAt)
+
+	mine.CreatedBy = current.CreatedBy
+	mine.CreatedAt = current.CreatedAt
+	if mine.CategoryID.Valid {
+		if category, err := s.categories.GetByID(ctx, mine.CategoryID.Int64); err == nil {
+			mine.CategoryName = sql.NullString{String: category.Name, Valid: true}
+		}
+	}
+	if mine.LocationID.Valid {
+		if location, err := s.locations.GetByID(ctx, mine.LocationID.Int64); err == nil {
+			mine.LocationName = sql.NullString{String: location.Name, Valid: true}
+		}
+	}
+
+	return &TaskConflictError{Current: current, Mine: mine}
 }
 
 func (s *taskService) Delete(ctx context.Context, id int64) error {
 	if err := s.repository.Delete(ctx, id); err != nil {
 		return err
 	}
+
+	logging.FromContext(ctx).Info("task deleted", "task_id", id)
+	return nil
+}
+
+func (s *taskService) UpdateStatus(ctx context.Context, userId, id int64, status domain.Status) (*domain.Task, error) {
+	previous, err := s.repository.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repository.UpdateStatus(ctx, userId, id, status); err != nil {
+		return nil, err
+	}
+
+	logging.FromContext(ctx).Info("task status updated", "task_id", id, "status", status)
+	return s.reloadAndPublish(ctx, userId, previous)
+}
+
+func (s *taskService) reloadAndPublish(ctx context.Context, userId int64, previous *domain.Task) (*domain.Task, error) {
+	task, err := s.repository.GetByID(ctx, previous.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.publishChanges(ctx, userId, previous, task)
+	return task, nil
+}
+
+// publishChanges raises the events for what changed between previous and
+// task. New tasks are compared against an empty task.
+func (s *taskService) publishChanges(ctx context.Context, userId int64, previous, task *domain.Task) {
+	event := domain.TaskEvent{
+		Task:       task,
+		Previous:   previous,
+		ActorID:    userId,
+		OccurredAt: time.Now(),
+	}
+
+	if previous.ID == 0 {
+		event.Kind = domain.TaskEventCreated
+		s.bus.Publish(ctx, event)
+	}
+
+	if task.AssignedTo.Valid && task.AssignedTo != previous.AssignedTo {
+		event.Kind = domain.TaskEventAssigned
+		s.bus.Publish(ctx, event)
+	}
+
+	if previous.ID != 0 && task.Status != previous.Status {
+		event.Kind = domain.TaskEventStatusChanged
+		s.bus.Publish(ctx, event)
+	}
+}
+
+// overdueLookback limits the overdue check to recently missed due dates, so
+// tasks that have been overdue for a long time do not all raise events at
+// once after the check is first deployed.
+const overdueLookback = 7 * 24 * time.Hour
+
+// CheckDueDates raises the due soon and overdue events for open tasks. Each
+// event is raised once per task and due date however often this runs.
+func (s *taskService) CheckDueDates(ctx context.Context, now time.Time) error {
+	dueSoonUntil := now.Add(domain.DueSoonWindow)
+	overdueSince := now.Add(-overdueLookback)
+
+	checks := []struct {
+		kind domain.TaskEventKind
+		from *time.Time
+		to   *time.Time
+	}{
+		{domain.TaskEventDueSoon, &now, &dueSoonUntil},
+		{domain.TaskEventOverdue, &overdueSince, &now},
+	}
+
+	isCompleted := false
+	for _, check := range checks {
+		tasks, err := s.repository.GetAll(ctx, repository.TaskFilters{
+			IsCompleted: &isCompleted,
+			DueFrom:     check.from,
+			DueTo:       check.to,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, task := range tasks {
+			claimed, err := s.reminders.Claim(ctx, task.ID, check.kind, task.EstimatedCompletionDate.Time)
+			if err != nil {
+				return err
+			}
+			if !claimed {
+				continue
+			}
+
+			logging.FromContext(ctx).Info("task reminder", "task_id", task.ID, "kind", check.kind)
+			s.bus.Publish(ctx, domain.TaskEvent{
+				Kind:       check.kind,
+				Task:       task,
+				OccurredAt: now,
+			})
+		}
+	}
 	return nil
 }
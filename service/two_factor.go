@@ -0,0 +1,292 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mjmarrazzo/maintenance-app/domain"
+	"github.com/mjmarrazzo/maintenance-app/internal/hashing"
+	"github.com/mjmarrazzo/maintenance-app/internal/logging"
+	"github.com/mjmarrazzo/maintenance-app/internal/responses"
+	"github.com/mjmarrazzo/maintenance-app/internal/totp"
+	"github.com/mjmarrazzo/maintenance-app/repository"
+)
+
+// TwoFactorIssuer is the account issuer shown in authenticator apps.
+const TwoFactorIssuer = "Groundwork"
+
+type TwoFactorEnrollment struct {
+	Secret string
+	URI    string
+}
+
+type TwoFactorService interface {
+	IsRequired(ctx context.Context, user *domain.User) (bool, error)
+	BeginEnrollment(ctx context.Context, user *domain.User) (*TwoFactorEnrollment, error)
+	ConfirmEnrollment(ctx context.Context, user *domain.User, code string) ([]string, error)
+	Verify(ctx context.Context, userID int64, code, ipAddress string) (*domain.User, error)
+	RegenerateRecoveryCodes(ctx context.Context, user *domain.User, code string) ([]string, error)
+	Disable(ctx context.Context, user *domain.User, code string) error
+	RemainingRecoveryCodes(ctx context.Context, user *domain.User) (int, error)
+}
+
+type twoFactorService struct {
+	users         repository.UserRepository
+	recoveryCodes repository.RecoveryCodeRepository
+	settings      repository.SettingRepository
+	loginAttempts repository.LoginAttemptRepository
+}
+
+func NewTwoFactorService(pool *pgxpool.Pool) TwoFactorService {
+	return &twoFactorService{
+		users:         repository.NewUserRepository(pool),
+		recoveryCodes: repository.NewRecoveryCodeRepository(pool),
+		settings:      repository.NewSettingRepository(pool),
+		loginAttempts: repository.NewLoginAttemptRepository(pool),
+	}
+}
+
+func invalidCodeError() error {
+	return responses.NewValidationError("Validation failed", []string{"code"}, []*responses.ViolationsDetail{
+		{Name: "code", Message: "That code is not valid"},
+	})
+}
+
+func (s *twoFactorService) IsRequired(ctx context.Context, user *domain.User) (bool, error) {
+	if !user.IsAdmin() {
+		return false, nil
+	}
+	return requiresAdminTwoFactor(ctx, s.settings)
+}
+
+// BeginEnrollment returns the secret to add to an authenticator app. The
+// secret of an unconfirmed enrollment is reused so reloading the page does
+// not invalidate a code that was already scanned.
+func (s *twoFactorService) BeginEnrollment(ctx context.Context, user *domain.User) (*TwoFactorEnrollment, error) {
+	user, err := s.users.GetUserByID(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if user.HasTwoFactor() {
+		return nil, responses.NewConflictError("Two-factor authentication is already enabled")
+	}
+
+	secret := user.TOTPSecret.String
+	if !user.TOTPSecret.Valid {
+		secret, err = totp.GenerateSecret()
+		if err != nil {
+			return nil, err
+		}
+		if err := s.users.SetTOTPSecret(ctx, user.ID, secret); err != nil {
+			return nil, err
+		}
+	}
+
+	return &TwoFactorEnrollment{
+		Secret: secret,
+		URI:    totp.ProvisioningURI(TwoFactorIssuer, user.Email, secret),
+	}, nil
+}
+
+// ConfirmEnrollment enables two-factor authentication once the user proves
+// their app produces valid codes, and returns the plaintext recovery codes.
+// They are only ever shown this once.
+func (s *twoFactorService) ConfirmEnrollment(ctx context.Context, user *domain.User, code string) ([]string, error) {
+	user, err := s.users.GetUserByID(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if user.HasTwoFactor() {
+		return nil, responses.NewConflictError("Two-factor authentication is already enabled")
+	}
+	if !user.TOTPSecret.Valid {
+		return nil, responses.NewConflictError("Start the enrollment again")
+	}
+
+	counter, ok := totp.Validate(user.TOTPSecret.String, code, time.Now())
+	if !ok {
+		return nil, invalidCodeError()
+	}
+
+	if err := s.users.EnableTOTP(ctx, user.ID, counter); err != nil {
+		return nil, err
+	}
+
+	codes, err := s.replaceRecoveryCodes(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	logging.FromContext(ctx).Info("two-factor authentication enabled", "user", user)
+	return codes, nil
+}
+
+// Verify completes a login for a user that already passed the password
+// check. Failures count towards the account lockout like wrong passwords.
+func (s *twoFactorService) Verify(ctx context.Context, userID int64, code, ipAddress string) (*domain.User, error) {
+	logger := logging.FromContext(ctx)
+	now := time.Now()
+
+	user, err := s.users.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.IsLocked(now) {
+		logger.Info("authentication failed", "reason", domain.LoginReasonLocked, "user", user)
+		recordLoginAttempt(ctx, s.loginAttempts, user.Email, user, ipAddress, domain.LoginReasonLocked)
+		return nil, responses.NewUnauthorizedError("Invalid credentials")
+	}
+
+	ok, err := s.checkCode(ctx, user, code, now)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		logger.Info("authentication failed", "reason", domain.LoginReasonWrongSecondFactor, "user", user)
+		recordLoginAttempt(ctx, s.loginAttempts, user.Email, user, ipAddress, domain.LoginReasonWrongSecondFactor)
+		if err := registerLoginFailure(ctx, s.users, user, now); err != nil {
+			return nil, err
+		}
+		return nil, invalidCodeError()
+	}
+
+	if user.FailedLoginCount > 0 || user.LockedUntil.Valid {
+		if err := s.users.ResetFailedLogins(ctx, user.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	return user, nil
+}
+
+func (s *twoFactorService) RegenerateRecoveryCodes(ctx context.Context, user *domain.User, code string) ([]string, error) {
+	user, err := s.requireCode(ctx, user, code)
+	if err != nil {
+		return nil, err
+	}
+
+	codes, err := s.replaceRecoveryCodes(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	logging.FromContext(ctx).Info("recovery codes regenerated", "user", user)
+	return codes, nil
+}
+
+func (s *twoFactorService) Disable(ctx context.Context, user *domain.User, code string) error {
+	required, err := s.IsRequired(ctx, user)
+	if err != nil {
+		return err
+	}
+	if required {
+		return responses.NewForbiddenError("Administrators have to keep two-factor authentication enabled")
+	}
+
+	user, err = s.requireCode(ctx, user, code)
+	if err != nil {
+		return err
+	}
+
+	if err := s.users.DisableTOTP(ctx, user.ID); err != nil {
+		return err
+	}
+	if err := s.recoveryCodes.DeleteByUserID(ctx, user.ID); err != nil {
+		return err
+	}
+
+	logging.FromContext(ctx).Info("two-factor authentication disabled", "user", user)
+	return nil
+}
+
+func (s *twoFactorService) RemainingRecoveryCodes(ctx context.Context, user *domain.User) (int, error) {
+	return s.recoveryCodes.CountUnused(ctx, user.ID)
+}
+
+// requireCode reloads user and checks that code is valid for them.
+func (s *twoFactorService) requireCode(ctx context.Context, user *domain.User, code string) (*domain.User, error) {
+	user, err := s.users.GetUserByID(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.HasTwoFactor() {
+		return nil, responses.NewConflictError("Two-factor authentication is not enabled")
+	}
+
+	ok, err := s.checkCode(ctx, user, code, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, invalidCodeError()
+	}
+	return user, nil
+}
+
+// checkCode accepts a code from the authenticator app, each at most once,
+// or an unused recovery code.
+func (s *twoFactorService) checkCode(ctx context.Context, user *domain.User, code string, now time.Time) (bool, error) {
+	if !user.HasTwoFactor() {
+		return false, nil
+	}
+
+	if counter, ok := totp.Validate(user.TOTPSecret.String, code, now); ok {
+		return s.users.UseTOTPCounter(ctx, user.ID, counter)
+	}
+
+	normalized := normalizeRecoveryCode(code)
+	if len(normalized) != recoveryCodeLength {
+		return false, nil
+	}
+
+	used, err := s.recoveryCodes.Consume(ctx, user.ID, hashing.HashToken(normalized))
+	if err != nil {
+		return false, err
+	}
+	if used {
+		logging.FromContext(ctx).Warn("recovery code used", "user", user)
+	}
+	return used, nil
+}
+
+func (s *twoFactorService) replaceRecoveryCodes(ctx context.Context, userID int64) ([]string, error) {
+	codes := make([]string, domain.RecoveryCodeCount)
+	hashes := make([]string, domain.RecoveryCodeCount)
+	for i := range codes {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		codes[i] = code
+		hashes[i] = hashing.HashToken(normalizeRecoveryCode(code))
+	}
+
+	if err := s.recoveryCodes.Replace(ctx, userID, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+const recoveryCodeLength = 10
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// generateRecoveryCode returns 50 random bits formatted as xxxxx-xxxxx.
+func generateRecoveryCode() (string, error) {
+	b := make([]byte, 7)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	code := strings.ToLower(recoveryCodeEncoding.EncodeToString(b))[:recoveryCodeLength]
+	return code[:5] + "-" + code[5:], nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
@@ -2,52 +2,231 @@ package service
 
 import (
 	"context"
-	"errors"
+	"database/sql"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/mjmarrazzo/maintenance-app/domain"
 	"github.com/mjmarrazzo/maintenance-app/internal/hashing"
+	"github.com/mjmarrazzo/maintenance-app/internal/logging"
+	"github.com/mjmarrazzo/maintenance-app/internal/responses"
 	"github.com/mjmarrazzo/maintenance-app/repository"
 )
 
 type UserService interface {
-	Create(ctx context.Context, user *domain.UserRequest) error
-	Authenticate(ctx context.Context, email, password string) (*domain.User, error)
+	Create(ctx context.Context, user *domain.UserRequest) (*domain.User, error)
+	Authenticate(ctx context.Context, email, password, ipAddress string) (*domain.User, error)
+	GetAll(ctx context.Context) ([]*domain.User, error)
+	GetByID(ctx context.Context, id int64) (*domain.User, error)
+	GetLocked(ctx context.Context) ([]*domain.User, error)
+	GetRecentFailedLogins(ctx context.Context) ([]*domain.LoginAttempt, error)
+	Unlock(ctx context.Context, id int64) error
+	UpdatePreferences(ctx context.Context, id int64, request *domain.PreferencesRequest) error
 }
 
+const recentFailedLoginsLimit = 50
+
 type userService struct {
-	repo repository.UserRepository
+	repo          repository.UserRepository
+	settings      repository.SettingRepository
+	invitations   repository.InvitationRepository
+	loginAttempts repository.LoginAttemptRepository
 }
 
 func NewUserService(pool *pgxpool.Pool) UserService {
-	return &userService{repo: repository.NewUserRepository(pool)}
+	return &userService{
+		repo:          repository.NewUserRepository(pool),
+		settings:      repository.NewSettingRepository(pool),
+		invitations:   repository.NewInvitationRepository(pool),
+		loginAttempts: repository.NewLoginAttemptRepository(pool),
+	}
 }
 
-func (s *userService) Create(ctx context.Context, userRequest *domain.UserRequest) error {
+// Create registers a user according to the registration mode. Users joining
+// through an invitation get the invited role and, since the link reached
+// their inbox, a verified email address.
+func (s *userService) Create(ctx context.Context, userRequest *domain.UserRequest) (*domain.User, error) {
+	mode, err := registrationMode(ctx, s.settings)
+	if err != nil {
+		return nil, err
+	}
+	if mode == domain.RegistrationClosed {
+		return nil, responses.NewForbiddenError("Registration is closed")
+	}
+
 	user := userRequest.ToDomain()
 
+	var invitation *domain.Invitation
+	if userRequest.InvitationToken != "" {
+		invitation, err = findPendingInvitation(ctx, s.invitations, userRequest.InvitationToken)
+		if err != nil {
+			return nil, err
+		}
+		if !invitation.Matches(userRequest.Email) {
+			return nil, responses.NewValidationError("Validation failed", []string{"email"}, []*responses.ViolationsDetail{
+				{Name: "email", Message: "Use the address the invitation was sent to"},
+			})
+		}
+		user.Role = invitation.Role
+		user.EmailVerifiedAt.Time = time.Now()
+		user.EmailVerifiedAt.Valid = true
+	} else if mode == domain.RegistrationInviteOnly {
+		return nil, responses.NewForbiddenError("Registration is by invitation only")
+	}
+
 	passwordHash, err := hashing.HashPassword(userRequest.Password)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	user.PasswordHash = passwordHash
 
-	if err := s.repo.CreateUser(ctx, user); err != nil {
-		return err
+	if err := createUser(ctx, s.repo, s.invitations, invitation, user); err != nil {
+		return nil, err
 	}
-	return nil
+
+	logging.FromContext(ctx).Info("user registered", "user", user, "invited", invitation != nil)
+	return user, nil
 }
 
-func (s *userService) Authenticate(ctx context.Context, email, password string) (*domain.User, error) {
+// Authenticate checks credentials while rate limiting by IP address and
+// locking accounts after repeated failures. Unknown emails, wrong passwords
+// and locked accounts all get the same response.
+func (s *userService) Authenticate(ctx context.Context, email, password, ipAddress string) (*domain.User, error) {
+	logger := logging.FromContext(ctx)
+	invalid := responses.NewUnauthorizedError("Invalid credentials")
+	now := time.Now()
+
+	failures, latest, err := s.loginAttempts.CountFailuresByIP(ctx, ipAddress, now.Add(-domain.IPFailureWindow))
+	if err != nil {
+		return nil, err
+	}
+	wait := domain.Backoff(failures, domain.IPFailureThreshold, domain.IPBackoffBase, domain.IPBackoffMax)
+	if wait > 0 && now.Before(latest.Add(wait)) {
+		logger.Warn("login rate limited", "ip_address", ipAddress, "failures", failures)
+		recordLoginAttempt(ctx, s.loginAttempts, email, nil, ipAddress, domain.LoginReasonRateLimited)
+		return nil, responses.NewTooManyRequestsError("Too many login attempts. Please try again later.")
+	}
+
 	user, err := s.repo.GetUserByEmail(ctx, email)
+	if appErr, ok := responses.IsAppError(err); ok && appErr.Kind == responses.KindNotFound {
+		hashing.SimulatePasswordCheck(password)
+		logger.Info("authentication failed", "reason", domain.LoginReasonUnknownEmail)
+		recordLoginAttempt(ctx, s.loginAttempts, email, nil, ipAddress, domain.LoginReasonUnknownEmail)
+		return nil, invalid
+	}
 	if err != nil {
-		return nil, errors.New("invalid credentials")
+		return nil, err
+	}
+
+	if user.IsLocked(now) {
+		logger.Info("authentication failed", "reason", domain.LoginReasonLocked, "user", user)
+		recordLoginAttempt(ctx, s.loginAttempts, email, user, ipAddress, domain.LoginReasonLocked)
+		return nil, invalid
 	}
 
 	if ok := hashing.VerifyPassword(password, user.PasswordHash); !ok {
-		return nil, errors.New("invalid credentials")
+		logger.Info("authentication failed", "reason", domain.LoginReasonWrongPassword, "user", user)
+		recordLoginAttempt(ctx, s.loginAttempts, email, user, ipAddress, domain.LoginReasonWrongPassword)
+		if err := registerLoginFailure(ctx, s.repo, user, now); err != nil {
+			return nil, err
+		}
+		return nil, invalid
+	}
+
+	// With two-factor enabled the password alone is not a successful login,
+	// so failures only reset once the second factor has been verified.
+	if !user.HasTwoFactor() && (user.FailedLoginCount > 0 || user.LockedUntil.Valid) {
+		if err := s.repo.ResetFailedLogins(ctx, user.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	if !user.IsEmailVerified() {
+		logger.Info("authentication failed", "reason", domain.LoginReasonEmailNotVerified, "user", user)
+		recordLoginAttempt(ctx, s.loginAttempts, email, user, ipAddress, domain.LoginReasonEmailNotVerified)
+		return nil, responses.NewForbiddenError("Please verify your email address before signing in")
 	}
 
+	recordLoginAttempt(ctx, s.loginAttempts, email, user, ipAddress, "")
 	return user, nil
 }
+
+// registerLoginFailure counts a failed password or second factor against the
+// account and locks it once the lockout threshold is reached.
+func registerLoginFailure(ctx context.Context, users repository.UserRepository, user *domain.User, now time.Time) error {
+	failures, err := users.IncrementFailedLogins(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	lockFor := domain.Backoff(failures, domain.AccountLockoutThreshold, domain.AccountLockoutBase, domain.AccountLockoutMax)
+	if lockFor == 0 {
+		return nil
+	}
+
+	if err := users.Lock(ctx, user.ID, now.Add(lockFor)); err != nil {
+		return err
+	}
+
+	logging.FromContext(ctx).Warn("account locked", "user", user, "failures", failures, "duration", lockFor)
+	return nil
+}
+
+// recordLoginAttempt writes the audit row. A failure to record is logged
+// rather than failing the login.
+func recordLoginAttempt(ctx context.Context, attempts repository.LoginAttemptRepository, email string, user *domain.User, ipAddress, reason string) {
+	attempt := &domain.LoginAttempt{
+		Email:     email,
+		IPAddress: ipAddress,
+		Succeeded: reason == "",
+		Reason:    reason,
+	}
+	if user != nil {
+		attempt.UserID.Int64 = user.ID
+		attempt.UserID.Valid = true
+	}
+
+	if err := attempts.Create(ctx, attempt); err != nil {
+		logging.FromContext(ctx).Error("failed to record login attempt", "error", err)
+	}
+}
+
+func (s *userService) GetLocked(ctx context.Context) ([]*domain.User, error) {
+	return s.repo.GetLocked(ctx)
+}
+
+func (s *userService) GetRecentFailedLogins(ctx context.Context) ([]*domain.LoginAttempt, error) {
+	return s.loginAttempts.GetRecentFailures(ctx, recentFailedLoginsLimit)
+}
+
+func (s *userService) Unlock(ctx context.Context, id int64) error {
+	if err := s.repo.ResetFailedLogins(ctx, id); err != nil {
+		return err
+	}
+
+	logging.FromContext(ctx).Info("account unlocked", "unlocked_user_id", id)
+	return nil
+}
+
+// UpdatePreferences stores the language and time zone of the user. An
+// empty locale goes back to the language of the browser, an empty time zone
+// to the one of the organization.
+func (s *userService) UpdatePreferences(ctx context.Context, id int64, request *domain.PreferencesRequest) error {
+	locale := sql.NullString{String: request.Locale, Valid: request.Locale != ""}
+	timeZone := sql.NullString{String: request.TimeZone, Valid: request.TimeZone != ""}
+	if err := s.repo.UpdatePreferences(ctx, id, locale, timeZone); err != nil {
+		return err
+	}
+
+	logging.FromContext(ctx).Info("preferences updated", "locale", request.Locale, "time_zone", request.TimeZone)
+	return nil
+}
+
+func (s *userService) GetAll(ctx context.Context) ([]*domain.User, error) {
+	return s.repo.GetAll(ctx)
+}
+
+func (s *userService) GetByID(ctx context.Context, id int64) (*domain.User, error) {
+	return s.repo.GetUserByID(ctx, id)
+}
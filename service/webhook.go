@@ -0,0 +1,245 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mjmarrazzo/maintenance-app/domain"
+	"github.com/mjmarrazzo/maintenance-app/internal/hashing"
+	"github.com/mjmarrazzo/maintenance-app/internal/logging"
+	"github.com/mjmarrazzo/maintenance-app/internal/webhook"
+	"github.com/mjmarrazzo/maintenance-app/repository"
+)
+
+const (
+	webhookDeliveryLogLimit = 50
+	webhookBatchSize        = 20
+	webhookTimeout          = 10 * time.Second
+)
+
+type WebhookService interface {
+	GetAll(ctx context.Context) ([]*domain.Webhook, error)
+	GetByID(ctx context.Context, id int64) (*domain.Webhook, error)
+	Create(ctx context.Context, creator *domain.User, request *domain.WebhookRequest) (*domain.Webhook, error)
+	Update(ctx context.Context, id int64, request *domain.WebhookRequest) error
+	Delete(ctx context.Context, id int64) error
+	GetDeliveries(ctx context.Context, webhookID int64) ([]*domain.WebhookDelivery, error)
+	// SendTest sends a webhook.test event right away and returns the
+	// delivery with its outcome. Test events are not retried.
+	SendTest(ctx context.Context, actor *domain.User, id int64) (*domain.WebhookDelivery, error)
+	HandleTaskEvent(ctx context.Context, event domain.TaskEvent)
+	ProcessDeliveries(ctx context.Context, now time.Time) error
+}
+
+type webhookService struct {
+	repository repository.WebhookRepository
+	deliveries repository.WebhookDeliveryRepository
+	users      repository.UserRepository
+	client     *webhook.Client
+	baseURL    string
+}
+
+func NewWebhookService(pool *pgxpool.Pool, baseURL string) WebhookService {
+	return &webhookService{
+		repository: repository.NewWebhookRepository(pool),
+		deliveries: repository.NewWebhookDeliveryRepository(pool),
+		users:      repository.NewUserRepository(pool),
+		client:     webhook.NewClient(webhookTimeout),
+		baseURL:    baseURL,
+	}
+}
+
+func (s *webhookService) GetAll(ctx context.Context) ([]*domain.Webhook, error) {
+	return s.repository.GetAll(ctx)
+}
+
+func (s *webhookService) GetByID(ctx context.Context, id int64) (*domain.Webhook, error) {
+	return s.repository.GetByID(ctx, id)
+}
+
+func (s *webhookService) Create(ctx context.Context, creator *domain.User, request *domain.WebhookRequest) (*domain.Webhook, error) {
+	hook := request.ToDomain()
+	hook.CreatedBy = sql.NullInt64{Int64: creator.ID, Valid: true}
+
+	if hook.Secret == "" {
+		secret, err := hashing.GenerateSecret()
+		if err != nil {
+			return nil, err
+		}
+		hook.Secret = secret
+	}
+
+	if err := s.repository.Create(ctx, hook); err != nil {
+		return nil, err
+	}
+
+	logging.FromContext(ctx).Info("webhook created", "webhook_id", hook.ID, "user_id", creator.ID)
+	return hook, nil
+}
+
+func (s *webhookService) Update(ctx context.Context, id int64, request *domain.WebhookRequest) error {
+	current, err := s.repository.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	hook := request.ToDomain()
+	hook.ID = id
+	if hook.Secret == "" {
+		hook.Secret = current.Secret
+	}
+
+	if err := s.repository.Update(ctx, hook); err != nil {
+		return err
+	}
+
+	logging.FromContext(ctx).Info("webhook updated", "webhook_id", id)
+	return nil
+}
+
+func (s *webhookService) Delete(ctx context.Context, id int64) error {
+	if err := s.repository.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	logging.FromContext(ctx).Info("webhook deleted", "webhook_id", id)
+	return nil
+}
+
+func (s *webhookService) GetDeliveries(ctx context.Context, webhookID int64) ([]*domain.WebhookDelivery, error) {
+	return s.deliveries.GetByWebhookID(ctx, webhookID, webhookDeliveryLogLimit)
+}
+
+// HandleTaskEvent queues a delivery of event for every webhook subscribed
+// to it. It is subscribed to events.Default at startup; the deliveries are
+// sent by ProcessDeliveries.
+func (s *webhookService) HandleTaskEvent(ctx context.Context, event domain.TaskEvent) {
+	logger := logging.FromContext(ctx)
+
+	hooks, err := s.repository.GetSubscribed(ctx, event.Kind)
+	if err != nil {
+		logger.Error("failed to load webhooks", "kind", event.Kind, "error", err)
+		return
+	}
+	if len(hooks) == 0 {
+		return
+	}
+
+	var actor *domain.User
+	if event.ActorID != 0 {
+		if actor, err = s.users.GetUserByID(ctx, event.ActorID); err != nil {
+			logger.Warn("failed to load webhook actor", "user_id", event.ActorID, "error", err)
+		}
+	}
+
+	payload, err := json.Marshal(webhook.NewPayload(event, actor, s.baseURL+"/tasks"))
+	if err != nil {
+		logger.Error("failed to encode webhook payload", "kind", event.Kind, "error", err)
+		return
+	}
+
+	for _, hook := range hooks {
+		delivery := &domain.WebhookDelivery{WebhookID: hook.ID, Event: event.Kind, Payload: payload}
+		if err := s.deliveries.Create(ctx, delivery); err != nil {
+			logger.Error("failed to queue webhook delivery", "webhook_id", hook.ID, "kind", event.Kind, "error", err)
+		}
+	}
+}
+
+func (s *webhookService) SendTest(ctx context.Context, actor *domain.User, id int64) (*domain.WebhookDelivery, error) {
+	hook, err := s.repository.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	payload, err := json.Marshal(webhook.NewPayload(domain.TaskEvent{
+		Kind:       domain.WebhookTestEvent,
+		ActorID:    actor.ID,
+		OccurredAt: now,
+	}, actor, s.baseURL+"/tasks"))
+	if err != nil {
+		return nil, err
+	}
+
+	delivery := &domain.WebhookDelivery{WebhookID: hook.ID, Event: domain.WebhookTestEvent, Payload: payload}
+	if err := s.deliveries.Create(ctx, delivery); err != nil {
+		return nil, err
+	}
+
+	if err := s.send(ctx, hook, delivery, now); err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// ProcessDeliveries sends the deliveries that are due. Failed deliveries
+// are retried with exponential backoff until domain.WebhookMaxAttempts.
+func (s *webhookService) ProcessDeliveries(ctx context.Context, now time.Time) error {
+	deliveries, err := s.deliveries.ClaimDue(ctx, now, webhookBatchSize)
+	if err != nil {
+		return err
+	}
+
+	hooks := map[int64]*domain.Webhook{}
+	for _, delivery := range deliveries {
+		hook, ok := hooks[delivery.WebhookID]
+		if !ok {
+			if hook, err = s.repository.GetByID(ctx, delivery.WebhookID); err != nil {
+				return err
+			}
+			hooks[delivery.WebhookID] = hook
+		}
+
+		if err := s.send(ctx, hook, delivery, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// send makes one attempt at delivery and records its outcome.
+func (s *webhookService) send(ctx context.Context, hook *domain.Webhook, delivery *domain.WebhookDelivery, now time.Time) error {
+	logger := logging.FromContext(ctx)
+
+	if !hook.IsActive && delivery.Event != domain.WebhookTestEvent {
+		delivery.FailedAt = sql.NullTime{Time: now, Valid: true}
+		delivery.LastError = sql.NullString{String: "Webhook is disabled", Valid: true}
+		return s.deliveries.RecordAttempt(ctx, delivery)
+	}
+
+	resp, sendErr := s.client.Send(ctx, webhook.Request{
+		URL:        hook.URL,
+		Secret:     hook.Secret,
+		Event:      string(delivery.Event),
+		DeliveryID: delivery.ID,
+		Body:       delivery.Payload,
+	})
+
+	delivery.Attempts++
+	delivery.ResponseStatus = sql.NullInt32{}
+	delivery.ResponseBody = sql.NullString{}
+	if resp != nil {
+		delivery.ResponseStatus = sql.NullInt32{Int32: int32(resp.StatusCode), Valid: true}
+		delivery.ResponseBody = sql.NullString{String: resp.Body, Valid: true}
+	}
+
+	switch {
+	case sendErr == nil:
+		delivery.DeliveredAt = sql.NullTime{Time: time.Now(), Valid: true}
+		delivery.LastError = sql.NullString{}
+	case delivery.Event == domain.WebhookTestEvent || delivery.Attempts >= domain.WebhookMaxAttempts:
+		logger.Warn("giving up on webhook delivery", "webhook_id", hook.ID, "delivery_id", delivery.ID, "attempts", delivery.Attempts, "error", sendErr)
+		delivery.FailedAt = sql.NullTime{Time: time.Now(), Valid: true}
+		delivery.LastError = sql.NullString{String: sendErr.Error(), Valid: true}
+	default:
+		delivery.NextAttemptAt = now.Add(domain.Backoff(delivery.Attempts, 1, domain.WebhookRetryBase, domain.WebhookRetryMax))
+		delivery.LastError = sql.NullString{String: sendErr.Error(), Valid: true}
+		logger.Info("webhook delivery failed", "webhook_id", hook.ID, "delivery_id", delivery.ID, "attempts", delivery.Attempts, "retry_at", delivery.NextAttemptAt, "error", sendErr)
+	}
+
+	return s.deliveries.RecordAttempt(ctx, delivery)
+}